@@ -0,0 +1,31 @@
+// Command bench drives synthetic create/list/get/upload/download traffic
+// against a running gophkeeper server and reports latency percentiles, to
+// validate the pgxpool and caching work under load.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	root := &cobra.Command{
+		Use:   "bench",
+		Short: "Load-test a gophkeeper server",
+		RunE:  runBench,
+	}
+
+	root.Flags().String("target", "http://localhost:8080", "base URL of the server under test")
+	root.Flags().Int("users", 10, "number of synthetic users to register and drive traffic as")
+	root.Flags().Duration("duration", 0, "how long to run (0 = one pass per user)")
+	root.Flags().Int("concurrency", 10, "number of users driving traffic concurrently")
+	root.Flags().StringToInt("mix", map[string]int{"create": 1, "list": 1, "get": 1, "upload": 1, "download": 1},
+		"relative weight of each operation in the traffic mix")
+
+	if err := root.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/andymarkow/gophkeeper/pkg/client"
+)
+
+// jsonBody JSON-encodes v into a request body reader.
+func jsonBody(v any) (io.Reader, error) {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(buf), nil
+}
+
+// syntheticUser is one simulated account driving traffic for the duration
+// of the run.
+type syntheticUser struct {
+	login    string
+	password string
+	target   string
+	client   *client.Client
+}
+
+func runBench(cmd *cobra.Command, _ []string) error {
+	target, _ := cmd.Flags().GetString("target")
+	numUsers, _ := cmd.Flags().GetInt("users")
+	duration, _ := cmd.Flags().GetDuration("duration")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	mix, _ := cmd.Flags().GetStringToInt("mix")
+
+	ctx := cmd.Context()
+
+	users, err := registerUsers(ctx, target, numUsers)
+	if err != nil {
+		return fmt.Errorf("register synthetic users: %w", err)
+	}
+
+	rec := newRecorder()
+	ops := weightedOps(mix)
+
+	deadline := time.Now().Add(duration)
+
+	g, ctx := errgroup.WithContext(ctx)
+	sem := make(chan struct{}, concurrency)
+
+	for _, u := range users {
+		u := u
+
+		g.Go(func() error {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			for {
+				for _, op := range ops {
+					runOp(ctx, u, op, rec)
+				}
+
+				if duration == 0 || time.Now().After(deadline) {
+					return nil
+				}
+			}
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	printReport(cmd, rec)
+
+	return nil
+}
+
+// registerUsers creates numUsers synthetic accounts against target and
+// returns a client for each. It does not log in, so the clients carry no
+// token and the traffic they drive is sent unauthenticated.
+func registerUsers(ctx context.Context, target string, numUsers int) ([]syntheticUser, error) {
+	users := make([]syntheticUser, 0, numUsers)
+
+	for i := 0; i < numUsers; i++ {
+		login, err := randomToken()
+		if err != nil {
+			return nil, err
+		}
+
+		password, err := randomToken()
+		if err != nil {
+			return nil, err
+		}
+
+		c := client.New(target)
+
+		body, _ := jsonBody(map[string]string{"login": login, "password": password})
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, target+"/api/v1/auth/register", body)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		if _, err := c.Do(ctx, req); err != nil {
+			return nil, fmt.Errorf("register user %q: %w", login, err)
+		}
+
+		users = append(users, syntheticUser{login: "bench-" + login, password: password, target: target, client: c})
+	}
+
+	return users, nil
+}
+
+// weightedOps expands mix into a flat slice where each op appears in
+// proportion to its weight, so a single pass over it reproduces the
+// configured traffic ratio.
+func weightedOps(mix map[string]int) []string {
+	ops := make([]string, 0)
+	for op, weight := range mix {
+		for i := 0; i < weight; i++ {
+			ops = append(ops, op)
+		}
+	}
+
+	return ops
+}
+
+func runOp(ctx context.Context, u syntheticUser, op string, rec *recorder) {
+	start := time.Now()
+
+	var (
+		req *http.Request
+		err error
+	)
+
+	switch op {
+	case "create":
+		body, _ := jsonBody(map[string]string{"name": "bench-secret", "login": "x", "password": "y"})
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, u.target+"/api/v1/credentials", body)
+	case "list":
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, u.target+"/api/v1/credentials", nil)
+	case "get":
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, u.target+"/api/v1/credentials/bench-secret", nil)
+	case "upload":
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, u.target+"/api/v1/files", bytes.NewReader(make([]byte, 1<<16)))
+	case "download":
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, u.target+"/api/v1/files/bench-file", nil)
+	default:
+		return
+	}
+	if err != nil {
+		rec.record(op, 0, err)
+
+		return
+	}
+
+	_, err = u.client.Do(ctx, req)
+	rec.record(op, time.Since(start), err)
+}
+
+func printReport(cmd *cobra.Command, rec *recorder) {
+	tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "OP\tCOUNT\tERRORS\tP50\tP95\tP99")
+
+	for _, s := range rec.summaries() {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%s\n", s.Op, s.Count, s.Errors, s.P50, s.P95, s.P99)
+	}
+
+	_ = tw.Flush()
+}
+
+func randomToken() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
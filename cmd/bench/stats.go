@@ -0,0 +1,91 @@
+package main
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// recorder collects operation latencies concurrently and reports
+// percentiles once the run finishes.
+type recorder struct {
+	mu      sync.Mutex
+	samples map[string][]time.Duration
+	errors  map[string]int
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		samples: make(map[string][]time.Duration),
+		errors:  make(map[string]int),
+	}
+}
+
+func (r *recorder) record(op string, d time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err != nil {
+		r.errors[op]++
+
+		return
+	}
+
+	r.samples[op] = append(r.samples[op], d)
+}
+
+// summary is the per-operation latency report.
+type summary struct {
+	Op     string
+	Count  int
+	Errors int
+	P50    time.Duration
+	P95    time.Duration
+	P99    time.Duration
+}
+
+func (r *recorder) summaries() []summary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ops := make([]string, 0, len(r.samples))
+	for op := range r.samples {
+		ops = append(ops, op)
+	}
+	for op := range r.errors {
+		if _, ok := r.samples[op]; !ok {
+			ops = append(ops, op)
+		}
+	}
+	sort.Strings(ops)
+
+	out := make([]summary, 0, len(ops))
+	for _, op := range ops {
+		durations := append([]time.Duration(nil), r.samples[op]...)
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+		out = append(out, summary{
+			Op:     op,
+			Count:  len(durations),
+			Errors: r.errors[op],
+			P50:    percentile(durations, 0.50),
+			P95:    percentile(durations, 0.95),
+			P99:    percentile(durations, 0.99),
+		})
+	}
+
+	return out
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	idx := int(p * float64(len(sorted)))
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+
+	return sorted[idx]
+}
@@ -0,0 +1,23 @@
+// Command client is the gophkeeper CLI client.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	root := &cobra.Command{
+		Use:   "gophkeeper",
+		Short: "gophkeeper CLI client",
+	}
+
+	root.AddCommand(newServeBridgeCommand())
+
+	if err := root.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/andymarkow/gophkeeper/internal/client/bridge"
+)
+
+// newServeBridgeCommand returns the `serve-bridge` command, which exposes a
+// loopback-only API for a browser extension to query and fill saved
+// credentials, never binding to a non-loopback address.
+func newServeBridgeCommand() *cobra.Command {
+	var (
+		port           int
+		allowedOrigins []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "serve-bridge",
+		Short: "Run the local HTTP bridge for browser extensions",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			allowed := make(bridge.AllowedOrigins, len(allowedOrigins))
+			for _, o := range allowedOrigins {
+				allowed[o] = true
+			}
+
+			srv := bridge.NewServer(nil, promptConfirmer{}, allowed)
+
+			addr := fmt.Sprintf("127.0.0.1:%d", port)
+
+			ln, err := net.Listen("tcp", addr)
+			if err != nil {
+				return fmt.Errorf("listen on %s: %w", addr, err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "bridge listening on %s\n", addr)
+
+			return http.Serve(ln, srv.Handler())
+		},
+	}
+
+	cmd.Flags().IntVar(&port, "port", 9696, "loopback port to listen on")
+	cmd.Flags().StringSliceVar(&allowedOrigins, "allow-origin", nil,
+		"browser extension origin allowed to call the bridge (repeatable)")
+
+	return cmd
+}
+
+// promptConfirmer asks the user on the controlling terminal before filling
+// a credential into a page the extension requested.
+type promptConfirmer struct{}
+
+func (promptConfirmer) Confirm(credentialID, siteURL string) bool {
+	fmt.Printf("Allow filling credential %s into %s? [y/N] ", credentialID, siteURL)
+
+	var answer string
+	_, _ = fmt.Scanln(&answer)
+
+	return answer == "y" || answer == "Y"
+}
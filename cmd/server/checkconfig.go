@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/andymarkow/gophkeeper/internal/config"
+)
+
+const maskedValue = "********"
+
+// newCheckConfigCommand returns the `check-config` command, which loads
+// configuration from every source exactly as the server would, and prints
+// the effective values with secrets masked, so operators can debug which
+// env/flag/file value won without exposing credentials in a terminal or CI
+// log.
+func newCheckConfigCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "check-config",
+		Short: "Print the effective configuration with secrets masked",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := config.Load(configPath)
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+
+			masked := *cfg
+			if masked.S3.SecretKey != "" {
+				masked.S3.SecretKey = maskedValue
+			}
+			if masked.S3.AccessKey != "" {
+				masked.S3.AccessKey = maskedValue
+			}
+			if masked.Auth.JWTSecret != "" {
+				masked.Auth.JWTSecret = maskedValue
+			}
+			if masked.DB.DSN != "" {
+				masked.DB.DSN = maskedValue
+			}
+			if masked.Telegram.BotToken != "" {
+				masked.Telegram.BotToken = maskedValue
+			}
+
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+
+			return enc.Encode(masked)
+		},
+	}
+}
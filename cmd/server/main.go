@@ -0,0 +1,32 @@
+// Command server runs the gophkeeper API server.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var configPath string
+
+func main() {
+	root := &cobra.Command{
+		Use:   "gophkeeper-server",
+		Short: "gophkeeper API server",
+	}
+
+	root.PersistentFlags().StringVar(&configPath, "config", "", "path to a config file (yaml/toml)")
+
+	root.AddCommand(newCheckConfigCommand())
+	root.AddCommand(newTelegramBotCommand())
+	root.AddCommand(newIntegrityAuditCommand())
+	root.AddCommand(newFsckCommand())
+	root.AddCommand(newBackupCommand())
+	root.AddCommand(newServeCommand())
+
+	if err := root.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
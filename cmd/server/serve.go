@@ -0,0 +1,486 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/spf13/cobra"
+
+	"github.com/andymarkow/gophkeeper/internal/api"
+	"github.com/andymarkow/gophkeeper/internal/api/handlers"
+	"github.com/andymarkow/gophkeeper/internal/api/middlewares"
+	"github.com/andymarkow/gophkeeper/internal/audit"
+	"github.com/andymarkow/gophkeeper/internal/bwlimit"
+	"github.com/andymarkow/gophkeeper/internal/config"
+	"github.com/andymarkow/gophkeeper/internal/cryptutils"
+	"github.com/andymarkow/gophkeeper/internal/notify"
+	"github.com/andymarkow/gophkeeper/internal/objrepo"
+	"github.com/andymarkow/gophkeeper/internal/ratelimit"
+	"github.com/andymarkow/gophkeeper/internal/repository/apitokenrepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/bankcardrepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/credrepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/deadletterrepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/filerepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/genericrepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/otprepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/relationrepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/reminderrepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/searchindexrepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/telegramrepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/textrepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/userrepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/wifirepo"
+	"github.com/andymarkow/gophkeeper/internal/service/apitokensvc"
+	"github.com/andymarkow/gophkeeper/internal/service/authsvc"
+	"github.com/andymarkow/gophkeeper/internal/service/bankcardsvc"
+	"github.com/andymarkow/gophkeeper/internal/service/credentialsvc"
+	"github.com/andymarkow/gophkeeper/internal/service/exportsvc"
+	"github.com/andymarkow/gophkeeper/internal/service/filesvc"
+	"github.com/andymarkow/gophkeeper/internal/service/genericsvc"
+	"github.com/andymarkow/gophkeeper/internal/service/notifysvc"
+	"github.com/andymarkow/gophkeeper/internal/service/otpsvc"
+	"github.com/andymarkow/gophkeeper/internal/service/relationsvc"
+	"github.com/andymarkow/gophkeeper/internal/service/remindersvc"
+	"github.com/andymarkow/gophkeeper/internal/service/retentionsvc"
+	"github.com/andymarkow/gophkeeper/internal/service/textsvc"
+	"github.com/andymarkow/gophkeeper/internal/service/trashsvc"
+	"github.com/andymarkow/gophkeeper/internal/service/wifisvc"
+	"github.com/andymarkow/gophkeeper/internal/startup"
+)
+
+// purgeInterval is how often the background job checks for trashed secrets
+// past their retention window. It isn't exposed as a config knob yet since
+// nothing else about the purge cadence is configurable either.
+const purgeInterval = time.Hour
+
+// revealRateLimit and revealRateWindow bound how often a caller may reveal
+// a bank card or credential in plaintext. Not configurable yet, same as
+// purgeInterval.
+const (
+	revealRateLimit  = 30
+	revealRateWindow = time.Minute
+)
+
+// newServeCommand returns the `serve` command, which builds every
+// repository/service this binary knows how to construct from Config alone
+// and serves the HTTP API described by internal/api/openapi/spec.yaml.
+//
+// Secret types backed purely by in-memory storage (bankcard, credential,
+// generic, apitoken, wifi, otp) are always wired up. File and text secrets
+// additionally need an object storage backend for their content, so their
+// full CRUD/upload/download surface (and the trash/retention machinery
+// built on top of them) is only wired when S3.Endpoint is configured;
+// without it, serve logs that those routes are disabled and starts without
+// them rather than failing outright.
+//
+// A handful of handlers are deliberately left unmounted: the /admin/*
+// surfaces (deadletter replay, GDPR export/erase, migration status,
+// retention purge) and /scim/v2 have no authorization gate anywhere in this
+// codebase yet (see internal/api/middlewares), so serving them here would
+// expose operator-only actions to any caller with a valid session token.
+// They can be wired in once an admin-role check exists.
+func newServeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "serve",
+		Short: "Run the gophkeeper HTTP API",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := config.Load(configPath)
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+
+			return runServe(cmd.Context(), cfg, slog.Default())
+		},
+	}
+}
+
+func runServe(ctx context.Context, cfg *config.Config, logger *slog.Logger) error {
+	keyring, err := cryptutils.NewKeyringFromHex(cfg.Crypto.PrimaryKeyID, cfg.Crypto.Keys)
+	if err != nil {
+		return fmt.Errorf("build keyring: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	auditStore := audit.NewMemStore()
+	users := userrepo.NewMemStorage()
+	authSvc := authsvc.NewService(users, []byte(cfg.Auth.JWTSecret), cfg.Auth.TokenTTL, cfg.Auth.MaxLifetime, cfg.Auth.BcryptCost)
+
+	bankcardRepo := bankcardrepo.NewMemStorage()
+	genericRepo := genericrepo.NewMemStorage()
+
+	bankcards := bankcardsvc.NewService(bankcardRepo, keyring)
+	credentialSvc := credentialsvc.NewService(credrepo.NewMemStorage(), keyring, cfg.Limits.MaxSecretsPerType)
+	generics := genericsvc.NewService(genericRepo, keyring, cfg.Limits.MaxSecretsPerType)
+	apitokens := apitokensvc.NewService(apitokenrepo.NewMemStorage(), keyring, cfg.Limits.MaxSecretsPerType)
+	wifis := wifisvc.NewService(wifirepo.NewMemStorage(), keyring, cfg.Limits.MaxSecretsPerType)
+	otps := otpsvc.NewService(otprepo.NewMemStorage(), keyring, cfg.Limits.MaxSecretsPerType)
+
+	notifier := notifysvc.NewService(users, telegramrepo.NewMemStorage(), map[notifysvc.Channel]notify.Notifier{}, deadletterrepo.NewMemStorage())
+	relations := relationsvc.NewService(relationrepo.NewMemStorage())
+	reminders := remindersvc.NewService(reminderrepo.NewMemStorage(), bankcardRepo, apitokenrepo.NewMemStorage(), keyring, notifier)
+
+	reveal := ratelimit.NewFixedWindow(revealRateLimit, revealRateWindow)
+
+	router := api.NewRouter(api.RouterConfig{
+		RequestTimeout:     cfg.Server.RequestTimeout,
+		LongRequestTimeout: cfg.Server.LongRequestTimeout,
+		LongPaths:          longRequestPaths(),
+	})
+
+	router.Use(middlewares.RequestLogger(logger))
+
+	mountPublicRoutes(router, authSvc, cfg.Auth.TokenTTL)
+
+	protected := chi.NewRouter()
+	protected.Use(middlewares.Verifier(authSvc, authSvc))
+	protected.Use(middlewares.UserID)
+
+	mountSecretRoutes(protected, authSvc, bankcards, credentialSvc, generics, apitokens, wifis, otps, reveal, auditStore)
+	mountAggregateRoutes(protected, credentialSvc, bankcardRepo, generics, genericRepo, cfg.Limits.MaxListPageSize)
+	mountMiscRoutes(protected, authSvc, auditStore, relations, reminders, credentialSvc, bankcardRepo, keyring, cfg.Limits.MaxListPageSize)
+
+	var (
+		files *filesvc.Service
+		texts *textsvc.Service
+		deps  map[string]*startup.LazyInit
+	)
+
+	if cfg.S3.Endpoint != "" {
+		var trash *trashsvc.Service
+
+		files, texts, trash, err = buildFileTextServices(cfg, keyring, users)
+		if err != nil {
+			return fmt.Errorf("build file/text services: %w", err)
+		}
+
+		bw := bwlimit.NewLimiter(cfg.Limits.PerConnectionBytesPerSecond, cfg.Limits.PerUserBytesPerSecond)
+
+		mountFileTextRoutes(protected, authSvc, texts, files, bw)
+
+		retention := retentionsvc.NewService(trash, users, auditStore, cfg.Retention.DefaultTrashRetention)
+		go runRetentionPurge(ctx, retention, logger)
+
+		deps = map[string]*startup.LazyInit{
+			"object_storage": startup.NewLazyInit(ctx, startup.RetryConfig{
+				MaxAttempts: 0, InitialDelay: time.Second, MaxDelay: 30 * time.Second,
+			}, func(context.Context) error {
+				_, err := minio.New(cfg.S3.Endpoint, &minio.Options{
+					Creds:  credentials.NewStaticV4(cfg.S3.AccessKey, cfg.S3.SecretKey, ""),
+					Secure: cfg.S3.UseSSL,
+				})
+
+				return err
+			}),
+		}
+	} else {
+		logger.Warn("s3.endpoint not configured: file/text secret, trash and retention routes are disabled")
+	}
+
+	highsec := handlers.NewHighSecurityHandler(bankcards, credentialSvc, generics, texts, files, authSvc)
+	protected.Post("/secrets/{type}/{id}/high-security", highsec.Set)
+
+	router.Mount("/", protected)
+
+	adminRouter := api.NewAdminRouter(api.AdminRouterConfig{
+		Readyz: handlers.NewReadyzHandler(nil, deps).Readyz,
+	})
+
+	return serveHTTP(ctx, cfg, router, adminRouter, logger)
+}
+
+// longRequestPaths are the exact paths given cfg.Server.LongRequestTimeout
+// instead of RequestTimeout, matching file upload's larger payloads.
+// Downloads (/api/v1/files/{id}) carry the secret ID in the path, so no
+// exact entry matches them and they get RequestTimeout.
+func longRequestPaths() map[string]bool {
+	return map[string]bool{
+		"/api/v1/files":          true,
+		"/api/v1/files/stage":    true,
+		"/api/v1/files/complete": true,
+	}
+}
+
+func mountPublicRoutes(r chi.Router, authSvc *authsvc.Service, ttl time.Duration) {
+	auth := handlers.NewAuthHandler(authSvc, authSvc, nil, ttl)
+
+	r.Post("/auth/login", auth.Login)
+	r.Post("/auth/logout", auth.Logout)
+}
+
+func mountSecretRoutes(
+	r chi.Router,
+	authSvc *authsvc.Service,
+	bankcards *bankcardsvc.Service,
+	credentials *credentialsvc.Service,
+	generics *genericsvc.Service,
+	apitokens *apitokensvc.Service,
+	wifis *wifisvc.Service,
+	otps *otpsvc.Service,
+	reveal ratelimit.Limiter,
+	auditLog audit.Logger,
+) {
+	elevate := handlers.NewElevateHandler(authSvc)
+	r.Post("/auth/elevate", elevate.Elevate)
+
+	generic := handlers.NewGenericHandler(generics, authSvc)
+	r.Post("/secrets/generic", generic.Create)
+	r.Get("/secrets/generic", generic.List)
+	r.Get("/secrets/generic/{id}", generic.Get)
+	r.Put("/secrets/generic/{id}", generic.Update)
+	r.Delete("/secrets/generic/{id}", generic.Delete)
+
+	bankcard := handlers.NewBankCardHandler(bankcards, authSvc, reveal, auditLog)
+	r.Get("/secrets/bankcards/{id}", bankcard.Get)
+	r.Post("/secrets/bankcards/{id}/reveal", bankcard.Reveal)
+
+	credential := handlers.NewCredentialHandler(credentials, authSvc, 0, reveal, auditLog)
+	r.Get("/credentials", credential.List)
+	r.Get("/secrets/credentials/{id}", credential.Get)
+	r.Post("/secrets/credentials/{id}/reveal", credential.Reveal)
+	r.Post("/secrets/credentials/bulk", credential.Bulk)
+
+	apitoken := handlers.NewAPITokenHandler(apitokens)
+	r.Post("/secrets/apitokens", apitoken.Create)
+	r.Get("/secrets/apitokens", apitoken.List)
+	r.Get("/secrets/apitokens/{id}", apitoken.Get)
+	r.Put("/secrets/apitokens/{id}", apitoken.Update)
+	r.Delete("/secrets/apitokens/{id}", apitoken.Delete)
+
+	wifi := handlers.NewWiFiHandler(wifis)
+	r.Post("/secrets/wifi", wifi.Create)
+	r.Get("/wifi", wifi.List)
+	r.Get("/secrets/wifi/{id}", wifi.Get)
+	r.Put("/secrets/wifi/{id}", wifi.Update)
+	r.Delete("/secrets/wifi/{id}", wifi.Delete)
+	r.Get("/wifi/{id}/qr", wifi.ProvisioningPayload)
+
+	otp := handlers.NewOTPHandler(otps)
+	r.Post("/secrets/otp", otp.Create)
+	r.Get("/otp", otp.List)
+	r.Get("/secrets/otp/{id}", otp.Get)
+	r.Put("/secrets/otp/{id}", otp.Update)
+	r.Delete("/secrets/otp/{id}", otp.Delete)
+	r.Get("/otp/{id}/code", otp.GenerateCode)
+}
+
+func mountAggregateRoutes(
+	r chi.Router,
+	credentials *credentialsvc.Service,
+	bankcardRepo bankcardrepo.Storage,
+	generics *genericsvc.Service,
+	genericRepo genericrepo.Storage,
+	maxPageSize int,
+) {
+	sources := map[string]exportsvc.SourceFunc{
+		"credential": exportsvc.CredentialSource(credentials, maxPageSize),
+		"bankcard":   exportsvc.BankCardSource(bankcardRepo),
+		"generic":    exportsvc.GenericSource(genericRepo, generics),
+	}
+	exporter := exportsvc.NewNDJSONExporter(sources)
+
+	aggregate := handlers.NewAggregateSecretsHandler(exporter)
+	r.Get("/api/v1/secrets", aggregate.List)
+
+	search := handlers.NewSecretSearchHandler(exporter)
+	r.Get("/api/v1/secrets/search", search.Search)
+
+	ndjson := handlers.NewNDJSONExportHandler(exporter)
+	r.Get("/api/v1/export/ndjson", ndjson.Export)
+
+	stats := handlers.NewStatsHandler(map[string]handlers.StatsCounterFunc{
+		"credential": countFunc(credentials.List),
+		"generic":    countFunc(generics.List),
+	}, 0)
+	r.Get("/api/v1/stats", stats.Stats)
+}
+
+func mountMiscRoutes(
+	r chi.Router,
+	authSvc *authsvc.Service,
+	auditStore *audit.MemStore,
+	relations *relationsvc.Service,
+	reminders *remindersvc.Service,
+	credentials *credentialsvc.Service,
+	bankcardRepo bankcardrepo.Storage,
+	keyring *cryptutils.Keyring,
+	maxPageSize int,
+) {
+	auditHandler := handlers.NewAuditHandler(auditStore)
+	r.Get("/api/v1/audit", auditHandler.Mine)
+
+	csvExport := exportsvc.NewService(credentials, bankcardRepo, keyring, maxPageSize)
+	export := handlers.NewExportHandler(csvExport, authSvc, auditStore)
+	r.Get("/secrets/credentials/export", export.Credentials)
+	r.Get("/secrets/bankcards/export", export.BankCards)
+
+	relation := handlers.NewRelationHandler(relations)
+	r.Post("/relations", relation.Link)
+	r.Delete("/relations", relation.Unlink)
+	r.Get("/relations", relation.List)
+
+	reminder := handlers.NewReminderHandler(reminders)
+	r.Post("/reminders", reminder.Create)
+	r.Get("/reminders", reminder.List)
+	r.Get("/reminders/{id}", reminder.Get)
+	r.Delete("/reminders/{id}", reminder.Delete)
+}
+
+// countFunc adapts a secret service's List method into a
+// handlers.StatsCounterFunc reporting just the count.
+func countFunc[T any](list func(ctx context.Context, userID string) ([]T, error)) handlers.StatsCounterFunc {
+	return func(ctx context.Context, userID string) (int, error) {
+		items, err := list(ctx, userID)
+		if err != nil {
+			return 0, err
+		}
+
+		return len(items), nil
+	}
+}
+
+// buildFileTextServices wires file/text secret services against a real
+// MinIO-backed object store, plus the trashsvc adapter that lets
+// retentionsvc purge both secret types uniformly.
+func buildFileTextServices(
+	cfg *config.Config, keyring *cryptutils.Keyring, users *userrepo.MemStorage,
+) (*filesvc.Service, *textsvc.Service, *trashsvc.Service, error) {
+	minioClient, err := minio.New(cfg.S3.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.S3.AccessKey, cfg.S3.SecretKey, ""),
+		Secure: cfg.S3.UseSSL,
+	})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("build minio client: %w", err)
+	}
+
+	objects := objrepo.NewMinioClient(minioClient, cfg.S3.Bucket, objrepo.SSEConfig{})
+
+	files := filesvc.NewService(filerepo.NewMemStorage(), objects, keyring,
+		filesvc.ContentTypePolicy{Allow: cfg.Limits.AllowedContentTypes, Deny: cfg.Limits.DeniedContentTypes},
+		cfg.Limits.MaxFileVersions, cfg.Limits.MaxSecretsPerType)
+
+	texts := textsvc.NewService(textrepo.NewMemStorage(), objects, keyring, users, searchindexrepo.NewMemStorage(), cfg.Limits.MaxSecretsPerType)
+
+	return files, texts, trashsvc.NewService(files, texts), nil
+}
+
+func mountFileTextRoutes(
+	r chi.Router, authSvc *authsvc.Service, texts *textsvc.Service, files *filesvc.Service, bw *bwlimit.Limiter,
+) {
+	text := handlers.NewTextHandler(texts, authSvc)
+	r.Post("/secrets/texts", text.Create)
+	r.Get("/secrets/texts", text.List)
+	r.Get("/secrets/texts/search", text.Search)
+	r.Get("/secrets/texts/{id}", text.Get)
+	r.Put("/secrets/texts/{id}", text.Replace)
+	r.Delete("/secrets/texts/{id}", text.Delete)
+
+	file := handlers.NewFileHandler(files, authSvc, bw)
+	r.Post("/api/v1/files", file.Upload)
+	r.Post("/api/v1/files/stage", file.StageUpload)
+	r.Post("/api/v1/files/complete", file.CompleteUpload)
+	r.Get("/api/v1/files/{id}", file.Download)
+	r.Get("/api/v1/files/{id}/manifest", file.Manifest)
+	r.Get("/api/v1/files/{id}/versions/{version}", file.DownloadVersion)
+	r.Get("/api/v1/files/{id}/preview", file.Preview)
+	r.Get("/api/v1/files/{id}/thumbnail", file.Thumbnail)
+
+	trashHandler := handlers.NewTrashHandler(files, texts)
+	r.Get("/trash", trashHandler.List)
+	r.Post("/trash/{type}/{id}/restore", trashHandler.Restore)
+}
+
+// runRetentionPurge runs retention.PurgeExpired immediately, then every
+// purgeInterval until ctx is canceled.
+func runRetentionPurge(ctx context.Context, retention *retentionsvc.Service, logger *slog.Logger) {
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := retention.PurgeExpired(ctx); err != nil {
+			logger.Error("retention purge failed", "error", err)
+		} else if n > 0 {
+			logger.Info("retention purge completed", "purged", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// serveHTTP starts the public listener on cfg.Server.Address and, if
+// cfg.Server.AdminAddress is set, the admin listener alongside it, shutting
+// both down gracefully once ctx is canceled.
+func serveHTTP(ctx context.Context, cfg *config.Config, router, adminRouter http.Handler, logger *slog.Logger) error {
+	srv := &http.Server{Addr: cfg.Server.Address, Handler: router}
+
+	errCh := make(chan error, 2)
+
+	go func() {
+		logger.Info("starting API server", "address", cfg.Server.Address)
+
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- fmt.Errorf("api server: %w", err)
+
+			return
+		}
+
+		errCh <- nil
+	}()
+
+	var adminSrv *http.Server
+
+	if cfg.Server.AdminAddress != "" {
+		adminSrv = &http.Server{Addr: cfg.Server.AdminAddress, Handler: adminRouter}
+
+		go func() {
+			logger.Info("starting admin server", "address", cfg.Server.AdminAddress)
+
+			if err := adminSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errCh <- fmt.Errorf("admin server: %w", err)
+
+				return
+			}
+
+			errCh <- nil
+		}()
+	}
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return err
+		}
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var shutdownErr error
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		shutdownErr = fmt.Errorf("shutdown api server: %w", err)
+	}
+
+	if adminSrv != nil {
+		if err := adminSrv.Shutdown(shutdownCtx); err != nil {
+			shutdownErr = errors.Join(shutdownErr, fmt.Errorf("shutdown admin server: %w", err))
+		}
+	}
+
+	return shutdownErr
+}
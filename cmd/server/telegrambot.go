@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+
+	"github.com/andymarkow/gophkeeper/internal/audit"
+	"github.com/andymarkow/gophkeeper/internal/config"
+	"github.com/andymarkow/gophkeeper/internal/cryptutils"
+	"github.com/andymarkow/gophkeeper/internal/repository/credrepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/telegramrepo"
+	"github.com/andymarkow/gophkeeper/internal/service/credentialsvc"
+	"github.com/andymarkow/gophkeeper/internal/service/telegramsvc"
+	"github.com/andymarkow/gophkeeper/internal/telegrambot"
+)
+
+// newTelegramBotCommand returns the `telegram-bot` command, which long-polls
+// the Telegram Bot API and serves linked users' credential secrets via chat
+// commands. It refuses to start without a configured bot token.
+func newTelegramBotCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "telegram-bot",
+		Short: "Run the optional Telegram bot integration",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := config.Load(configPath)
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+
+			if cfg.Telegram.BotToken == "" {
+				return fmt.Errorf("telegram.bot_token is not configured")
+			}
+
+			keyring, err := cryptutils.NewKeyringFromHex(cfg.Crypto.PrimaryKeyID, cfg.Crypto.Keys)
+			if err != nil {
+				return fmt.Errorf("build keyring: %w", err)
+			}
+
+			api := telegrambot.NewAPI(cfg.Telegram.BotToken)
+			links := telegramsvc.NewService(telegramrepo.NewMemStorage())
+			secrets := credentialSecretReader{svc: credentialsvc.NewService(credrepo.NewMemStorage(), keyring, cfg.Limits.MaxSecretsPerType)}
+			auditLog := audit.NewSlogLogger(slog.Default())
+
+			bot := telegrambot.NewBot(api, links, secrets, auditLog)
+
+			fmt.Fprintln(cmd.OutOrStdout(), "telegram bot polling for updates")
+
+			return telegrambot.Run(cmd.Context(), api, bot, slog.Default())
+		},
+	}
+}
+
+// credentialSecretReader renders a credential secret as plain text for
+// telegrambot.SecretReader, since chat replies have no structured fields.
+type credentialSecretReader struct {
+	svc *credentialsvc.Service
+}
+
+func (r credentialSecretReader) GetByName(ctx context.Context, userID, name string) (string, error) {
+	secrets, err := r.svc.List(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	for _, sec := range secrets {
+		if sec.Name == name {
+			return fmt.Sprintf("login: %s\npassword: %s", sec.Login, sec.Password), nil
+		}
+	}
+
+	return "", fmt.Errorf("secret %q not found", name)
+}
@@ -0,0 +1,48 @@
+// Command smoketest runs a scripted end-to-end scenario (register, create
+// each secret type, upload, download, delete) against a live gophkeeper
+// instance and exits non-zero on any failure, for post-deploy verification.
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	var target string
+	var timeout time.Duration
+
+	root := &cobra.Command{
+		Use:   "smoketest",
+		Short: "Run an end-to-end smoke test against a live gophkeeper server",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			return runScenario(ctx, cmd, target)
+		},
+	}
+
+	root.Flags().StringVar(&target, "target", "http://localhost:8080", "base URL of the server under test")
+	root.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "overall scenario timeout")
+
+	if err := root.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "smoketest: FAIL:", err)
+		os.Exit(1)
+	}
+}
+
+func randomToken() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/andymarkow/gophkeeper/pkg/client"
+)
+
+// step is one checked action in the scenario. Failing a step aborts the
+// rest of the run.
+type step struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// runScenario registers a throwaway account, exercises each secret type
+// plus file upload/download, deletes the credential it created (the bank
+// card, text and file are left in place), and reports the first failure, so
+// it fails loud and fast under CI/ops tooling.
+func runScenario(ctx context.Context, cmd *cobra.Command, target string) error {
+	login, err := randomToken()
+	if err != nil {
+		return fmt.Errorf("generate login: %w", err)
+	}
+	login = "smoketest-" + login
+
+	password, err := randomToken()
+	if err != nil {
+		return fmt.Errorf("generate password: %w", err)
+	}
+
+	c := client.New(target)
+
+	var secretID string
+
+	steps := []step{
+		{"register", func(ctx context.Context) error {
+			return doJSON(ctx, c, http.MethodPost, target+"/api/v1/auth/register",
+				map[string]string{"login": login, "password": password}, nil)
+		}},
+		{"login", func(ctx context.Context) error {
+			var resp struct {
+				Token string `json:"token"`
+			}
+
+			if err := doJSON(ctx, c, http.MethodPost, target+"/api/v1/auth/login",
+				map[string]string{"login": login, "password": password}, &resp); err != nil {
+				return err
+			}
+
+			c = client.New(target, client.WithToken(resp.Token))
+
+			return nil
+		}},
+		{"create credential", func(ctx context.Context) error {
+			var resp struct {
+				ID string `json:"id"`
+			}
+
+			if err := doJSON(ctx, c, http.MethodPost, target+"/api/v1/credentials",
+				map[string]string{"name": "smoketest", "login": "x", "password": "y"}, &resp); err != nil {
+				return err
+			}
+
+			secretID = resp.ID
+
+			return nil
+		}},
+		{"create bankcard", func(ctx context.Context) error {
+			return doJSON(ctx, c, http.MethodPost, target+"/api/v1/bankcards",
+				map[string]string{"name": "smoketest", "number": "4111111111111111", "holder": "A B", "expiry_date": "12/30", "cvv": "123"}, nil)
+		}},
+		{"create text secret", func(ctx context.Context) error {
+			return doJSON(ctx, c, http.MethodPost, target+"/api/v1/texts",
+				map[string]string{"name": "smoketest", "content": "hello"}, nil)
+		}},
+		{"upload file", func(ctx context.Context) error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, target+"/api/v1/files",
+				bytes.NewReader([]byte("smoketest payload")))
+			if err != nil {
+				return err
+			}
+
+			return doRequest(ctx, c, req, nil)
+		}},
+		{"download file", func(ctx context.Context) error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, target+"/api/v1/files/smoketest", nil)
+			if err != nil {
+				return err
+			}
+
+			return doRequest(ctx, c, req, nil)
+		}},
+		{"delete credential", func(ctx context.Context) error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodDelete, target+"/api/v1/credentials/"+secretID, nil)
+			if err != nil {
+				return err
+			}
+
+			return doRequest(ctx, c, req, nil)
+		}},
+	}
+
+	for _, s := range steps {
+		if err := s.run(ctx); err != nil {
+			return fmt.Errorf("step %q: %w", s.name, err)
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "ok: %s\n", s.name)
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), "smoketest: PASS")
+
+	return nil
+}
+
+// doJSON sends a JSON-encoded body and decodes a JSON response into out, if
+// non-nil.
+func doJSON(ctx context.Context, c *client.Client, method, url string, body, out any) error {
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encode request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(buf))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return doRequest(ctx, c, req, out)
+}
+
+func doRequest(ctx context.Context, c *client.Client, req *http.Request, out any) error {
+	resp, err := c.Do(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(resp.Body)
+
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, b)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/andymarkow/gophkeeper/internal/api/reqctx"
+)
+
+// ContextWithUserID returns a copy of ctx carrying the authenticated user's
+// ID. It is called by middlewares.UserID once per request; handlers should
+// not call it directly.
+func ContextWithUserID(ctx context.Context, userID string) context.Context {
+	return reqctx.ContextWithUserID(ctx, userID)
+}
+
+// UserFromContext returns the authenticated user's ID placed on the context
+// by middlewares.UserID. ok is false if no identity middleware ran for this
+// request.
+func UserFromContext(ctx context.Context) (userID string, ok bool) {
+	return reqctx.UserFromContext(ctx)
+}
+
+// ContextWithLogger returns a copy of ctx carrying logger. Called by
+// middlewares.RequestLogger once per request.
+func ContextWithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return reqctx.ContextWithLogger(ctx, logger)
+}
+
+// LoggerFromContext returns the request-scoped logger placed on the context
+// by middlewares.RequestLogger, falling back to slog.Default() if none was
+// set (e.g. in tests that construct a bare context).
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	return reqctx.LoggerFromContext(ctx)
+}
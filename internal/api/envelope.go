@@ -0,0 +1,40 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// ListEnvelope is the standard response body for list endpoints: the page
+// of items plus enough metadata for a generic SDK paginator, instead of
+// each handler returning a bare array with its own ad hoc shape.
+type ListEnvelope[T any] struct {
+	Items []T `json:"items"`
+	// NextCursor is the opaque cursor for the following page, empty when
+	// the handler has no more pages to return. Handlers that list their
+	// full result set in one response (none currently page server-side)
+	// always leave this empty.
+	NextCursor string `json:"next_cursor,omitempty"`
+	// Total is the number of items matching the request, before paging.
+	Total int `json:"total"`
+	// RequestID echoes the chi request ID (see middlewares.Logger), so a
+	// client can correlate a list response with server-side logs.
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// NewListEnvelope builds a ListEnvelope for items, stamping RequestID from
+// r's chi request ID and NextCursor from nextCursor (empty for "no more
+// pages" or when the handler doesn't page server-side).
+func NewListEnvelope[T any](r *http.Request, items []T, nextCursor string) ListEnvelope[T] {
+	if items == nil {
+		items = []T{}
+	}
+
+	return ListEnvelope[T]{
+		Items:      items,
+		NextCursor: nextCursor,
+		Total:      len(items),
+		RequestID:  middleware.GetReqID(r.Context()),
+	}
+}
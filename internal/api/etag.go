@@ -0,0 +1,35 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+)
+
+// ETag formats checksum (a hex-encoded content hash, e.g.
+// contentinfo.ContentInfo.Checksum) as a quoted strong HTTP ETag value.
+func ETag(checksum string) string {
+	return `"` + checksum + `"`
+}
+
+// IfNoneMatchHit reports whether r's If-None-Match header already names
+// etag (or is "*"), so a download handler can respond 304 Not Modified
+// instead of re-sending content the client already has. It compares exact
+// values only; weak validators ("W/...") never match.
+func IfNoneMatchHit(r *http.Request, etag string) bool {
+	inm := r.Header.Get("If-None-Match")
+	if inm == "" {
+		return false
+	}
+
+	if inm == "*" {
+		return true
+	}
+
+	for _, candidate := range strings.Split(inm, ",") {
+		if strings.TrimSpace(candidate) == etag {
+			return true
+		}
+	}
+
+	return false
+}
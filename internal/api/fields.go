@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// ParseFieldsParam parses the ?fields= query parameter shared by get/list
+// endpoints, e.g. "?fields=id,name,updated_at", trimming response payloads
+// for mobile/low-bandwidth clients. ok is false (no trimming requested)
+// when the parameter is absent or empty.
+func ParseFieldsParam(r *http.Request) (fields []string, ok bool) {
+	raw := r.URL.Query().Get("fields")
+	if raw == "" {
+		return nil, false
+	}
+
+	for _, f := range strings.Split(raw, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+
+	return fields, len(fields) > 0
+}
+
+// TrimFields re-encodes v as JSON keeping only the given top-level field
+// names, so handlers don't need a partial-response variant of every
+// response type. If v (once marshaled) is an object with an "items" array
+// -- i.e. a ListEnvelope -- fields are applied to each item instead of the
+// envelope itself, leaving total/next_cursor/request_id untouched. v is
+// marshaled unchanged if fields is empty.
+func TrimFields(v any, fields []string) (json.RawMessage, error) {
+	if len(fields) == 0 {
+		return json.Marshal(v)
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return nil, err
+	}
+
+	set := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		set[f] = true
+	}
+
+	switch val := decoded.(type) {
+	case map[string]any:
+		if items, ok := val["items"].([]any); ok {
+			for i, item := range items {
+				items[i] = trimObject(item, set)
+			}
+
+			return json.Marshal(val)
+		}
+
+		return json.Marshal(trimObject(val, set))
+	case []any:
+		for i, item := range val {
+			val[i] = trimObject(item, set)
+		}
+
+		return json.Marshal(val)
+	default:
+		return raw, nil
+	}
+}
+
+// trimObject returns a copy of v keeping only the keys named in fields, or
+// v unchanged if it isn't a JSON object.
+func trimObject(v any, fields map[string]bool) any {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return v
+	}
+
+	out := make(map[string]any, len(fields))
+
+	for k := range fields {
+		if fv, ok := obj[k]; ok {
+			out[k] = fv
+		}
+	}
+
+	return out
+}
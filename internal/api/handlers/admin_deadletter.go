@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/andymarkow/gophkeeper/internal/api"
+	"github.com/andymarkow/gophkeeper/internal/api/httperr"
+	"github.com/andymarkow/gophkeeper/internal/domain/deadletter"
+	"github.com/andymarkow/gophkeeper/internal/repository/deadletterrepo"
+	"github.com/andymarkow/gophkeeper/internal/service/notifysvc"
+)
+
+// AdminDeadLetterHandler exposes operator visibility into notifications
+// that failed delivery after exhausting retries (see notifysvc.Service),
+// so an integration with a flaky webhook receiver doesn't silently lose
+// events.
+type AdminDeadLetterHandler struct {
+	notify *notifysvc.Service
+}
+
+// NewAdminDeadLetterHandler returns an AdminDeadLetterHandler backed by
+// notify.
+func NewAdminDeadLetterHandler(notify *notifysvc.Service) *AdminDeadLetterHandler {
+	return &AdminDeadLetterHandler{notify: notify}
+}
+
+type deadLetterResponse struct {
+	ID            string    `json:"id"`
+	UserID        string    `json:"user_id"`
+	Channel       string    `json:"channel"`
+	Kind          string    `json:"kind"`
+	Address       string    `json:"address"`
+	Subject       string    `json:"subject"`
+	Error         string    `json:"error"`
+	Attempts      int       `json:"attempts"`
+	CreatedAt     time.Time `json:"created_at"`
+	LastAttemptAt time.Time `json:"last_attempt_at"`
+}
+
+// List handles GET /admin/deadletters, returning every notification
+// delivery that exhausted retries, oldest first.
+func (h *AdminDeadLetterHandler) List(w http.ResponseWriter, r *http.Request) {
+	letters, err := h.notify.ListDeadLetters(r.Context())
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	resp := make([]deadLetterResponse, 0, len(letters))
+	for _, l := range letters {
+		resp = append(resp, toDeadLetterResponse(l))
+	}
+
+	writeJSONFields(w, r, api.NewListEnvelope(r, resp, ""))
+}
+
+// Replay handles POST /admin/deadletters/{id}/replay, retrying delivery and
+// removing the letter on success.
+func (h *AdminDeadLetterHandler) Replay(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if err := h.notify.ReplayDeadLetter(r.Context(), id); err != nil {
+		if errors.Is(err, deadletterrepo.ErrNotFound) {
+			httperr.NotFound(w, r, "dead letter not found")
+
+			return
+		}
+
+		if errors.Is(err, notifysvc.ErrChannelNotConfigured) {
+			httperr.UnprocessableEntity(w, r, "notification channel not configured")
+
+			return
+		}
+
+		httperr.Write(w, r, http.StatusBadGateway, "replay_failed", "replay delivery failed, dead letter retained")
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func toDeadLetterResponse(l *deadletter.Letter) deadLetterResponse {
+	return deadLetterResponse{
+		ID: l.ID, UserID: l.UserID, Channel: l.Channel, Kind: l.Kind, Address: l.Address,
+		Subject: l.Subject, Error: l.Error, Attempts: l.Attempts,
+		CreatedAt: l.CreatedAt, LastAttemptAt: l.LastAttemptAt,
+	}
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/andymarkow/gophkeeper/internal/api"
+	"github.com/andymarkow/gophkeeper/internal/api/httperr"
+	"github.com/andymarkow/gophkeeper/internal/repository/confirmationrepo"
+	"github.com/andymarkow/gophkeeper/internal/service/confirmsvc"
+	"github.com/andymarkow/gophkeeper/internal/service/gdprsvc"
+)
+
+// confirmActionErase is the confirmsvc action name scoping a force-delete
+// ticket to erasure specifically, so a ticket prepared for one destructive
+// action can't be replayed against another.
+const confirmActionErase = "admin.erase_user"
+
+// AdminGDPRHandler exposes the export/erasure endpoints used by operators to
+// service GDPR subject requests. Erase is destructive and irreversible, so
+// it requires a one-time ticket from PrepareErase instead of running on a
+// bare request.
+type AdminGDPRHandler struct {
+	gdpr    *gdprsvc.Service
+	confirm *confirmsvc.Service
+}
+
+// NewAdminGDPRHandler returns an AdminGDPRHandler backed by gdpr, gating
+// Erase on a ticket issued by confirm.
+func NewAdminGDPRHandler(gdpr *gdprsvc.Service, confirm *confirmsvc.Service) *AdminGDPRHandler {
+	return &AdminGDPRHandler{gdpr: gdpr, confirm: confirm}
+}
+
+// Export handles GET /admin/users/{userID}/export.
+func (h *AdminGDPRHandler) Export(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+
+	export, err := h.gdpr.ExportUser(r.Context(), userID)
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(export)
+}
+
+type prepareConfirmResponse struct {
+	ConfirmToken string `json:"confirm_token"`
+}
+
+// PrepareErase handles POST /admin/users/{userID}/erase/prepare, issuing a
+// one-time token Erase requires in its X-Confirm-Token header.
+func (h *AdminGDPRHandler) PrepareErase(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+	actorID, _ := api.UserFromContext(r.Context())
+
+	token, err := h.confirm.Prepare(r.Context(), actorID, confirmActionErase, userID)
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, prepareConfirmResponse{ConfirmToken: token})
+}
+
+// Erase handles POST /admin/users/{userID}/erase. The caller must supply a
+// valid, unused token from a prior PrepareErase call in X-Confirm-Token,
+// preventing an accidental or replayed request from erasing a user twice.
+func (h *AdminGDPRHandler) Erase(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+
+	if err := h.confirm.Confirm(r.Context(), r.Header.Get("X-Confirm-Token"), confirmActionErase, userID); err != nil {
+		if errors.Is(err, confirmationrepo.ErrInvalidTicket) {
+			httperr.UnprocessableEntity(w, r, "missing or invalid confirmation token")
+
+			return
+		}
+
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	if err := h.gdpr.EraseUser(r.Context(), userID); err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/andymarkow/gophkeeper/internal/api/httperr"
+	"github.com/andymarkow/gophkeeper/internal/service/migrationsvc"
+)
+
+// migrationStatuser reports applied/pending migration state.
+type migrationStatuser interface {
+	Status(ctx context.Context) ([]migrationsvc.Migration, error)
+}
+
+// AdminMigrationsHandler exposes the migration status endpoint used by
+// operators to verify schema state without shelling into the database.
+type AdminMigrationsHandler struct {
+	migrations migrationStatuser
+}
+
+// NewAdminMigrationsHandler returns an AdminMigrationsHandler backed by
+// migrations.
+func NewAdminMigrationsHandler(migrations migrationStatuser) *AdminMigrationsHandler {
+	return &AdminMigrationsHandler{migrations: migrations}
+}
+
+type migrationStatus struct {
+	Version int64  `json:"version"`
+	Name    string `json:"name"`
+	Applied bool   `json:"applied"`
+}
+
+// Status handles GET /admin/migrations.
+func (h *AdminMigrationsHandler) Status(w http.ResponseWriter, r *http.Request) {
+	migrations, err := h.migrations.Status(r.Context())
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	resp := make([]migrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		resp = append(resp, migrationStatus{Version: m.Version, Name: m.Name, Applied: m.Applied})
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(resp)
+}
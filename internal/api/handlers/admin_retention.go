@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/andymarkow/gophkeeper/internal/api"
+	"github.com/andymarkow/gophkeeper/internal/api/httperr"
+	"github.com/andymarkow/gophkeeper/internal/repository/confirmationrepo"
+	"github.com/andymarkow/gophkeeper/internal/service/confirmsvc"
+	"github.com/andymarkow/gophkeeper/internal/service/retentionsvc"
+)
+
+// confirmActionPurgeTrash scopes a purge ticket to the global trash purge
+// specifically.
+const confirmActionPurgeTrash = "admin.purge_trash"
+
+// confirmTargetGlobal is the Target a global (not per-user) destructive
+// action is confirmed against.
+const confirmTargetGlobal = "*"
+
+// AdminRetentionHandler exposes the instance-wide trash purge job as an
+// on-demand admin action, for an operator who wants to reclaim storage
+// immediately rather than waiting for the next scheduled run. Purge is
+// destructive and irreversible, so it requires a one-time ticket from
+// PreparePurge instead of running on a bare request.
+type AdminRetentionHandler struct {
+	retention *retentionsvc.Service
+	confirm   *confirmsvc.Service
+}
+
+// NewAdminRetentionHandler returns an AdminRetentionHandler backed by
+// retention, gating Purge on a ticket issued by confirm.
+func NewAdminRetentionHandler(retention *retentionsvc.Service, confirm *confirmsvc.Service) *AdminRetentionHandler {
+	return &AdminRetentionHandler{retention: retention, confirm: confirm}
+}
+
+// PreparePurge handles POST /admin/retention/purge/prepare, issuing a
+// one-time token Purge requires in its X-Confirm-Token header.
+func (h *AdminRetentionHandler) PreparePurge(w http.ResponseWriter, r *http.Request) {
+	actorID, _ := api.UserFromContext(r.Context())
+
+	token, err := h.confirm.Prepare(r.Context(), actorID, confirmActionPurgeTrash, confirmTargetGlobal)
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, prepareConfirmResponse{ConfirmToken: token})
+}
+
+type purgeResponse struct {
+	Purged int `json:"purged"`
+}
+
+// Purge handles POST /admin/retention/purge, running retentionsvc's purge
+// job immediately across every account instead of waiting for its next
+// scheduled run. The caller must supply a valid, unused token from a prior
+// PreparePurge call in X-Confirm-Token.
+func (h *AdminRetentionHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	if err := h.confirm.Confirm(r.Context(), r.Header.Get("X-Confirm-Token"), confirmActionPurgeTrash, confirmTargetGlobal); err != nil {
+		if errors.Is(err, confirmationrepo.ErrInvalidTicket) {
+			httperr.UnprocessableEntity(w, r, "missing or invalid confirmation token")
+
+			return
+		}
+
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	purged, err := h.retention.PurgeExpired(r.Context())
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	writeJSON(w, purgeResponse{Purged: purged})
+}
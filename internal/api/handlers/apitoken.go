@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/andymarkow/gophkeeper/internal/api"
+	"github.com/andymarkow/gophkeeper/internal/api/httperr"
+	"github.com/andymarkow/gophkeeper/internal/api/validate"
+	"github.com/andymarkow/gophkeeper/internal/domain/apitoken"
+	"github.com/andymarkow/gophkeeper/internal/domain/metadata"
+	"github.com/andymarkow/gophkeeper/internal/repository/apitokenrepo"
+	"github.com/andymarkow/gophkeeper/internal/service/apitokensvc"
+)
+
+// maxAPITokenNameLen caps how long an API token secret's Name may be.
+const maxAPITokenNameLen = 255
+
+// validateAPITokenRequest checks req's fields, returning one FieldError per
+// problem found.
+func validateAPITokenRequest(req apiTokenRequest) validate.Errors {
+	var verrs validate.Errors
+
+	verrs.Required("name", req.Name)
+	verrs.MaxLen("name", req.Name, maxAPITokenNameLen)
+	verrs.Required("token", req.Token)
+
+	return verrs
+}
+
+// apiTokenStore is the API token secret CRUD surface APITokenHandler depends
+// on, plus the masked-list used by List.
+type apiTokenStore interface {
+	ListMasked(ctx context.Context, userID string) ([]*apitokensvc.Masked, error)
+	EncryptToken(token string) (ciphertext []byte, keyID string, err error)
+	DecryptToken(sec *apitoken.Secret) (string, error)
+	Create(ctx context.Context, userID string, sec *apitoken.Secret) error
+	Get(ctx context.Context, userID, id string) (*apitoken.Secret, error)
+	Update(ctx context.Context, userID string, sec *apitoken.Secret) error
+	Delete(ctx context.Context, userID, id string) error
+}
+
+// APITokenHandler exposes full CRUD over /secrets/apitokens. List masks
+// Token to its last 4 characters; Create, Update and Get return it in full,
+// so there is no separate masked-GET/plaintext-reveal split for this type.
+type APITokenHandler struct {
+	apitokens apiTokenStore
+}
+
+// NewAPITokenHandler returns an APITokenHandler backed by apitokens.
+func NewAPITokenHandler(apitokens apiTokenStore) *APITokenHandler {
+	return &APITokenHandler{apitokens: apitokens}
+}
+
+type apiTokenRequest struct {
+	Name        string       `json:"name"`
+	ServiceName string       `json:"service_name,omitempty"`
+	Token       string       `json:"token"`
+	Scopes      []string     `json:"scopes,omitempty"`
+	ExpiresAt   *time.Time   `json:"expires_at,omitempty"`
+	Metadata    metadata.Bag `json:"metadata,omitempty"`
+}
+
+type apiTokenResponse struct {
+	ID          string       `json:"id"`
+	Name        string       `json:"name"`
+	ServiceName string       `json:"service_name,omitempty"`
+	Token       string       `json:"token"`
+	Scopes      []string     `json:"scopes,omitempty"`
+	ExpiresAt   *time.Time   `json:"expires_at,omitempty"`
+	Metadata    metadata.Bag `json:"metadata,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// Create handles POST /secrets/apitokens, responding 201 with a Location
+// header pointing at the new secret and Token returned in full (as Update
+// and Get also return it).
+func (h *APITokenHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	var req apiTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.BadRequest(w, r, "invalid request body")
+
+		return
+	}
+
+	if verrs := validateAPITokenRequest(req); verrs.HasErrors() {
+		httperr.ValidationFailed(w, r, verrs)
+
+		return
+	}
+
+	ciphertext, keyID, err := h.apitokens.EncryptToken(req.Token)
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	id, err := randomID()
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	sec := &apitoken.Secret{
+		ID: id, Name: req.Name, ServiceName: req.ServiceName, Token: ciphertext, KeyID: keyID,
+		Scopes: req.Scopes, ExpiresAt: req.ExpiresAt, Metadata: req.Metadata,
+	}
+
+	if err := h.apitokens.Create(r.Context(), userID, sec); err != nil {
+		if errors.Is(err, apitokensvc.ErrSecretLimitExceeded) {
+			httperr.UnprocessableEntity(w, r, "secret limit exceeded for this account")
+
+			return
+		}
+
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	w.Header().Set("Location", "/api/v1/secrets/apitokens/"+url.PathEscape(sec.Name))
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, toAPITokenResponse(sec, req.Token))
+}
+
+// Get handles GET /secrets/apitokens/{id}, returning Token decrypted in
+// full.
+func (h *APITokenHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	sec, err := h.apitokens.Get(r.Context(), userID, chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeGetErr(w, r, err)
+
+		return
+	}
+
+	token, err := h.apitokens.DecryptToken(sec)
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	writeJSONFields(w, r, toAPITokenResponse(sec, token))
+}
+
+// Update handles PUT /secrets/apitokens/{id}.
+func (h *APITokenHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+	id := chi.URLParam(r, "id")
+
+	var req apiTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.BadRequest(w, r, "invalid request body")
+
+		return
+	}
+
+	if verrs := validateAPITokenRequest(req); verrs.HasErrors() {
+		httperr.ValidationFailed(w, r, verrs)
+
+		return
+	}
+
+	ciphertext, keyID, err := h.apitokens.EncryptToken(req.Token)
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	sec := &apitoken.Secret{
+		ID: id, Name: req.Name, ServiceName: req.ServiceName, Token: ciphertext, KeyID: keyID,
+		Scopes: req.Scopes, ExpiresAt: req.ExpiresAt, Metadata: req.Metadata,
+	}
+
+	if err := h.apitokens.Update(r.Context(), userID, sec); err != nil {
+		h.writeGetErr(w, r, err)
+
+		return
+	}
+
+	writeJSON(w, toAPITokenResponse(sec, req.Token))
+}
+
+// Delete handles DELETE /secrets/apitokens/{id}.
+func (h *APITokenHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	if err := h.apitokens.Delete(r.Context(), userID, chi.URLParam(r, "id")); err != nil {
+		h.writeGetErr(w, r, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// List handles GET /secrets/apitokens, returning every token owned by the
+// caller with Token masked to its last 4 characters. Supports ?selector=
+// and ?metadata.<key>=<value> filtering on metadata (see
+// parseSelectorParam).
+func (h *APITokenHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	sel, ok := parseSelectorParam(w, r)
+	if !ok {
+		return
+	}
+
+	secrets, err := h.apitokens.ListMasked(r.Context(), userID)
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	resp := make([]apiTokenResponse, 0, len(secrets))
+	for _, sec := range secrets {
+		if !sel.Matches(sec.Metadata) {
+			continue
+		}
+
+		resp = append(resp, apiTokenResponse{
+			ID: sec.ID, Name: sec.Name, ServiceName: sec.ServiceName, Token: sec.Token,
+			Scopes: sec.Scopes, ExpiresAt: sec.ExpiresAt, Metadata: sec.Metadata,
+			CreatedAt: sec.CreatedAt, UpdatedAt: sec.UpdatedAt,
+		})
+	}
+
+	writeJSONFields(w, r, api.NewListEnvelope(r, resp, ""))
+}
+
+func (h *APITokenHandler) writeGetErr(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, apitokensvc.ErrAccessDenied):
+		httperr.Forbidden(w, r, "access denied")
+	case errors.Is(err, apitokenrepo.ErrNotFound):
+		httperr.NotFound(w, r, "secret not found")
+	default:
+		httperr.Internal(w, r)
+	}
+}
+
+func toAPITokenResponse(sec *apitoken.Secret, token string) apiTokenResponse {
+	return apiTokenResponse{
+		ID: sec.ID, Name: sec.Name, ServiceName: sec.ServiceName, Token: token,
+		Scopes: sec.Scopes, ExpiresAt: sec.ExpiresAt, Metadata: sec.Metadata,
+		CreatedAt: sec.CreatedAt, UpdatedAt: sec.UpdatedAt,
+	}
+}
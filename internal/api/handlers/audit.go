@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/api"
+	"github.com/andymarkow/gophkeeper/internal/api/httperr"
+	"github.com/andymarkow/gophkeeper/internal/audit"
+)
+
+// auditQuerier queries the audit trail with filters and cursor pagination.
+type auditQuerier interface {
+	Query(ctx context.Context, filter audit.Filter) (events []audit.Event, nextCursor string, err error)
+}
+
+// AuditHandler exposes the audit trail over HTTP: Mine for a user's own
+// actions, Admin for operators querying across every actor.
+type AuditHandler struct {
+	audit auditQuerier
+}
+
+// NewAuditHandler returns an AuditHandler backed by store.
+func NewAuditHandler(store auditQuerier) *AuditHandler {
+	return &AuditHandler{audit: store}
+}
+
+type auditEventResponse struct {
+	Action    string    `json:"action"`
+	ActorID   string    `json:"actor_id"`
+	Target    string    `json:"target"`
+	Detail    string    `json:"detail"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type auditQueryResponse struct {
+	Events     []auditEventResponse `json:"events"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
+// Mine handles GET /api/v1/audit, returning the caller's own audit trail
+// filtered by ?action=, ?target=, ?since=, ?until= and ?cursor= (all
+// optional; since/until are RFC 3339 timestamps).
+func (h *AuditHandler) Mine(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	filter, ok := parseAuditFilter(w, r)
+	if !ok {
+		return
+	}
+
+	filter.ActorID = userID
+
+	h.query(w, r, filter)
+}
+
+// Admin handles GET /admin/audit, returning the audit trail across every
+// actor, additionally filterable by ?actor_id=.
+func (h *AuditHandler) Admin(w http.ResponseWriter, r *http.Request) {
+	filter, ok := parseAuditFilter(w, r)
+	if !ok {
+		return
+	}
+
+	filter.ActorID = r.URL.Query().Get("actor_id")
+
+	h.query(w, r, filter)
+}
+
+func (h *AuditHandler) query(w http.ResponseWriter, r *http.Request, filter audit.Filter) {
+	events, nextCursor, err := h.audit.Query(r.Context(), filter)
+	if err != nil {
+		httperr.BadRequest(w, r, err.Error())
+
+		return
+	}
+
+	resp := auditQueryResponse{Events: make([]auditEventResponse, 0, len(events)), NextCursor: nextCursor}
+	for _, e := range events {
+		resp.Events = append(resp.Events, auditEventResponse{
+			Action: e.Action, ActorID: e.ActorID, Target: e.Target, Detail: e.Detail, Timestamp: e.Timestamp,
+		})
+	}
+
+	writeJSON(w, resp)
+}
+
+func parseAuditFilter(w http.ResponseWriter, r *http.Request) (audit.Filter, bool) {
+	q := r.URL.Query()
+
+	filter := audit.Filter{
+		Action: q.Get("action"),
+		Target: q.Get("target"),
+		Cursor: q.Get("cursor"),
+	}
+
+	if v := q.Get("since"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			httperr.BadRequest(w, r, "invalid since: must be RFC 3339")
+
+			return audit.Filter{}, false
+		}
+
+		filter.Since = t
+	}
+
+	if v := q.Get("until"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			httperr.BadRequest(w, r, "invalid until: must be RFC 3339")
+
+			return audit.Filter{}, false
+		}
+
+		filter.Until = t
+	}
+
+	return filter, true
+}
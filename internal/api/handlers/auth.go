@@ -0,0 +1,115 @@
+// Package handlers implements the HTTP handlers mounted on the API router.
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/api"
+	"github.com/andymarkow/gophkeeper/internal/api/httperr"
+	"github.com/andymarkow/gophkeeper/internal/api/middlewares"
+)
+
+// tokenIssuer issues session tokens for authenticated users.
+type tokenIssuer interface {
+	IssueToken(ctx context.Context, userID string) (string, error)
+}
+
+// authenticator verifies a login/password pair and returns the user ID.
+type authenticator interface {
+	Authenticate(ctx context.Context, login, password string) (userID string, err error)
+}
+
+// loginAlerter notifies a user when their account is used from a new
+// device/IP. Failures are logged but never block the login itself.
+type loginAlerter interface {
+	NotifyIfNewDevice(ctx context.Context, userID, ip, userAgent string) error
+}
+
+// AuthHandler exposes login/logout endpoints.
+type AuthHandler struct {
+	auth   authenticator
+	tokens tokenIssuer
+	alerts loginAlerter
+	ttl    time.Duration
+}
+
+// NewAuthHandler returns an AuthHandler issuing tokens with the given TTL.
+// alerts may be nil to disable new-device login notifications.
+func NewAuthHandler(auth authenticator, tokens tokenIssuer, alerts loginAlerter, ttl time.Duration) *AuthHandler {
+	return &AuthHandler{auth: auth, tokens: tokens, alerts: alerts, ttl: ttl}
+}
+
+type loginRequest struct {
+	Login     string `json:"login"`
+	Password  string `json:"password"`
+	Transport string `json:"transport"` // "" (bearer, default) or "cookie"
+}
+
+type loginResponse struct {
+	Token string `json:"token,omitempty"`
+}
+
+// Login authenticates the caller and issues a session token, either in the
+// response body (default) or as an HttpOnly cookie when the client asks for
+// transport=cookie.
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var req loginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.BadRequest(w, r, "invalid request body")
+
+		return
+	}
+
+	userID, err := h.auth.Authenticate(r.Context(), req.Login, req.Password)
+	if err != nil {
+		httperr.Unauthorized(w, r, "invalid login or password")
+
+		return
+	}
+
+	token, err := h.tokens.IssueToken(r.Context(), userID)
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	if h.alerts != nil {
+		if err := h.alerts.NotifyIfNewDevice(r.Context(), userID, clientIP(r), r.UserAgent()); err != nil {
+			api.LoggerFromContext(r.Context()).Warn("login alert failed", "error", err, "user_id", userID)
+		}
+	}
+
+	if req.Transport == "cookie" {
+		middlewares.SetAuthCookie(w, token, h.ttl)
+		w.WriteHeader(http.StatusNoContent)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(loginResponse{Token: token})
+}
+
+// Logout clears the auth cookie, if any. Bearer tokens remain valid until
+// they expire since they are not tracked server-side.
+func (h *AuthHandler) Logout(w http.ResponseWriter, _ *http.Request) {
+	middlewares.ClearAuthCookie(w)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// clientIP returns the originating client address, preferring the first
+// X-Forwarded-For hop when present (trusted only behind our own proxy).
+func clientIP(r *http.Request) string {
+	if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+		first, _, _ := strings.Cut(fwd, ",")
+
+		return strings.TrimSpace(first)
+	}
+
+	return r.RemoteAddr
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/andymarkow/gophkeeper/internal/api"
+	"github.com/andymarkow/gophkeeper/internal/api/httperr"
+	"github.com/andymarkow/gophkeeper/internal/audit"
+	"github.com/andymarkow/gophkeeper/internal/domain/metadata"
+	"github.com/andymarkow/gophkeeper/internal/ratelimit"
+	"github.com/andymarkow/gophkeeper/internal/repository/bankcardrepo"
+	"github.com/andymarkow/gophkeeper/internal/service/bankcardsvc"
+)
+
+// bankCardGetter serves the masked-GET/plaintext-reveal split for a single
+// bank card secret.
+type bankCardGetter interface {
+	MaskedGet(ctx context.Context, userID, id string) (*bankcardsvc.Masked, error)
+	RevealGet(ctx context.Context, userID, id string) (*bankcardsvc.Decrypted, error)
+}
+
+// BankCardHandler exposes the masked-GET/plaintext-reveal split for bank
+// card secrets. Bank cards otherwise have no HTTP handler yet (see
+// internal/domain/bankcard), so create/update/delete/list do not exist
+// here until added. A card marked metadata.HighSecurityKey requires a valid
+// elevation token (see ElevateHandler) to reveal; without one, Reveal
+// refuses the request instead of returning plaintext.
+type BankCardHandler struct {
+	cards     bankCardGetter
+	elevation elevationVerifier
+	reveal    ratelimit.Limiter
+	audit     audit.Logger
+}
+
+// NewBankCardHandler returns a BankCardHandler backed by cards, gating
+// high-security reveals on elevation. Reveal requests are throttled by
+// limiter and always recorded via logger.
+func NewBankCardHandler(cards bankCardGetter, elevation elevationVerifier, limiter ratelimit.Limiter, logger audit.Logger) *BankCardHandler {
+	return &BankCardHandler{cards: cards, elevation: elevation, reveal: limiter, audit: logger}
+}
+
+type bankCardResponse struct {
+	ID         string       `json:"id"`
+	Name       string       `json:"name"`
+	Number     string       `json:"number,omitempty"`
+	Holder     string       `json:"holder,omitempty"`
+	ExpiryDate string       `json:"expiry_date,omitempty"`
+	CVV        string       `json:"cvv,omitempty"`
+	Metadata   metadata.Bag `json:"metadata,omitempty"`
+	CreatedAt  time.Time    `json:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+}
+
+// Get handles GET /secrets/bankcards/{id}, returning Number and CVV masked
+// so a routine fetch never puts plaintext card data in a response, access
+// log or intermediate cache. Use Reveal to obtain the real values.
+func (h *BankCardHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	sec, err := h.cards.MaskedGet(r.Context(), userID, chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeGetErr(w, r, err)
+
+		return
+	}
+
+	writeJSONFields(w, r, bankCardResponse{
+		ID: sec.ID, Name: sec.Name, Number: sec.Number, Holder: sec.Holder, ExpiryDate: sec.ExpiryDate,
+		CVV: sec.CVV, Metadata: sec.Metadata, CreatedAt: sec.CreatedAt, UpdatedAt: sec.UpdatedAt,
+	})
+}
+
+// Reveal handles POST /secrets/bankcards/{id}/reveal, returning every field
+// decrypted in full. It is rate limited per caller and always recorded to
+// the audit log regardless of outcome, so plaintext exposure can always be
+// traced back to a specific request.
+func (h *BankCardHandler) Reveal(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+	id := chi.URLParam(r, "id")
+
+	allowed, err := h.reveal.Allow(r.Context(), userID)
+	if err == nil && !allowed {
+		httperr.TooManyRequests(w, r, "rate limit exceeded")
+
+		return
+	}
+
+	sec, err := h.cards.RevealGet(r.Context(), userID, id)
+	if err == nil {
+		err = checkHighSecurity(r, h.elevation, sec.Metadata)
+	}
+
+	h.audit.Log(r.Context(), audit.Event{
+		Action: "secrets.reveal", ActorID: userID, Target: id, Detail: errDetail(err),
+	})
+
+	if errors.Is(err, ErrStepUpRequired) {
+		httperr.Forbidden(w, r, "step-up authentication required")
+
+		return
+	}
+
+	if err != nil {
+		h.writeGetErr(w, r, err)
+
+		return
+	}
+
+	writeJSON(w, bankCardResponse{
+		ID: sec.ID, Name: sec.Name, Number: sec.Number, Holder: sec.Holder, ExpiryDate: sec.ExpiryDate,
+		CVV: sec.CVV, Metadata: sec.Metadata, CreatedAt: sec.CreatedAt, UpdatedAt: sec.UpdatedAt,
+	})
+}
+
+func (h *BankCardHandler) writeGetErr(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, bankcardsvc.ErrAccessDenied):
+		httperr.Forbidden(w, r, "access denied")
+	case errors.Is(err, bankcardrepo.ErrNotFound):
+		httperr.NotFound(w, r, "secret not found")
+	default:
+		httperr.Internal(w, r)
+	}
+}
@@ -0,0 +1,322 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/andymarkow/gophkeeper/internal/api"
+	"github.com/andymarkow/gophkeeper/internal/api/httperr"
+	"github.com/andymarkow/gophkeeper/internal/audit"
+	"github.com/andymarkow/gophkeeper/internal/domain/credential"
+	"github.com/andymarkow/gophkeeper/internal/domain/metadata"
+	"github.com/andymarkow/gophkeeper/internal/ratelimit"
+	"github.com/andymarkow/gophkeeper/internal/repository/credrepo"
+	"github.com/andymarkow/gophkeeper/internal/service/credentialsvc"
+)
+
+// credentialLister lists a user's credential secrets, optionally decrypted
+// in bulk, bulk-creates new ones, and serves the masked-GET/plaintext-reveal
+// split for a single secret.
+type credentialLister interface {
+	List(ctx context.Context, userID string) ([]*credential.Secret, error)
+	ListWithData(ctx context.Context, userID string, maxPageSize int) ([]*credentialsvc.Decrypted, error)
+	EncryptFields(login, password string) (loginCT, passwordCT []byte, keyID, loginIndex string, err error)
+	BulkCreate(ctx context.Context, userID string, secrets []*credential.Secret) ([]credentialsvc.BulkResult, error)
+	MaskedGet(ctx context.Context, userID, id string) (*credentialsvc.Masked, error)
+	RevealGet(ctx context.Context, userID, id string) (*credentialsvc.Decrypted, error)
+	FindByLogin(ctx context.Context, userID, login string) (*credential.Secret, error)
+}
+
+// CredentialHandler exposes credential secret listing, including the
+// decrypt-in-bulk variant used by clients rendering a full vault, and the
+// masked-GET/plaintext-reveal split for a single secret (see Get/Reveal). A
+// secret marked metadata.HighSecurityKey requires a valid elevation token
+// (see ElevateHandler) to reveal; without one, Reveal refuses the request
+// instead of returning plaintext.
+type CredentialHandler struct {
+	credentials credentialLister
+	elevation   elevationVerifier
+	maxPageSize int
+	reveal      ratelimit.Limiter
+	audit       audit.Logger
+}
+
+// NewCredentialHandler returns a CredentialHandler backed by credentials,
+// gating high-security reveals on elevation. ?include=data responses fail
+// with 413 once a user's secret count exceeds maxPageSize. Reveal requests
+// are throttled by limiter and always recorded via logger.
+func NewCredentialHandler(credentials credentialLister, elevation elevationVerifier, maxPageSize int, limiter ratelimit.Limiter, logger audit.Logger) *CredentialHandler {
+	return &CredentialHandler{credentials: credentials, elevation: elevation, maxPageSize: maxPageSize, reveal: limiter, audit: logger}
+}
+
+type credentialResponse struct {
+	ID        string       `json:"id"`
+	Name      string       `json:"name"`
+	Login     string       `json:"login,omitempty"`
+	Password  string       `json:"password,omitempty"`
+	Metadata  metadata.Bag `json:"metadata,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// List handles GET /credentials. Passing ?include=data decrypts and
+// includes each secret's login/password in the response instead of
+// requiring one GET per secret; it is rejected with 413 once the user's
+// vault exceeds the configured page-size cap. Passing ?login= instead
+// looks up the single secret with that login via its blind index, without
+// decrypting the rest of the vault to find it.
+func (h *CredentialHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	if login := r.URL.Query().Get("login"); login != "" {
+		sec, err := h.credentials.FindByLogin(r.Context(), userID, login)
+		if err != nil {
+			if errors.Is(err, credrepo.ErrNotFound) {
+				writeJSONFields(w, r, api.NewListEnvelope(r, []credentialResponse{}, ""))
+
+				return
+			}
+
+			httperr.Internal(w, r)
+
+			return
+		}
+
+		writeJSONFields(w, r, api.NewListEnvelope(r, []credentialResponse{{
+			ID: sec.ID, Name: sec.Name, Metadata: sec.Metadata,
+			CreatedAt: sec.CreatedAt, UpdatedAt: sec.UpdatedAt,
+		}}, ""))
+
+		return
+	}
+
+	sel, ok := parseSelectorParam(w, r)
+	if !ok {
+		return
+	}
+
+	if r.URL.Query().Get("include") != "data" {
+		secrets, err := h.credentials.List(r.Context(), userID)
+		if err != nil {
+			httperr.Internal(w, r)
+
+			return
+		}
+
+		resp := make([]credentialResponse, 0, len(secrets))
+		for _, sec := range secrets {
+			if !sel.Matches(sec.Metadata) {
+				continue
+			}
+
+			resp = append(resp, credentialResponse{
+				ID: sec.ID, Name: sec.Name, Metadata: sec.Metadata,
+				CreatedAt: sec.CreatedAt, UpdatedAt: sec.UpdatedAt,
+			})
+		}
+
+		writeJSONFields(w, r, api.NewListEnvelope(r, resp, ""))
+
+		return
+	}
+
+	secrets, err := h.credentials.ListWithData(r.Context(), userID, h.maxPageSize)
+	if err != nil {
+		if errors.Is(err, credentialsvc.ErrPageSizeExceeded) {
+			httperr.TooLarge(w, r, "too many secrets for ?include=data, page through the default list instead")
+
+			return
+		}
+
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	resp := make([]credentialResponse, 0, len(secrets))
+	for _, sec := range secrets {
+		if !sel.Matches(sec.Metadata) {
+			continue
+		}
+
+		resp = append(resp, credentialResponse{
+			ID: sec.ID, Name: sec.Name, Login: sec.Login, Password: sec.Password,
+			Metadata: sec.Metadata, CreatedAt: sec.CreatedAt, UpdatedAt: sec.UpdatedAt,
+		})
+	}
+
+	writeJSONFields(w, r, api.NewListEnvelope(r, resp, ""))
+}
+
+// Get handles GET /secrets/credentials/{id}, returning Login and Password
+// masked so a routine fetch never puts plaintext credentials in a response,
+// access log or intermediate cache. Use Reveal to obtain the real values.
+func (h *CredentialHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	sec, err := h.credentials.MaskedGet(r.Context(), userID, chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeGetErr(w, r, err)
+
+		return
+	}
+
+	writeJSONFields(w, r, credentialResponse{
+		ID: sec.ID, Name: sec.Name, Login: sec.Login, Password: sec.Password,
+		Metadata: sec.Metadata, CreatedAt: sec.CreatedAt, UpdatedAt: sec.UpdatedAt,
+	})
+}
+
+// Reveal handles POST /secrets/credentials/{id}/reveal, returning Login and
+// Password decrypted in full. It is rate limited per caller and always
+// recorded to the audit log regardless of outcome, so plaintext exposure
+// can always be traced back to a specific request.
+func (h *CredentialHandler) Reveal(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+	id := chi.URLParam(r, "id")
+
+	allowed, err := h.reveal.Allow(r.Context(), userID)
+	if err == nil && !allowed {
+		httperr.TooManyRequests(w, r, "rate limit exceeded")
+
+		return
+	}
+
+	sec, err := h.credentials.RevealGet(r.Context(), userID, id)
+	if err == nil {
+		err = checkHighSecurity(r, h.elevation, sec.Metadata)
+	}
+
+	h.audit.Log(r.Context(), audit.Event{
+		Action: "secrets.reveal", ActorID: userID, Target: id, Detail: errDetail(err),
+	})
+
+	if errors.Is(err, ErrStepUpRequired) {
+		httperr.Forbidden(w, r, "step-up authentication required")
+
+		return
+	}
+
+	if err != nil {
+		h.writeGetErr(w, r, err)
+
+		return
+	}
+
+	writeJSON(w, credentialResponse{
+		ID: sec.ID, Name: sec.Name, Login: sec.Login, Password: sec.Password,
+		Metadata: sec.Metadata, CreatedAt: sec.CreatedAt, UpdatedAt: sec.UpdatedAt,
+	})
+}
+
+func (h *CredentialHandler) writeGetErr(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, credentialsvc.ErrAccessDenied):
+		httperr.Forbidden(w, r, "access denied")
+	case errors.Is(err, credrepo.ErrNotFound):
+		httperr.NotFound(w, r, "secret not found")
+	default:
+		httperr.Internal(w, r)
+	}
+}
+
+type bulkCredentialItem struct {
+	Name     string       `json:"name"`
+	Login    string       `json:"login"`
+	Password string       `json:"password"`
+	Metadata metadata.Bag `json:"metadata,omitempty"`
+}
+
+type bulkResult struct {
+	Index int    `json:"index"`
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// Bulk handles POST /secrets/credentials/bulk, importing up to
+// credentialsvc.BulkMaxItems secrets in one request instead of one per
+// round trip.
+func (h *CredentialHandler) Bulk(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	var items []bulkCredentialItem
+	if err := json.NewDecoder(r.Body).Decode(&items); err != nil {
+		httperr.BadRequest(w, r, "invalid request body")
+
+		return
+	}
+
+	secrets := make([]*credential.Secret, len(items))
+
+	for i, item := range items {
+		loginCT, passwordCT, keyID, loginIndex, err := h.credentials.EncryptFields(item.Login, item.Password)
+		if err != nil {
+			httperr.Internal(w, r)
+
+			return
+		}
+
+		secrets[i] = &credential.Secret{
+			Name: item.Name, Login: loginCT, Password: passwordCT, KeyID: keyID,
+			LoginIndex: loginIndex, Metadata: item.Metadata,
+		}
+	}
+
+	results, err := h.credentials.BulkCreate(r.Context(), userID, secrets)
+	if err != nil {
+		if errors.Is(err, credentialsvc.ErrBulkTooLarge) {
+			httperr.BadRequest(w, r, "bulk request exceeds the maximum batch size")
+
+			return
+		}
+
+		if errors.Is(err, credentialsvc.ErrSecretLimitExceeded) {
+			httperr.UnprocessableEntity(w, r, "secret limit exceeded for this account")
+
+			return
+		}
+
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	resp := make([]bulkResult, len(results))
+	for i, r := range results {
+		resp[i] = bulkResult{Index: r.Index, ID: r.ID, Error: r.Error}
+	}
+
+	w.WriteHeader(http.StatusMultiStatus)
+	writeJSON(w, resp)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeJSONFields writes v as JSON, trimmed to the ?fields= query
+// parameter (see api.ParseFieldsParam/api.TrimFields) when the caller
+// supplied one, or unchanged otherwise.
+func writeJSONFields(w http.ResponseWriter, r *http.Request, v any) {
+	fields, ok := api.ParseFieldsParam(r)
+	if !ok {
+		writeJSON(w, v)
+
+		return
+	}
+
+	trimmed, err := api.TrimFields(v, fields)
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	writeJSON(w, trimmed)
+}
@@ -0,0 +1,513 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/andymarkow/gophkeeper/internal/api"
+	"github.com/andymarkow/gophkeeper/internal/api/httperr"
+	"github.com/andymarkow/gophkeeper/internal/bwlimit"
+	"github.com/andymarkow/gophkeeper/internal/objrepo"
+	"github.com/andymarkow/gophkeeper/internal/service/filesvc"
+)
+
+// defaultPreviewBytes is the number of decrypted bytes Preview returns when
+// the caller does not specify a bytes query parameter.
+const defaultPreviewBytes = 4096
+
+// maxPreviewBytes caps the bytes query parameter, so a preview request can't
+// be used to exfiltrate an entire large object a byte range at a time.
+const maxPreviewBytes = 1 << 20 // 1 MiB
+
+// FileHandler exposes file secret download over HTTP. A file marked
+// metadata.HighSecurityKey requires a valid elevation token (see
+// ElevateHandler) to download; without one, Download refuses the request
+// instead of streaming plaintext.
+type FileHandler struct {
+	files     *filesvc.Service
+	elevation elevationVerifier
+	// bw paces Upload/Download/DownloadVersion streaming, so one bulk
+	// transfer can't saturate the server or one user's share of it. A nil
+	// bw (from NewFileHandler's bw argument) disables throttling.
+	bw *bwlimit.Limiter
+}
+
+// NewFileHandler returns a FileHandler backed by files, gating
+// high-security downloads on elevation and pacing upload and download
+// streams against bw. Pass nil to disable bandwidth throttling.
+func NewFileHandler(files *filesvc.Service, elevation elevationVerifier, bw *bwlimit.Limiter) *FileHandler {
+	return &FileHandler{files: files, elevation: elevation, bw: bw}
+}
+
+// maxUploadMemoryBytes is how much of a multipart upload ParseMultipartForm
+// buffers in memory before spilling the rest to a temp file.
+const maxUploadMemoryBytes = 32 << 20 // 32 MiB
+
+// Upload handles POST /api/v1/files, accepting a multipart/form-data body
+// with a single "file" field, so browsers can upload without extra
+// client-side tooling.
+func (h *FileHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.UserFromContext(r.Context())
+	if !ok {
+		httperr.Unauthorized(w, r, "authentication required")
+
+		return
+	}
+
+	if err := r.ParseMultipartForm(maxUploadMemoryBytes); err != nil {
+		httperr.BadRequest(w, r, "invalid multipart form")
+
+		return
+	}
+
+	f, header, err := r.FormFile("file")
+	if err != nil {
+		httperr.BadRequest(w, r, "missing file field")
+
+		return
+	}
+	defer f.Close()
+
+	contentType := header.Header.Get("Content-Type")
+
+	throttled := h.bw.WrapReader(r.Context(), userID, f)
+
+	sec, err := h.files.Upload(r.Context(), userID, header.Filename, contentType, throttled, header.Size)
+	if errors.Is(err, filesvc.ErrContentTypeNotAllowed) {
+		httperr.UnsupportedMediaType(w, r, fmt.Sprintf("content type %q is not allowed", contentType))
+
+		return
+	}
+	if errors.Is(err, filesvc.ErrSecretLimitExceeded) {
+		httperr.UnprocessableEntity(w, r, "secret limit exceeded for this account")
+
+		return
+	}
+	if errors.Is(err, objrepo.ErrUnavailable) {
+		httperr.ObjectStorageUnavailable(w, r)
+
+		return
+	}
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Location", "/api/v1/secrets/files/"+url.PathEscape(sec.Name))
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(fileSecretResponse{ID: sec.ID, Name: sec.Name, CreatedAt: sec.CreatedAt, UpdatedAt: sec.UpdatedAt})
+}
+
+// StageUpload handles POST /api/v1/files/stage, returning a presigned URL
+// the client can PUT raw file bytes to directly in object storage, so large
+// uploads can bypass the app server's data path. The client must follow up
+// with CompleteUpload once the PUT succeeds.
+func (h *FileHandler) StageUpload(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.UserFromContext(r.Context())
+	if !ok {
+		httperr.Unauthorized(w, r, "authentication required")
+
+		return
+	}
+
+	url, stagingKey, err := h.files.StageUpload(r.Context(), userID)
+	if errors.Is(err, objrepo.ErrUnavailable) {
+		httperr.ObjectStorageUnavailable(w, r)
+
+		return
+	}
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	writeJSON(w, stageUploadResponse{URL: url, StagingKey: stagingKey})
+}
+
+// stageUploadResponse is the JSON body returned by StageUpload.
+type stageUploadResponse struct {
+	URL        string `json:"url"`
+	StagingKey string `json:"staging_key"`
+}
+
+// completeUploadRequest is the JSON body CompleteUpload expects.
+type completeUploadRequest struct {
+	StagingKey  string `json:"staging_key"`
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+}
+
+// CompleteUpload handles POST /api/v1/files/complete, finalizing a direct
+// upload previously staged via StageUpload: the staged bytes are encrypted
+// into a permanent object and a file secret record is created.
+func (h *FileHandler) CompleteUpload(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.UserFromContext(r.Context())
+	if !ok {
+		httperr.Unauthorized(w, r, "authentication required")
+
+		return
+	}
+
+	var req completeUploadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.BadRequest(w, r, "invalid request body")
+
+		return
+	}
+
+	sec, err := h.files.CompleteUpload(r.Context(), userID, req.StagingKey, req.Name, req.ContentType)
+	if errors.Is(err, filesvc.ErrAccessDenied) {
+		httperr.Forbidden(w, r, "access denied")
+
+		return
+	}
+	if errors.Is(err, filesvc.ErrContentTypeNotAllowed) {
+		httperr.UnsupportedMediaType(w, r, fmt.Sprintf("content type %q is not allowed", req.ContentType))
+
+		return
+	}
+	if errors.Is(err, objrepo.ErrUnavailable) {
+		httperr.ObjectStorageUnavailable(w, r)
+
+		return
+	}
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Location", "/api/v1/secrets/files/"+url.PathEscape(sec.Name))
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(fileSecretResponse{ID: sec.ID, Name: sec.Name, CreatedAt: sec.CreatedAt, UpdatedAt: sec.UpdatedAt})
+}
+
+// fileSecretResponse is the JSON representation of a file secret's metadata.
+type fileSecretResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Download handles GET /api/v1/files/{id}. It sets ETag to the stored
+// content checksum and responds 304 Not Modified if If-None-Match already
+// names it, so sync clients can skip re-downloading unchanged content.
+func (h *FileHandler) Download(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.UserFromContext(r.Context())
+	if !ok {
+		httperr.Unauthorized(w, r, "authentication required")
+
+		return
+	}
+
+	rc, sec, err := h.files.Download(r.Context(), userID, chi.URLParam(r, "id"))
+	if errors.Is(err, filesvc.ErrAccessDenied) {
+		httperr.Forbidden(w, r, "access denied")
+
+		return
+	}
+	if errors.Is(err, objrepo.ErrUnavailable) {
+		httperr.ObjectStorageUnavailable(w, r)
+
+		return
+	}
+	if err != nil {
+		httperr.NotFound(w, r, "file not found")
+
+		return
+	}
+	defer rc.Close()
+
+	if err := checkHighSecurity(r, h.elevation, sec.Metadata); err != nil {
+		httperr.Forbidden(w, r, "step-up authentication required")
+
+		return
+	}
+
+	etag := api.ETag(sec.Content.Checksum.String())
+	w.Header().Set("ETag", etag)
+
+	if api.IfNoneMatchHit(r, etag) {
+		w.WriteHeader(http.StatusNotModified)
+
+		return
+	}
+
+	w.Header().Set("Content-Disposition", contentDisposition(sec.Name))
+	w.Header().Set("Content-Type", "application/octet-stream")
+
+	_, _ = io.Copy(h.bw.WrapWriter(r.Context(), userID, w), rc)
+}
+
+type manifestChunk struct {
+	Offset   int64  `json:"offset"`
+	Size     int64  `json:"size"`
+	Checksum string `json:"checksum"`
+}
+
+type manifestResponse struct {
+	Size      int64           `json:"size"`
+	ChunkSize int64           `json:"chunk_size"`
+	Chunks    []manifestChunk `json:"chunks"`
+}
+
+// Manifest handles GET /api/v1/files/{id}/manifest, returning the decrypted
+// content's chunk offsets and checksums so a client resuming an interrupted
+// download can verify which locally-held chunks are still intact. Download
+// does not honor Range requests; it always streams the whole content.
+func (h *FileHandler) Manifest(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.UserFromContext(r.Context())
+	if !ok {
+		httperr.Unauthorized(w, r, "authentication required")
+
+		return
+	}
+
+	chunks, sec, err := h.files.Manifest(r.Context(), userID, chi.URLParam(r, "id"))
+	if errors.Is(err, filesvc.ErrAccessDenied) {
+		httperr.Forbidden(w, r, "access denied")
+
+		return
+	}
+	if errors.Is(err, objrepo.ErrUnavailable) {
+		httperr.ObjectStorageUnavailable(w, r)
+
+		return
+	}
+	if err != nil {
+		httperr.NotFound(w, r, "file not found")
+
+		return
+	}
+
+	resp := manifestResponse{
+		Size:      sec.Content.Size,
+		ChunkSize: filesvc.ManifestChunkSize,
+		Chunks:    make([]manifestChunk, len(chunks)),
+	}
+
+	for i, c := range chunks {
+		resp.Chunks[i] = manifestChunk{Offset: c.Offset, Size: c.Size, Checksum: c.Checksum.String()}
+	}
+
+	writeJSON(w, resp)
+}
+
+// DownloadVersion handles GET /api/v1/files/{id}/versions/{version},
+// downloading a retained prior version of the secret's content (see
+// filesvc.Service.Replace), identified by its 0-based index, oldest first.
+// Like Download, it sets ETag and honors If-None-Match.
+func (h *FileHandler) DownloadVersion(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.UserFromContext(r.Context())
+	if !ok {
+		httperr.Unauthorized(w, r, "authentication required")
+
+		return
+	}
+
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil {
+		httperr.BadRequest(w, r, "invalid version")
+
+		return
+	}
+
+	rc, sec, err := h.files.DownloadVersion(r.Context(), userID, chi.URLParam(r, "id"), version)
+	if errors.Is(err, filesvc.ErrAccessDenied) {
+		httperr.Forbidden(w, r, "access denied")
+
+		return
+	}
+	if errors.Is(err, filesvc.ErrVersionNotFound) {
+		httperr.NotFound(w, r, "version not found")
+
+		return
+	}
+	if errors.Is(err, objrepo.ErrUnavailable) {
+		httperr.ObjectStorageUnavailable(w, r)
+
+		return
+	}
+	if err != nil {
+		httperr.NotFound(w, r, "file not found")
+
+		return
+	}
+	defer rc.Close()
+
+	etag := api.ETag(sec.Versions[version].Checksum.String())
+	w.Header().Set("ETag", etag)
+
+	if api.IfNoneMatchHit(r, etag) {
+		w.WriteHeader(http.StatusNotModified)
+
+		return
+	}
+
+	w.Header().Set("Content-Disposition", contentDisposition(sec.Name))
+	w.Header().Set("Content-Type", "application/octet-stream")
+
+	_, _ = io.Copy(h.bw.WrapWriter(r.Context(), userID, w), rc)
+}
+
+// Preview handles GET /api/v1/files/{id}/preview?bytes=N, streaming only the
+// first N decrypted bytes of the secret's content so clients can render a
+// text/image preview without downloading the whole object.
+func (h *FileHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.UserFromContext(r.Context())
+	if !ok {
+		httperr.Unauthorized(w, r, "authentication required")
+
+		return
+	}
+
+	n := int64(defaultPreviewBytes)
+
+	if raw := r.URL.Query().Get("bytes"); raw != "" {
+		parsed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || parsed <= 0 {
+			httperr.BadRequest(w, r, "bytes must be a positive integer")
+
+			return
+		}
+
+		n = parsed
+	}
+
+	if n > maxPreviewBytes {
+		n = maxPreviewBytes
+	}
+
+	rc, sec, err := h.files.Preview(r.Context(), userID, chi.URLParam(r, "id"), n)
+	if errors.Is(err, filesvc.ErrAccessDenied) {
+		httperr.Forbidden(w, r, "access denied")
+
+		return
+	}
+	if errors.Is(err, objrepo.ErrUnavailable) {
+		httperr.ObjectStorageUnavailable(w, r)
+
+		return
+	}
+	if err != nil {
+		httperr.NotFound(w, r, "file not found")
+
+		return
+	}
+	defer rc.Close()
+
+	w.Header().Set("Content-Disposition", contentDisposition(sec.Name))
+	w.Header().Set("Content-Type", "application/octet-stream")
+
+	_, _ = io.Copy(w, rc)
+}
+
+// Thumbnail handles GET /api/v1/files/{id}/thumbnail, returning the
+// secret's JPEG thumbnail generated at upload time. It responds 404 if the
+// secret has none.
+func (h *FileHandler) Thumbnail(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.UserFromContext(r.Context())
+	if !ok {
+		httperr.Unauthorized(w, r, "authentication required")
+
+		return
+	}
+
+	rc, _, err := h.files.Thumbnail(r.Context(), userID, chi.URLParam(r, "id"))
+	if errors.Is(err, filesvc.ErrAccessDenied) {
+		httperr.Forbidden(w, r, "access denied")
+
+		return
+	}
+	if errors.Is(err, filesvc.ErrNoThumbnail) {
+		httperr.NotFound(w, r, "no thumbnail available")
+
+		return
+	}
+	if errors.Is(err, objrepo.ErrUnavailable) {
+		httperr.ObjectStorageUnavailable(w, r)
+
+		return
+	}
+	if err != nil {
+		httperr.NotFound(w, r, "file not found")
+
+		return
+	}
+	defer rc.Close()
+
+	w.Header().Set("Content-Type", "image/jpeg")
+
+	_, _ = io.Copy(w, rc)
+}
+
+// contentDisposition builds a Content-Disposition header value for name,
+// encoding it per RFC 6266/5987 so control characters, quotes and non-ASCII
+// bytes in a user-supplied name cannot break out of the header or corrupt
+// the download's suggested file name.
+func contentDisposition(name string) string {
+	return fmt.Sprintf(`attachment; filename=%q; filename*=UTF-8''%s`,
+		toASCIIFallback(name), percentEncodeRFC5987(name))
+}
+
+// percentEncodeRFC5987 percent-encodes name for use as an RFC 5987
+// ext-value, leaving only unreserved characters unescaped.
+func percentEncodeRFC5987(name string) string {
+	const hex = "0123456789ABCDEF"
+
+	var b strings.Builder
+
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9',
+			c == '-', c == '.', c == '_', c == '~':
+			b.WriteByte(c)
+		default:
+			b.WriteByte('%')
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&0x0f])
+		}
+	}
+
+	return b.String()
+}
+
+// toASCIIFallback strips anything outside a safe, quotable ASCII subset for
+// the legacy `filename` parameter, leaving the RFC 5987 `filename*`
+// parameter to carry the full name for clients that support it.
+func toASCIIFallback(name string) string {
+	var b strings.Builder
+
+	for _, r := range name {
+		switch {
+		case r == '"' || r == '\\' || r < 0x20 || r == 0x7f:
+			b.WriteByte('_')
+		case r > 0x7e:
+			b.WriteByte('_')
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	if b.Len() == 0 {
+		return "download"
+	}
+
+	return b.String()
+}
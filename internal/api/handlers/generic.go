@@ -0,0 +1,317 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/andymarkow/gophkeeper/internal/api"
+	"github.com/andymarkow/gophkeeper/internal/api/httperr"
+	"github.com/andymarkow/gophkeeper/internal/api/validate"
+	"github.com/andymarkow/gophkeeper/internal/domain/generic"
+	"github.com/andymarkow/gophkeeper/internal/domain/metadata"
+	"github.com/andymarkow/gophkeeper/internal/repository/genericrepo"
+	"github.com/andymarkow/gophkeeper/internal/service/genericsvc"
+)
+
+// maxGenericNameLen caps how long a generic secret's Name may be.
+const maxGenericNameLen = 255
+
+// validateGenericRequest checks req's fields, returning one FieldError per
+// problem found.
+func validateGenericRequest(req genericRequest) validate.Errors {
+	var verrs validate.Errors
+
+	verrs.Required("name", req.Name)
+	verrs.MaxLen("name", req.Name, maxGenericNameLen)
+
+	if len(req.Payload) == 0 {
+		verrs.Add("payload", validate.CodeRequired, "payload is required")
+	}
+
+	return verrs
+}
+
+// genericStore is the generic secret CRUD surface GenericHandler depends on.
+type genericStore interface {
+	EncryptPayload(payload map[string]any) (ciphertext []byte, keyID string, err error)
+	DecryptPayload(sec *generic.Secret) (map[string]any, error)
+	Create(ctx context.Context, userID string, sec *generic.Secret) error
+	Get(ctx context.Context, userID, id string) (*generic.Secret, error)
+	Update(ctx context.Context, userID string, sec *generic.Secret) error
+	Delete(ctx context.Context, userID, id string) error
+	List(ctx context.Context, userID string) ([]*generic.Secret, error)
+}
+
+// GenericHandler exposes full CRUD over /secrets/generic for arbitrary
+// key/value payloads with a user-declared schema, encrypting/decrypting the
+// payload on the way in and out so handlers never see a raw keyring. A
+// secret marked metadata.HighSecurityKey requires a valid elevation token
+// (see ElevateHandler) to read its Data; without one, Get returns metadata
+// only.
+type GenericHandler struct {
+	secrets   genericStore
+	elevation elevationVerifier
+}
+
+// NewGenericHandler returns a GenericHandler backed by secrets, gating
+// high-security reads on elevation.
+func NewGenericHandler(secrets genericStore, elevation elevationVerifier) *GenericHandler {
+	return &GenericHandler{secrets: secrets, elevation: elevation}
+}
+
+type genericFieldSpec struct {
+	Key  string `json:"key"`
+	Type string `json:"type"`
+}
+
+type genericRequest struct {
+	Name     string             `json:"name"`
+	Schema   []genericFieldSpec `json:"schema,omitempty"`
+	Payload  map[string]any     `json:"payload"`
+	Metadata metadata.Bag       `json:"metadata,omitempty"`
+}
+
+type genericResponse struct {
+	ID        string             `json:"id"`
+	Name      string             `json:"name"`
+	Schema    []genericFieldSpec `json:"schema,omitempty"`
+	Payload   map[string]any     `json:"payload"`
+	Metadata  metadata.Bag       `json:"metadata,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// Create handles POST /secrets/generic, responding 201 with a Location
+// header pointing at the new secret.
+func (h *GenericHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	var req genericRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.BadRequest(w, r, "invalid request body")
+
+		return
+	}
+
+	if verrs := validateGenericRequest(req); verrs.HasErrors() {
+		httperr.ValidationFailed(w, r, verrs)
+
+		return
+	}
+
+	ciphertext, keyID, err := h.secrets.EncryptPayload(req.Payload)
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	id, err := randomID()
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	sec := &generic.Secret{
+		ID:       id,
+		Name:     req.Name,
+		Schema:   toDomainSchema(req.Schema),
+		Payload:  ciphertext,
+		KeyID:    keyID,
+		Metadata: req.Metadata,
+	}
+
+	if err := h.secrets.Create(r.Context(), userID, sec); err != nil {
+		if errors.Is(err, genericsvc.ErrSecretLimitExceeded) {
+			httperr.UnprocessableEntity(w, r, "secret limit exceeded for this account")
+
+			return
+		}
+
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	w.Header().Set("Location", "/api/v1/secrets/generic/"+url.PathEscape(sec.Name))
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, toGenericResponse(sec, req.Payload))
+}
+
+// Get handles GET /secrets/generic/{id}.
+func (h *GenericHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	sec, err := h.secrets.Get(r.Context(), userID, chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeGetErr(w, r, err)
+
+		return
+	}
+
+	if metadata.IsHighSecurity(sec.Metadata) && !isElevated(r, h.elevation) {
+		writeJSONFields(w, r, toGenericResponse(sec, nil))
+
+		return
+	}
+
+	payload, err := h.secrets.DecryptPayload(sec)
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	writeJSONFields(w, r, toGenericResponse(sec, payload))
+}
+
+// Update handles PUT /secrets/generic/{id}.
+func (h *GenericHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+	id := chi.URLParam(r, "id")
+
+	var req genericRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.BadRequest(w, r, "invalid request body")
+
+		return
+	}
+
+	if verrs := validateGenericRequest(req); verrs.HasErrors() {
+		httperr.ValidationFailed(w, r, verrs)
+
+		return
+	}
+
+	ciphertext, keyID, err := h.secrets.EncryptPayload(req.Payload)
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	sec := &generic.Secret{
+		ID:       id,
+		Name:     req.Name,
+		Schema:   toDomainSchema(req.Schema),
+		Payload:  ciphertext,
+		KeyID:    keyID,
+		Metadata: req.Metadata,
+	}
+
+	if err := h.secrets.Update(r.Context(), userID, sec); err != nil {
+		h.writeGetErr(w, r, err)
+
+		return
+	}
+
+	writeJSON(w, toGenericResponse(sec, req.Payload))
+}
+
+// Delete handles DELETE /secrets/generic/{id}.
+func (h *GenericHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	if err := h.secrets.Delete(r.Context(), userID, chi.URLParam(r, "id")); err != nil {
+		h.writeGetErr(w, r, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// List handles GET /secrets/generic. Payloads are not decrypted in bulk;
+// fetch each secret individually via Get. Supports ?selector= and
+// ?metadata.<key>=<value> filtering on metadata (see parseSelectorParam).
+func (h *GenericHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	sel, ok := parseSelectorParam(w, r)
+	if !ok {
+		return
+	}
+
+	secrets, err := h.secrets.List(r.Context(), userID)
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	resp := make([]genericResponse, 0, len(secrets))
+	for _, sec := range secrets {
+		if !sel.Matches(sec.Metadata) {
+			continue
+		}
+
+		resp = append(resp, toGenericResponse(sec, nil))
+	}
+
+	writeJSONFields(w, r, api.NewListEnvelope(r, resp, ""))
+}
+
+func (h *GenericHandler) writeGetErr(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, genericsvc.ErrAccessDenied):
+		httperr.Forbidden(w, r, "access denied")
+	case errors.Is(err, genericrepo.ErrNotFound):
+		httperr.NotFound(w, r, "secret not found")
+	default:
+		httperr.Internal(w, r)
+	}
+}
+
+func toDomainSchema(in []genericFieldSpec) []generic.FieldSpec {
+	if in == nil {
+		return nil
+	}
+
+	out := make([]generic.FieldSpec, len(in))
+	for i, f := range in {
+		out[i] = generic.FieldSpec{Key: f.Key, Type: f.Type}
+	}
+
+	return out
+}
+
+func toResponseSchema(in []generic.FieldSpec) []genericFieldSpec {
+	if in == nil {
+		return nil
+	}
+
+	out := make([]genericFieldSpec, len(in))
+	for i, f := range in {
+		out[i] = genericFieldSpec{Key: f.Key, Type: f.Type}
+	}
+
+	return out
+}
+
+func toGenericResponse(sec *generic.Secret, payload map[string]any) genericResponse {
+	return genericResponse{
+		ID: sec.ID, Name: sec.Name, Schema: toResponseSchema(sec.Schema), Payload: payload,
+		Metadata: sec.Metadata, CreatedAt: sec.CreatedAt, UpdatedAt: sec.UpdatedAt,
+	}
+}
+
+// randomID returns a random 16-byte hex-encoded identifier for a new
+// secret.
+func randomID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate id: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
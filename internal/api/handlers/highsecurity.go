@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/andymarkow/gophkeeper/internal/api"
+	"github.com/andymarkow/gophkeeper/internal/api/httperr"
+	"github.com/andymarkow/gophkeeper/internal/repository/bankcardrepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/credrepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/filerepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/genericrepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/textrepo"
+	"github.com/andymarkow/gophkeeper/internal/service/bankcardsvc"
+	"github.com/andymarkow/gophkeeper/internal/service/credentialsvc"
+	"github.com/andymarkow/gophkeeper/internal/service/filesvc"
+	"github.com/andymarkow/gophkeeper/internal/service/genericsvc"
+	"github.com/andymarkow/gophkeeper/internal/service/textsvc"
+)
+
+// highSecuritySetter flips the metadata.HighSecurityKey flag on a single
+// secret, the subset of each secret service's surface HighSecurityHandler
+// depends on.
+type highSecuritySetter interface {
+	SetHighSecurity(ctx context.Context, userID, id string, enabled bool) error
+}
+
+// HighSecurityHandler marks or unmarks a secret as high security across
+// every secret type, so reveal/download paths (see checkHighSecurity) can
+// require a fresh elevation token before returning plaintext. Changing the
+// flag is itself gated on elevation, so a caller holding only a stolen
+// bearer token cannot unmark a secret and then reveal it.
+type HighSecurityHandler struct {
+	bankCards   highSecuritySetter
+	credentials highSecuritySetter
+	generic     highSecuritySetter
+	texts       highSecuritySetter
+	files       highSecuritySetter
+	elevation   elevationVerifier
+}
+
+// NewHighSecurityHandler returns a HighSecurityHandler backed by one setter
+// per secret type, gating changes on elevation.
+func NewHighSecurityHandler(
+	bankCards *bankcardsvc.Service,
+	credentials *credentialsvc.Service,
+	generic *genericsvc.Service,
+	texts *textsvc.Service,
+	files *filesvc.Service,
+	elevation elevationVerifier,
+) *HighSecurityHandler {
+	return &HighSecurityHandler{
+		bankCards: bankCards, credentials: credentials, generic: generic,
+		texts: texts, files: files, elevation: elevation,
+	}
+}
+
+type setHighSecurityRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// Set handles POST /secrets/{type}/{id}/high-security, requiring a valid
+// elevation token regardless of the secret's current flag state, so the
+// same step-up proof needed to reveal a high-security secret is also needed
+// to toggle the flag that guards it.
+func (h *HighSecurityHandler) Set(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+	id := chi.URLParam(r, "id")
+
+	if !isElevated(r, h.elevation) {
+		httperr.Forbidden(w, r, "step-up authentication required")
+
+		return
+	}
+
+	var req setHighSecurityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.BadRequest(w, r, "invalid request body")
+
+		return
+	}
+
+	var setter highSecuritySetter
+
+	switch chi.URLParam(r, "type") {
+	case "bankcards":
+		setter = h.bankCards
+	case "credentials":
+		setter = h.credentials
+	case "generic":
+		setter = h.generic
+	case "texts":
+		setter = h.texts
+	case "files":
+		setter = h.files
+	default:
+		httperr.BadRequest(w, r, "unknown secret type")
+
+		return
+	}
+
+	if err := setter.SetHighSecurity(r.Context(), userID, id, req.Enabled); err != nil {
+		switch {
+		case errors.Is(err, bankcardsvc.ErrAccessDenied), errors.Is(err, credentialsvc.ErrAccessDenied),
+			errors.Is(err, genericsvc.ErrAccessDenied), errors.Is(err, textsvc.ErrAccessDenied),
+			errors.Is(err, filesvc.ErrAccessDenied):
+			httperr.Forbidden(w, r, "access denied")
+		case errors.Is(err, bankcardrepo.ErrNotFound), errors.Is(err, credrepo.ErrNotFound),
+			errors.Is(err, genericrepo.ErrNotFound), errors.Is(err, textrepo.ErrNotFound),
+			errors.Is(err, filerepo.ErrNotFound):
+			httperr.NotFound(w, r, "secret not found")
+		default:
+			httperr.Internal(w, r)
+		}
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
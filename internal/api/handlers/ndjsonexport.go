@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/andymarkow/gophkeeper/internal/api"
+)
+
+// ndjsonExporter streams every registered secret type's records for a user
+// as newline-delimited JSON.
+type ndjsonExporter interface {
+	Export(ctx context.Context, userID string, includeData bool, w io.Writer) error
+}
+
+// NDJSONExportHandler exposes a whole-vault streaming export, one JSON
+// object per line, for piping into backup tooling.
+type NDJSONExportHandler struct {
+	export ndjsonExporter
+}
+
+// NewNDJSONExportHandler returns an NDJSONExportHandler backed by export.
+func NewNDJSONExportHandler(export ndjsonExporter) *NDJSONExportHandler {
+	return &NDJSONExportHandler{export: export}
+}
+
+// Export handles GET /api/v1/export/ndjson. Passing ?data=true decrypts and
+// includes each secret's data inline instead of metadata alone.
+func (h *NDJSONExportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+	includeData := r.URL.Query().Get("data") == "true"
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+
+	// Headers and any prior lines may already be flushed by the time an
+	// error occurs partway through, so there's no well-formed error
+	// response left to send; a half-written NDJSON stream is still valid
+	// line-by-line for whatever was sent before the error.
+	_ = h.export.Export(r.Context(), userID, includeData, w)
+}
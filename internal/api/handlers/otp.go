@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/andymarkow/gophkeeper/internal/api"
+	"github.com/andymarkow/gophkeeper/internal/api/httperr"
+	"github.com/andymarkow/gophkeeper/internal/api/validate"
+	"github.com/andymarkow/gophkeeper/internal/domain/metadata"
+	"github.com/andymarkow/gophkeeper/internal/domain/otp"
+	"github.com/andymarkow/gophkeeper/internal/repository/otprepo"
+	"github.com/andymarkow/gophkeeper/internal/service/otpsvc"
+)
+
+// maxOTPNameLen caps how long an OTP secret's Name may be.
+const maxOTPNameLen = 255
+
+// validateOTPRequest checks req's fields, returning one FieldError per
+// problem found. Seed is required on both Create and Update; there is no
+// partial update that leaves it unchanged.
+func validateOTPRequest(req otpRequest) validate.Errors {
+	var verrs validate.Errors
+
+	verrs.Required("name", req.Name)
+	verrs.MaxLen("name", req.Name, maxOTPNameLen)
+	verrs.Required("seed", req.Seed)
+
+	return verrs
+}
+
+// otpStore is the TOTP secret CRUD surface OTPHandler depends on, plus
+// server-side code generation.
+type otpStore interface {
+	List(ctx context.Context, userID string) ([]*otp.Secret, error)
+	GenerateCode(ctx context.Context, userID, id string) (code string, validUntil time.Time, err error)
+	EncryptSeed(seedBase32 string) (ciphertext []byte, keyID string, err error)
+	Create(ctx context.Context, userID string, sec *otp.Secret) error
+	Get(ctx context.Context, userID, id string) (*otp.Secret, error)
+	Update(ctx context.Context, userID string, sec *otp.Secret) error
+	Delete(ctx context.Context, userID, id string) error
+}
+
+// OTPHandler exposes full CRUD over /secrets/otp plus server-side code
+// generation, so a client never needs to implement RFC 6238 itself. The
+// seed is never included in any response, including Get; use GenerateCode
+// to obtain a code instead of exporting the seed to clients.
+type OTPHandler struct {
+	otps otpStore
+}
+
+// NewOTPHandler returns an OTPHandler backed by otps.
+func NewOTPHandler(otps otpStore) *OTPHandler {
+	return &OTPHandler{otps: otps}
+}
+
+type otpRequest struct {
+	Name        string       `json:"name"`
+	Issuer      string       `json:"issuer,omitempty"`
+	AccountName string       `json:"account_name,omitempty"`
+	Seed        string       `json:"seed"`
+	Algorithm   string       `json:"algorithm,omitempty"`
+	Digits      int          `json:"digits,omitempty"`
+	Period      int          `json:"period_seconds,omitempty"`
+	Metadata    metadata.Bag `json:"metadata,omitempty"`
+}
+
+type otpResponse struct {
+	ID          string       `json:"id"`
+	Name        string       `json:"name"`
+	Issuer      string       `json:"issuer,omitempty"`
+	AccountName string       `json:"account_name,omitempty"`
+	Algorithm   string       `json:"algorithm,omitempty"`
+	Digits      int          `json:"digits,omitempty"`
+	Period      int          `json:"period_seconds,omitempty"`
+	Metadata    metadata.Bag `json:"metadata,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// Create handles POST /secrets/otp, responding 201 with a Location header
+// pointing at the new secret. The seed is accepted base32-encoded, as
+// presented in an otpauth:// URI.
+func (h *OTPHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	var req otpRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.BadRequest(w, r, "invalid request body")
+
+		return
+	}
+
+	if verrs := validateOTPRequest(req); verrs.HasErrors() {
+		httperr.ValidationFailed(w, r, verrs)
+
+		return
+	}
+
+	ciphertext, keyID, err := h.otps.EncryptSeed(req.Seed)
+	if err != nil {
+		httperr.BadRequest(w, r, "invalid seed")
+
+		return
+	}
+
+	id, err := randomID()
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	sec := &otp.Secret{
+		ID: id, Name: req.Name, Issuer: req.Issuer, AccountName: req.AccountName, Seed: ciphertext,
+		KeyID: keyID, Algorithm: req.Algorithm, Digits: req.Digits,
+		Period: time.Duration(req.Period) * time.Second, Metadata: req.Metadata,
+	}
+
+	if err := h.otps.Create(r.Context(), userID, sec); err != nil {
+		if errors.Is(err, otpsvc.ErrSecretLimitExceeded) {
+			httperr.UnprocessableEntity(w, r, "secret limit exceeded for this account")
+
+			return
+		}
+
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	w.Header().Set("Location", "/api/v1/secrets/otp/"+url.PathEscape(sec.Name))
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, toOTPResponse(sec))
+}
+
+// Get handles GET /secrets/otp/{id}.
+func (h *OTPHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	sec, err := h.otps.Get(r.Context(), userID, chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeGetErr(w, r, err)
+
+		return
+	}
+
+	writeJSONFields(w, r, toOTPResponse(sec))
+}
+
+// Update handles PUT /secrets/otp/{id}.
+func (h *OTPHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+	id := chi.URLParam(r, "id")
+
+	var req otpRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.BadRequest(w, r, "invalid request body")
+
+		return
+	}
+
+	if verrs := validateOTPRequest(req); verrs.HasErrors() {
+		httperr.ValidationFailed(w, r, verrs)
+
+		return
+	}
+
+	ciphertext, keyID, err := h.otps.EncryptSeed(req.Seed)
+	if err != nil {
+		httperr.BadRequest(w, r, "invalid seed")
+
+		return
+	}
+
+	sec := &otp.Secret{
+		ID: id, Name: req.Name, Issuer: req.Issuer, AccountName: req.AccountName, Seed: ciphertext,
+		KeyID: keyID, Algorithm: req.Algorithm, Digits: req.Digits,
+		Period: time.Duration(req.Period) * time.Second, Metadata: req.Metadata,
+	}
+
+	if err := h.otps.Update(r.Context(), userID, sec); err != nil {
+		h.writeGetErr(w, r, err)
+
+		return
+	}
+
+	writeJSON(w, toOTPResponse(sec))
+}
+
+// Delete handles DELETE /secrets/otp/{id}.
+func (h *OTPHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	if err := h.otps.Delete(r.Context(), userID, chi.URLParam(r, "id")); err != nil {
+		h.writeGetErr(w, r, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// List handles GET /otp. The seed is never included in the response; use
+// GenerateCode to obtain a code instead of exporting the seed to clients.
+// Supports ?selector= and ?metadata.<key>=<value> filtering on metadata
+// (see parseSelectorParam).
+func (h *OTPHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	sel, ok := parseSelectorParam(w, r)
+	if !ok {
+		return
+	}
+
+	secrets, err := h.otps.List(r.Context(), userID)
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	resp := make([]otpResponse, 0, len(secrets))
+	for _, sec := range secrets {
+		if !sel.Matches(sec.Metadata) {
+			continue
+		}
+
+		resp = append(resp, toOTPResponse(sec))
+	}
+
+	writeJSONFields(w, r, api.NewListEnvelope(r, resp, ""))
+}
+
+type generateCodeResponse struct {
+	Code       string    `json:"code"`
+	ValidUntil time.Time `json:"valid_until"`
+}
+
+// GenerateCode handles GET /otp/{id}/code, returning the current TOTP code
+// for the secret and the instant it stops being valid.
+func (h *OTPHandler) GenerateCode(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+	id := chi.URLParam(r, "id")
+
+	code, validUntil, err := h.otps.GenerateCode(r.Context(), userID, id)
+	if err != nil {
+		if errors.Is(err, otpsvc.ErrAccessDenied) {
+			httperr.Forbidden(w, r, "access denied")
+
+			return
+		}
+
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	writeJSON(w, generateCodeResponse{Code: code, ValidUntil: validUntil})
+}
+
+func (h *OTPHandler) writeGetErr(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, otpsvc.ErrAccessDenied):
+		httperr.Forbidden(w, r, "access denied")
+	case errors.Is(err, otprepo.ErrNotFound):
+		httperr.NotFound(w, r, "secret not found")
+	default:
+		httperr.Internal(w, r)
+	}
+}
+
+func toOTPResponse(sec *otp.Secret) otpResponse {
+	return otpResponse{
+		ID: sec.ID, Name: sec.Name, Issuer: sec.Issuer, AccountName: sec.AccountName,
+		Algorithm: sec.Algorithm, Digits: sec.Digits, Period: int(sec.Period.Seconds()),
+		Metadata: sec.Metadata, CreatedAt: sec.CreatedAt, UpdatedAt: sec.UpdatedAt,
+	}
+}
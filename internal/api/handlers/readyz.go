@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/andymarkow/gophkeeper/internal/startup"
+)
+
+// ReadyzHandler reports whether the server's database pools are healthy,
+// including their current pool statistics so an operator can correlate a
+// readiness dip with connection-pool exhaustion, and whether any
+// lazily-initialized dependency (see internal/startup.LazyInit) has
+// finished coming up yet.
+type ReadyzHandler struct {
+	// pools maps a descriptive name (e.g. "users", "blobs") to the pool
+	// backing that repository, so a deployment with one pool per
+	// repository (or one shared pool under several names) reports each.
+	pools map[string]*pgxpool.Pool
+	// deps maps a descriptive name (e.g. "object_storage") to a
+	// dependency still finishing its lazy, retried startup probe.
+	deps map[string]*startup.LazyInit
+}
+
+// NewReadyzHandler returns a ReadyzHandler reporting stats for pools and
+// readiness for deps. Either may be nil.
+func NewReadyzHandler(pools map[string]*pgxpool.Pool, deps map[string]*startup.LazyInit) *ReadyzHandler {
+	return &ReadyzHandler{pools: pools, deps: deps}
+}
+
+type dbPoolStats struct {
+	AcquiredConns int32 `json:"acquired_conns"`
+	IdleConns     int32 `json:"idle_conns"`
+	MaxConns      int32 `json:"max_conns"`
+	TotalConns    int32 `json:"total_conns"`
+}
+
+type depStatus struct {
+	Ready bool   `json:"ready"`
+	Error string `json:"error,omitempty"`
+}
+
+type readyzResponse struct {
+	Status  string                 `json:"status"`
+	DBPools map[string]dbPoolStats `json:"db_pools,omitempty"`
+	Deps    map[string]depStatus   `json:"deps,omitempty"`
+}
+
+// Readyz handles GET /readyz, reporting each pool's statistics and each
+// lazily-initializing dependency's readiness. Status is "ok" once every
+// dependency is ready, "degraded" otherwise; it always responds 200 so a
+// degraded-but-serving instance isn't pulled out of rotation by a strict
+// readiness probe, only flagged for operators watching the payload.
+func (h *ReadyzHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	resp := readyzResponse{
+		Status:  "ok",
+		DBPools: make(map[string]dbPoolStats, len(h.pools)),
+		Deps:    make(map[string]depStatus, len(h.deps)),
+	}
+
+	for name, pool := range h.pools {
+		s := pool.Stat()
+
+		resp.DBPools[name] = dbPoolStats{
+			AcquiredConns: s.AcquiredConns(),
+			IdleConns:     s.IdleConns(),
+			MaxConns:      s.MaxConns(),
+			TotalConns:    s.TotalConns(),
+		}
+	}
+
+	for name, dep := range h.deps {
+		ready, err := dep.Ready()
+
+		status := depStatus{Ready: ready}
+		if err != nil {
+			status.Error = err.Error()
+		}
+
+		resp.Deps[name] = status
+
+		if !ready {
+			resp.Status = "degraded"
+		}
+	}
+
+	writeJSON(w, resp)
+}
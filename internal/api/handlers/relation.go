@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/andymarkow/gophkeeper/internal/api"
+	"github.com/andymarkow/gophkeeper/internal/api/httperr"
+	"github.com/andymarkow/gophkeeper/internal/api/validate"
+	"github.com/andymarkow/gophkeeper/internal/domain/relation"
+	"github.com/andymarkow/gophkeeper/internal/service/relationsvc"
+)
+
+// relationLinker links and resolves secret relations.
+type relationLinker interface {
+	Link(ctx context.Context, from, to relation.Ref) error
+	Unlink(ctx context.Context, from, to relation.Ref) error
+	Linked(ctx context.Context, ref relation.Ref) ([]relation.Ref, error)
+}
+
+// RelationHandler exposes secret relation management. It trusts the caller
+// to have already authorized access to both Refs; it does not look secrets
+// up itself since they live across several unrelated repositories.
+type RelationHandler struct {
+	relations relationLinker
+}
+
+// NewRelationHandler returns a RelationHandler backed by relations.
+func NewRelationHandler(relations relationLinker) *RelationHandler {
+	return &RelationHandler{relations: relations}
+}
+
+type relationRef struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+type relationRequest struct {
+	From relationRef `json:"from"`
+	To   relationRef `json:"to"`
+}
+
+// validateRelationRequest checks req's Refs are fully populated, returning
+// one FieldError per missing field.
+func validateRelationRequest(req relationRequest) validate.Errors {
+	var verrs validate.Errors
+
+	verrs.Required("from.type", req.From.Type)
+	verrs.Required("from.id", req.From.ID)
+	verrs.Required("to.type", req.To.Type)
+	verrs.Required("to.id", req.To.ID)
+
+	return verrs
+}
+
+// Link handles POST /relations.
+func (h *RelationHandler) Link(w http.ResponseWriter, r *http.Request) {
+	var req relationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.BadRequest(w, r, "invalid request body")
+
+		return
+	}
+
+	if verrs := validateRelationRequest(req); verrs.HasErrors() {
+		httperr.ValidationFailed(w, r, verrs)
+
+		return
+	}
+
+	from := relation.Ref{Type: req.From.Type, ID: req.From.ID}
+	to := relation.Ref{Type: req.To.Type, ID: req.To.ID}
+
+	if err := h.relations.Link(r.Context(), from, to); err != nil {
+		if errors.Is(err, relationsvc.ErrSelfLink) {
+			httperr.BadRequest(w, r, "cannot link a secret to itself")
+
+			return
+		}
+
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+// Unlink handles DELETE /relations.
+func (h *RelationHandler) Unlink(w http.ResponseWriter, r *http.Request) {
+	var req relationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.BadRequest(w, r, "invalid request body")
+
+		return
+	}
+
+	if verrs := validateRelationRequest(req); verrs.HasErrors() {
+		httperr.ValidationFailed(w, r, verrs)
+
+		return
+	}
+
+	from := relation.Ref{Type: req.From.Type, ID: req.From.ID}
+	to := relation.Ref{Type: req.To.Type, ID: req.To.ID}
+
+	if err := h.relations.Unlink(r.Context(), from, to); err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// List handles GET /relations?type=<type>&id=<id>, returning the Refs
+// linked to the given secret.
+func (h *RelationHandler) List(w http.ResponseWriter, r *http.Request) {
+	ref := relation.Ref{Type: r.URL.Query().Get("type"), ID: r.URL.Query().Get("id")}
+
+	linked, err := h.relations.Linked(r.Context(), ref)
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	resp := make([]relationRef, 0, len(linked))
+	for _, l := range linked {
+		resp = append(resp, relationRef{Type: l.Type, ID: l.ID})
+	}
+
+	writeJSONFields(w, r, api.NewListEnvelope(r, resp, ""))
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/andymarkow/gophkeeper/internal/api"
+	"github.com/andymarkow/gophkeeper/internal/api/httperr"
+	"github.com/andymarkow/gophkeeper/internal/api/validate"
+	"github.com/andymarkow/gophkeeper/internal/domain/reminder"
+	"github.com/andymarkow/gophkeeper/internal/repository/reminderrepo"
+	"github.com/andymarkow/gophkeeper/internal/service/remindersvc"
+)
+
+// reminderStore is the reminder rule CRUD surface ReminderHandler depends
+// on.
+type reminderStore interface {
+	Create(ctx context.Context, userID string, rule *reminder.Rule) error
+	Get(ctx context.Context, userID, id string) (*reminder.Rule, error)
+	Delete(ctx context.Context, userID, id string) error
+	List(ctx context.Context, userID string) ([]*reminder.Rule, error)
+}
+
+// ReminderHandler exposes CRUD over /reminders, for rules that notify a
+// user at an absolute date or N days before one of their secrets expires.
+type ReminderHandler struct {
+	rules reminderStore
+}
+
+// NewReminderHandler returns a ReminderHandler backed by rules.
+func NewReminderHandler(rules reminderStore) *ReminderHandler {
+	return &ReminderHandler{rules: rules}
+}
+
+type reminderRequest struct {
+	SecretType       string     `json:"secret_type"`
+	SecretID         string     `json:"secret_id"`
+	RemindAt         *time.Time `json:"remind_at,omitempty"`
+	DaysBeforeExpiry *int       `json:"days_before_expiry,omitempty"`
+}
+
+type reminderResponse struct {
+	ID               string     `json:"id"`
+	SecretType       string     `json:"secret_type"`
+	SecretID         string     `json:"secret_id"`
+	RemindAt         *time.Time `json:"remind_at,omitempty"`
+	DaysBeforeExpiry *int       `json:"days_before_expiry,omitempty"`
+	FiredAt          *time.Time `json:"fired_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// validateReminderRequest checks req's fields, returning one FieldError per
+// problem found. It does not check the RemindAt/DaysBeforeExpiry
+// mutual-exclusion rule, which remindersvc.Create enforces.
+func validateReminderRequest(req reminderRequest) validate.Errors {
+	var verrs validate.Errors
+
+	verrs.Required("secret_type", req.SecretType)
+	verrs.Required("secret_id", req.SecretID)
+
+	return verrs
+}
+
+// Create handles POST /reminders, responding 201 with the created rule.
+func (h *ReminderHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	var req reminderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.BadRequest(w, r, "invalid request body")
+
+		return
+	}
+
+	if verrs := validateReminderRequest(req); verrs.HasErrors() {
+		httperr.ValidationFailed(w, r, verrs)
+
+		return
+	}
+
+	id, err := randomID()
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	rule := &reminder.Rule{
+		ID:               id,
+		SecretType:       reminder.SecretType(req.SecretType),
+		SecretID:         req.SecretID,
+		RemindAt:         req.RemindAt,
+		DaysBeforeExpiry: req.DaysBeforeExpiry,
+	}
+
+	if err := h.rules.Create(r.Context(), userID, rule); err != nil {
+		if errors.Is(err, remindersvc.ErrInvalidRule) {
+			httperr.BadRequest(w, r, "exactly one of remind_at or days_before_expiry must be set")
+
+			return
+		}
+
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, toReminderResponse(rule))
+}
+
+// Get handles GET /reminders/{id}.
+func (h *ReminderHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	rule, err := h.rules.Get(r.Context(), userID, chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeGetErr(w, r, err)
+
+		return
+	}
+
+	writeJSON(w, toReminderResponse(rule))
+}
+
+// Delete handles DELETE /reminders/{id}.
+func (h *ReminderHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	if err := h.rules.Delete(r.Context(), userID, chi.URLParam(r, "id")); err != nil {
+		h.writeGetErr(w, r, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// List handles GET /reminders.
+func (h *ReminderHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	rules, err := h.rules.List(r.Context(), userID)
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	resp := make([]reminderResponse, 0, len(rules))
+	for _, rule := range rules {
+		resp = append(resp, toReminderResponse(rule))
+	}
+
+	writeJSONFields(w, r, api.NewListEnvelope(r, resp, ""))
+}
+
+func (h *ReminderHandler) writeGetErr(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, remindersvc.ErrAccessDenied):
+		httperr.Forbidden(w, r, "access denied")
+	case errors.Is(err, reminderrepo.ErrNotFound):
+		httperr.NotFound(w, r, "reminder rule not found")
+	default:
+		httperr.Internal(w, r)
+	}
+}
+
+func toReminderResponse(rule *reminder.Rule) reminderResponse {
+	return reminderResponse{
+		ID:               rule.ID,
+		SecretType:       string(rule.SecretType),
+		SecretID:         rule.SecretID,
+		RemindAt:         rule.RemindAt,
+		DaysBeforeExpiry: rule.DaysBeforeExpiry,
+		FiredAt:          rule.FiredAt,
+		CreatedAt:        rule.CreatedAt,
+		UpdatedAt:        rule.UpdatedAt,
+	}
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/andymarkow/gophkeeper/internal/api/httperr"
+	"github.com/andymarkow/gophkeeper/internal/service/scimsvc"
+)
+
+const scimUserSchema = "urn:ietf:params:scim:schemas:core:2.0:User"
+
+// scimResource is the wire shape of a SCIM User resource. Only the fields
+// gophkeeper maps onto an account are modeled; unrecognized fields sent by
+// the IdP are ignored.
+type scimResource struct {
+	Schemas  []string `json:"schemas"`
+	ID       string   `json:"id,omitempty"`
+	UserName string   `json:"userName"`
+	Active   bool     `json:"active"`
+	Emails   []struct {
+		Value   string `json:"value"`
+		Primary bool   `json:"primary"`
+	} `json:"emails,omitempty"`
+}
+
+// SCIMHandler implements the SCIM 2.0 Users surface IdPs use to provision
+// and deprovision accounts.
+type SCIMHandler struct {
+	scim *scimsvc.Service
+}
+
+// NewSCIMHandler returns a SCIMHandler backed by scim.
+func NewSCIMHandler(scim *scimsvc.Service) *SCIMHandler {
+	return &SCIMHandler{scim: scim}
+}
+
+// Create handles POST /scim/v2/Users.
+func (h *SCIMHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var res scimResource
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		httperr.BadRequest(w, r, "invalid SCIM resource")
+
+		return
+	}
+
+	u, err := h.scim.Create(r.Context(), scimsvc.User{
+		UserName: res.UserName,
+		Email:    primaryEmail(res),
+		Active:   res.Active,
+	})
+	if errors.Is(err, scimsvc.ErrConflict) {
+		httperr.Conflict(w, r, "userName already exists")
+
+		return
+	}
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	writeSCIMResource(w, http.StatusCreated, u)
+}
+
+// Get handles GET /scim/v2/Users/{id}.
+func (h *SCIMHandler) Get(w http.ResponseWriter, r *http.Request) {
+	u, err := h.scim.Get(r.Context(), chi.URLParam(r, "id"))
+	if errors.Is(err, scimsvc.ErrNotFound) {
+		httperr.NotFound(w, r, "user not found")
+
+		return
+	}
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	writeSCIMResource(w, http.StatusOK, u)
+}
+
+// Replace handles PUT /scim/v2/Users/{id}.
+func (h *SCIMHandler) Replace(w http.ResponseWriter, r *http.Request) {
+	var res scimResource
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		httperr.BadRequest(w, r, "invalid SCIM resource")
+
+		return
+	}
+
+	u, err := h.scim.Replace(r.Context(), chi.URLParam(r, "id"), scimsvc.User{
+		UserName: res.UserName,
+		Email:    primaryEmail(res),
+		Active:   res.Active,
+	})
+	if errors.Is(err, scimsvc.ErrNotFound) {
+		httperr.NotFound(w, r, "user not found")
+
+		return
+	}
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	writeSCIMResource(w, http.StatusOK, u)
+}
+
+// Delete handles DELETE /scim/v2/Users/{id}, deprovisioning (soft-disabling)
+// the account rather than deleting its data.
+func (h *SCIMHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	err := h.scim.Deprovision(r.Context(), chi.URLParam(r, "id"))
+	if errors.Is(err, scimsvc.ErrNotFound) {
+		httperr.NotFound(w, r, "user not found")
+
+		return
+	}
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func primaryEmail(res scimResource) string {
+	for _, e := range res.Emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+
+	if len(res.Emails) > 0 {
+		return res.Emails[0].Value
+	}
+
+	return ""
+}
+
+func writeSCIMResource(w http.ResponseWriter, status int, u scimsvc.User) {
+	w.Header().Set("Content-Type", "application/scim+json; charset=utf-8")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(scimResource{
+		Schemas:  []string{scimUserSchema},
+		ID:       u.ID,
+		UserName: u.UserName,
+		Active:   u.Active,
+		Emails: []struct {
+			Value   string `json:"value"`
+			Primary bool   `json:"primary"`
+		}{{Value: u.Email, Primary: true}},
+	})
+}
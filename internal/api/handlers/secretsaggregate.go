@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/andymarkow/gophkeeper/internal/api"
+	"github.com/andymarkow/gophkeeper/internal/api/httperr"
+	"github.com/andymarkow/gophkeeper/internal/domain/metadata"
+	"github.com/andymarkow/gophkeeper/internal/service/exportsvc"
+)
+
+// aggregateLister lists every registered secret type's records for a user
+// in one call.
+type aggregateLister interface {
+	ListAll(ctx context.Context, userID string, includeData bool) ([]exportsvc.Record, error)
+}
+
+// AggregateSecretsHandler exposes a merged listing across every secret
+// type, so a client can render a vault overview without issuing one list
+// call per type.
+type AggregateSecretsHandler struct {
+	secrets aggregateLister
+}
+
+// NewAggregateSecretsHandler returns an AggregateSecretsHandler backed by
+// secrets.
+func NewAggregateSecretsHandler(secrets aggregateLister) *AggregateSecretsHandler {
+	return &AggregateSecretsHandler{secrets: secrets}
+}
+
+type aggregateSecretResponse struct {
+	Type     string       `json:"type"`
+	ID       string       `json:"id"`
+	Name     string       `json:"name"`
+	Metadata metadata.Bag `json:"metadata,omitempty"`
+}
+
+// List handles GET /api/v1/secrets, returning every secret the caller owns
+// across all types with its type discriminator. It never decrypts data;
+// fetch a secret by its own endpoint for that.
+func (h *AggregateSecretsHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	records, err := h.secrets.ListAll(r.Context(), userID, false)
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	resp := make([]aggregateSecretResponse, len(records))
+	for i, rec := range records {
+		resp[i] = aggregateSecretResponse{Type: rec.Type, ID: rec.ID, Name: rec.Name, Metadata: rec.Metadata}
+	}
+
+	writeJSONFields(w, r, api.NewListEnvelope(r, resp, ""))
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/andymarkow/gophkeeper/internal/api"
+	"github.com/andymarkow/gophkeeper/internal/api/httperr"
+	"github.com/andymarkow/gophkeeper/internal/domain/metadata"
+	"github.com/andymarkow/gophkeeper/internal/service/exportsvc"
+)
+
+// secretSearcher searches a user's secret names and metadata across every
+// registered secret type.
+type secretSearcher interface {
+	Search(ctx context.Context, userID, query string) ([]exportsvc.Record, error)
+}
+
+// SecretSearchHandler exposes full-text search across a user's whole vault,
+// so a client can resolve one query to a secret without issuing a list call
+// per secret type first.
+type SecretSearchHandler struct {
+	search secretSearcher
+}
+
+// NewSecretSearchHandler returns a SecretSearchHandler backed by search.
+func NewSecretSearchHandler(search secretSearcher) *SecretSearchHandler {
+	return &SecretSearchHandler{search: search}
+}
+
+type secretSearchResult struct {
+	Type     string       `json:"type"`
+	ID       string       `json:"id"`
+	Name     string       `json:"name"`
+	Metadata metadata.Bag `json:"metadata,omitempty"`
+}
+
+// Search handles GET /api/v1/secrets/search?q=..., matching query
+// case-insensitively against each secret's name and metadata values.
+// Payloads are never decrypted: results carry just enough to let the
+// client fetch the matching secret by type and ID.
+func (h *SecretSearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+	query := r.URL.Query().Get("q")
+
+	records, err := h.search.Search(r.Context(), userID, query)
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	resp := make([]secretSearchResult, len(records))
+	for i, rec := range records {
+		resp[i] = secretSearchResult{Type: rec.Type, ID: rec.ID, Name: rec.Name, Metadata: rec.Metadata}
+	}
+
+	writeJSONFields(w, r, api.NewListEnvelope(r, resp, ""))
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/andymarkow/gophkeeper/internal/api/httperr"
+	"github.com/andymarkow/gophkeeper/internal/domain/metadata"
+)
+
+// metadataParamPrefix marks a query parameter as a metadata equality filter,
+// e.g. "?metadata.env=prod" requires metadata key "env" to equal "prod".
+const metadataParamPrefix = "metadata."
+
+// parseSelectorParam parses the ?selector= query parameter shared by list
+// endpoints that support filtering on metadata, e.g.
+// "?selector=env=prod,team!=infra", ANDing in an equality requirement for
+// every "?metadata.<key>=<value>" parameter present, a plainer alternative
+// for the common case of filtering on a single known key. It writes a 400
+// response and reports ok = false on a malformed ?selector= expression.
+func parseSelectorParam(w http.ResponseWriter, r *http.Request) (sel metadata.Selector, ok bool) {
+	sel, err := metadata.ParseSelector(r.URL.Query().Get("selector"))
+	if err != nil {
+		httperr.BadRequest(w, r, "invalid selector: "+err.Error())
+
+		return metadata.Selector{}, false
+	}
+
+	for param, values := range r.URL.Query() {
+		key, isMetadata := strings.CutPrefix(param, metadataParamPrefix)
+		if !isMetadata || key == "" || len(values) == 0 {
+			continue
+		}
+
+		sel = sel.AddEquals(key, values[0])
+	}
+
+	return sel, true
+}
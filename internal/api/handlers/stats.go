@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/andymarkow/gophkeeper/internal/api"
+	"github.com/andymarkow/gophkeeper/internal/api/httperr"
+)
+
+// StatsCounterFunc reports how many secrets of one type userID currently
+// owns.
+type StatsCounterFunc func(ctx context.Context, userID string) (int, error)
+
+// StatsHandler reports a user's current secret counts per type alongside
+// the configured per-type cap (see config.LimitsConfig.MaxSecretsPerType),
+// so a client can show usage before a create call is rejected.
+type StatsHandler struct {
+	counters   map[string]StatsCounterFunc
+	maxPerType int
+}
+
+// NewStatsHandler returns a StatsHandler backed by counters, keyed by
+// secret type name (e.g. "credential", "generic"). maxPerType is reported
+// alongside each count; 0 means the deployment has no cap configured.
+func NewStatsHandler(counters map[string]StatsCounterFunc, maxPerType int) *StatsHandler {
+	return &StatsHandler{counters: counters, maxPerType: maxPerType}
+}
+
+// statsTypeUsage reports one secret type's current usage.
+type statsTypeUsage struct {
+	Count int `json:"count"`
+	Max   int `json:"max,omitempty"`
+}
+
+// statsResponse is the JSON body returned by Stats.
+type statsResponse struct {
+	Secrets map[string]statsTypeUsage `json:"secrets"`
+}
+
+// Stats handles GET /api/v1/stats, reporting the caller's current secret
+// counts per type.
+func (h *StatsHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.UserFromContext(r.Context())
+	if !ok {
+		httperr.Unauthorized(w, r, "authentication required")
+
+		return
+	}
+
+	resp := statsResponse{Secrets: make(map[string]statsTypeUsage, len(h.counters))}
+
+	for typeName, counter := range h.counters {
+		count, err := counter(r.Context(), userID)
+		if err != nil {
+			httperr.Internal(w, r)
+
+			return
+		}
+
+		resp.Secrets[typeName] = statsTypeUsage{Count: count, Max: h.maxPerType}
+	}
+
+	writeJSON(w, resp)
+}
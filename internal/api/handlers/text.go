@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/andymarkow/gophkeeper/internal/api"
+	"github.com/andymarkow/gophkeeper/internal/api/httperr"
+	"github.com/andymarkow/gophkeeper/internal/api/validate"
+	"github.com/andymarkow/gophkeeper/internal/domain/metadata"
+	"github.com/andymarkow/gophkeeper/internal/domain/text"
+	"github.com/andymarkow/gophkeeper/internal/repository/textrepo"
+	"github.com/andymarkow/gophkeeper/internal/service/textsvc"
+)
+
+// maxTextNameLen caps how long a text secret's Name may be.
+const maxTextNameLen = 255
+
+// textStore is the text secret CRUD and search surface TextHandler depends
+// on.
+type textStore interface {
+	Create(ctx context.Context, userID, name, content string) (*text.Secret, error)
+	Get(ctx context.Context, userID, id string) (*text.Secret, string, error)
+	Replace(ctx context.Context, userID, id, content string) (*text.Secret, error)
+	Delete(ctx context.Context, userID, id string) error
+	List(ctx context.Context, userID string) ([]*text.Secret, error)
+	Search(ctx context.Context, userID, query string) ([]*text.Secret, error)
+}
+
+// TextHandler exposes full CRUD plus search over /secrets/texts for
+// arbitrary text content. A secret marked metadata.HighSecurityKey requires
+// a valid elevation token (see ElevateHandler) to read its Content; without
+// one, Get returns metadata only.
+type TextHandler struct {
+	texts     textStore
+	elevation elevationVerifier
+}
+
+// NewTextHandler returns a TextHandler backed by texts, gating
+// high-security reads on elevation.
+func NewTextHandler(texts textStore, elevation elevationVerifier) *TextHandler {
+	return &TextHandler{texts: texts, elevation: elevation}
+}
+
+type textSecretResponse struct {
+	ID        string       `json:"id"`
+	Name      string       `json:"name"`
+	Metadata  metadata.Bag `json:"metadata,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// textContentResponse is textSecretResponse plus the decrypted content,
+// returned by Create/Get/Replace but not by List/Search, which never
+// decrypt in bulk.
+type textContentResponse struct {
+	ID        string       `json:"id"`
+	Name      string       `json:"name"`
+	Content   string       `json:"content,omitempty"`
+	Metadata  metadata.Bag `json:"metadata,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+type textRequest struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+func validateTextRequest(req textRequest) validate.Errors {
+	var verrs validate.Errors
+
+	verrs.Required("name", req.Name)
+	verrs.MaxLen("name", req.Name, maxTextNameLen)
+	verrs.Required("content", req.Content)
+
+	return verrs
+}
+
+// Create handles POST /secrets/texts, responding 201 with a Location
+// header pointing at the new secret.
+func (h *TextHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	var req textRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.BadRequest(w, r, "invalid request body")
+
+		return
+	}
+
+	if verrs := validateTextRequest(req); verrs.HasErrors() {
+		httperr.ValidationFailed(w, r, verrs)
+
+		return
+	}
+
+	sec, err := h.texts.Create(r.Context(), userID, req.Name, req.Content)
+	if err != nil {
+		if errors.Is(err, textsvc.ErrSecretLimitExceeded) {
+			httperr.UnprocessableEntity(w, r, "secret limit exceeded for this account")
+
+			return
+		}
+
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	w.Header().Set("Location", "/secrets/texts/"+url.PathEscape(sec.ID))
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, toTextContentResponse(sec, req.Content))
+}
+
+// Get handles GET /secrets/texts/{id}.
+func (h *TextHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	sec, content, err := h.texts.Get(r.Context(), userID, chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeGetErr(w, r, err)
+
+		return
+	}
+
+	if metadata.IsHighSecurity(sec.Metadata) && !isElevated(r, h.elevation) {
+		writeJSONFields(w, r, toTextSecretResponse(sec))
+
+		return
+	}
+
+	writeJSONFields(w, r, toTextContentResponse(sec, content))
+}
+
+// Replace handles PUT /secrets/texts/{id}.
+func (h *TextHandler) Replace(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+	id := chi.URLParam(r, "id")
+
+	var req textRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.BadRequest(w, r, "invalid request body")
+
+		return
+	}
+
+	if req.Content == "" {
+		httperr.ValidationFailed(w, r, validate.Errors{
+			{Field: "content", Code: validate.CodeRequired, Message: "content is required"},
+		})
+
+		return
+	}
+
+	sec, err := h.texts.Replace(r.Context(), userID, id, req.Content)
+	if err != nil {
+		h.writeGetErr(w, r, err)
+
+		return
+	}
+
+	writeJSON(w, toTextContentResponse(sec, req.Content))
+}
+
+// Delete handles DELETE /secrets/texts/{id}.
+func (h *TextHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	if err := h.texts.Delete(r.Context(), userID, chi.URLParam(r, "id")); err != nil {
+		h.writeGetErr(w, r, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// List handles GET /secrets/texts. Content is not decrypted in bulk; fetch
+// each secret individually via Get.
+func (h *TextHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	secrets, err := h.texts.List(r.Context(), userID)
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	resp := make([]textSecretResponse, 0, len(secrets))
+	for _, sec := range secrets {
+		resp = append(resp, toTextSecretResponse(sec))
+	}
+
+	writeJSONFields(w, r, api.NewListEnvelope(r, resp, ""))
+}
+
+// Search handles GET /secrets/texts/search?q=..., matching against the
+// caller's opt-in blind search index instead of downloading and decrypting
+// every text secret client-side.
+func (h *TextHandler) Search(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		httperr.BadRequest(w, r, "missing q query parameter")
+
+		return
+	}
+
+	secrets, err := h.texts.Search(r.Context(), userID, query)
+	if err != nil {
+		if errors.Is(err, textsvc.ErrSearchDisabled) {
+			httperr.Forbidden(w, r, "search indexing is not enabled for this account")
+
+			return
+		}
+
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	resp := make([]textSecretResponse, 0, len(secrets))
+	for _, sec := range secrets {
+		resp = append(resp, toTextSecretResponse(sec))
+	}
+
+	writeJSONFields(w, r, api.NewListEnvelope(r, resp, ""))
+}
+
+func (h *TextHandler) writeGetErr(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, textsvc.ErrAccessDenied):
+		httperr.Forbidden(w, r, "access denied")
+	case errors.Is(err, textrepo.ErrNotFound):
+		httperr.NotFound(w, r, "secret not found")
+	default:
+		httperr.Internal(w, r)
+	}
+}
+
+func toTextSecretResponse(sec *text.Secret) textSecretResponse {
+	return textSecretResponse{
+		ID: sec.ID, Name: sec.Name, Metadata: sec.Metadata,
+		CreatedAt: sec.CreatedAt, UpdatedAt: sec.UpdatedAt,
+	}
+}
+
+func toTextContentResponse(sec *text.Secret, content string) textContentResponse {
+	return textContentResponse{
+		ID: sec.ID, Name: sec.Name, Content: content, Metadata: sec.Metadata,
+		CreatedAt: sec.CreatedAt, UpdatedAt: sec.UpdatedAt,
+	}
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/andymarkow/gophkeeper/internal/api"
+	"github.com/andymarkow/gophkeeper/internal/api/httperr"
+	"github.com/andymarkow/gophkeeper/internal/domain/file"
+	"github.com/andymarkow/gophkeeper/internal/domain/metadata"
+	"github.com/andymarkow/gophkeeper/internal/domain/text"
+	"github.com/andymarkow/gophkeeper/internal/repository/filerepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/textrepo"
+	"github.com/andymarkow/gophkeeper/internal/service/filesvc"
+	"github.com/andymarkow/gophkeeper/internal/service/textsvc"
+)
+
+// trashedFiles and trashedTexts are the subsets of filesvc.Service and
+// textsvc.Service TrashHandler depends on.
+type trashedFiles interface {
+	ListTrashed(ctx context.Context, userID string) ([]*file.Secret, error)
+	Restore(ctx context.Context, userID, id string) error
+}
+
+type trashedTexts interface {
+	ListTrashed(ctx context.Context, userID string) ([]*text.Secret, error)
+	Restore(ctx context.Context, userID, id string) error
+}
+
+// TrashHandler exposes the caller's own trashed file and text secrets,
+// listing them across both types and restoring one by type and ID. The
+// instance-wide purge job that permanently removes expired trash is an
+// admin-only action; see AdminRetentionHandler.
+type TrashHandler struct {
+	files trashedFiles
+	texts trashedTexts
+}
+
+// NewTrashHandler returns a TrashHandler backed by files and texts.
+func NewTrashHandler(files *filesvc.Service, texts *textsvc.Service) *TrashHandler {
+	return &TrashHandler{files: files, texts: texts}
+}
+
+type trashedSecretResponse struct {
+	Type      string       `json:"type"`
+	ID        string       `json:"id"`
+	Name      string       `json:"name"`
+	Metadata  metadata.Bag `json:"metadata,omitempty"`
+	DeletedAt time.Time    `json:"deleted_at"`
+}
+
+// List handles GET /trash, returning the caller's trashed file and text
+// secrets together: file secrets first, then text secrets, each in the
+// order its service's ListTrashed returns them.
+func (h *TrashHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	resp := make([]trashedSecretResponse, 0)
+
+	files, err := h.files.ListTrashed(r.Context(), userID)
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	for _, sec := range files {
+		resp = append(resp, trashedSecretResponse{
+			Type: "file", ID: sec.ID, Name: sec.Name, Metadata: sec.Metadata, DeletedAt: *sec.DeletedAt,
+		})
+	}
+
+	texts, err := h.texts.ListTrashed(r.Context(), userID)
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	for _, sec := range texts {
+		resp = append(resp, trashedSecretResponse{
+			Type: "text", ID: sec.ID, Name: sec.Name, Metadata: sec.Metadata, DeletedAt: *sec.DeletedAt,
+		})
+	}
+
+	writeJSONFields(w, r, api.NewListEnvelope(r, resp, ""))
+}
+
+// Restore handles POST /trash/{type}/{id}/restore, taking the caller's own
+// trashed secret of the given type back out of the trash.
+func (h *TrashHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+	id := chi.URLParam(r, "id")
+
+	var err error
+
+	switch chi.URLParam(r, "type") {
+	case "file":
+		err = h.files.Restore(r.Context(), userID, id)
+	case "text":
+		err = h.texts.Restore(r.Context(), userID, id)
+	default:
+		httperr.BadRequest(w, r, "unknown secret type, expected \"file\" or \"text\"")
+
+		return
+	}
+
+	if err != nil {
+		switch {
+		case errors.Is(err, filesvc.ErrAccessDenied), errors.Is(err, textsvc.ErrAccessDenied):
+			httperr.Forbidden(w, r, "access denied")
+		case errors.Is(err, filerepo.ErrNotFound), errors.Is(err, textrepo.ErrNotFound):
+			httperr.NotFound(w, r, "secret not found")
+		case errors.Is(err, filesvc.ErrNotTrashed), errors.Is(err, textsvc.ErrNotTrashed):
+			httperr.UnprocessableEntity(w, r, "secret is not trashed")
+		default:
+			httperr.Internal(w, r)
+		}
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
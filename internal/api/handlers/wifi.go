@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/andymarkow/gophkeeper/internal/api"
+	"github.com/andymarkow/gophkeeper/internal/api/httperr"
+	"github.com/andymarkow/gophkeeper/internal/api/validate"
+	"github.com/andymarkow/gophkeeper/internal/domain/metadata"
+	"github.com/andymarkow/gophkeeper/internal/domain/wifi"
+	"github.com/andymarkow/gophkeeper/internal/repository/wifirepo"
+	"github.com/andymarkow/gophkeeper/internal/service/wifisvc"
+)
+
+// maxWiFiNameLen caps how long a Wi-Fi secret's Name may be.
+const maxWiFiNameLen = 255
+
+// validateWiFiRequest checks req's fields, returning one FieldError per
+// problem found.
+func validateWiFiRequest(req wifiRequest) validate.Errors {
+	var verrs validate.Errors
+
+	verrs.Required("name", req.Name)
+	verrs.MaxLen("name", req.Name, maxWiFiNameLen)
+	verrs.Required("ssid", req.SSID)
+
+	return verrs
+}
+
+// wifiStore is the Wi-Fi secret CRUD surface WiFiHandler depends on, plus
+// QR provisioning payload rendering.
+type wifiStore interface {
+	List(ctx context.Context, userID string) ([]*wifi.Secret, error)
+	ProvisioningPayload(ctx context.Context, userID, id string) (string, error)
+	EncryptPassword(password string) (ciphertext []byte, keyID string, err error)
+	DecryptPassword(sec *wifi.Secret) (string, error)
+	Create(ctx context.Context, userID string, sec *wifi.Secret) error
+	Get(ctx context.Context, userID, id string) (*wifi.Secret, error)
+	Update(ctx context.Context, userID string, sec *wifi.Secret) error
+	Delete(ctx context.Context, userID, id string) error
+}
+
+// WiFiHandler exposes full CRUD over /secrets/wifi plus QR provisioning
+// payload rendering. The password is never included in List; use Get for a
+// single secret's decrypted password or ProvisioningPayload to hand it to a
+// client in scannable form instead.
+type WiFiHandler struct {
+	wifis wifiStore
+}
+
+// NewWiFiHandler returns a WiFiHandler backed by wifis.
+func NewWiFiHandler(wifis wifiStore) *WiFiHandler {
+	return &WiFiHandler{wifis: wifis}
+}
+
+type wifiRequest struct {
+	Name     string       `json:"name"`
+	SSID     string       `json:"ssid"`
+	Security string       `json:"security,omitempty"`
+	Password string       `json:"password,omitempty"`
+	Hidden   bool         `json:"hidden,omitempty"`
+	Metadata metadata.Bag `json:"metadata,omitempty"`
+}
+
+type wifiResponse struct {
+	ID        string       `json:"id"`
+	Name      string       `json:"name"`
+	SSID      string       `json:"ssid"`
+	Security  string       `json:"security"`
+	Password  string       `json:"password,omitempty"`
+	Hidden    bool         `json:"hidden"`
+	Metadata  metadata.Bag `json:"metadata,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// Create handles POST /secrets/wifi, responding 201 with a Location header
+// pointing at the new secret.
+func (h *WiFiHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	var req wifiRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.BadRequest(w, r, "invalid request body")
+
+		return
+	}
+
+	if verrs := validateWiFiRequest(req); verrs.HasErrors() {
+		httperr.ValidationFailed(w, r, verrs)
+
+		return
+	}
+
+	ciphertext, keyID, err := h.wifis.EncryptPassword(req.Password)
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	id, err := randomID()
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	sec := &wifi.Secret{
+		ID: id, Name: req.Name, SSID: req.SSID, Security: req.Security, Password: ciphertext,
+		KeyID: keyID, Hidden: req.Hidden, Metadata: req.Metadata,
+	}
+
+	if err := h.wifis.Create(r.Context(), userID, sec); err != nil {
+		if errors.Is(err, wifisvc.ErrSecretLimitExceeded) {
+			httperr.UnprocessableEntity(w, r, "secret limit exceeded for this account")
+
+			return
+		}
+
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	w.Header().Set("Location", "/api/v1/secrets/wifi/"+url.PathEscape(sec.Name))
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, toWiFiResponse(sec, req.Password))
+}
+
+// Get handles GET /secrets/wifi/{id}, returning Password decrypted in full.
+func (h *WiFiHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	sec, err := h.wifis.Get(r.Context(), userID, chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeGetErr(w, r, err)
+
+		return
+	}
+
+	password, err := h.wifis.DecryptPassword(sec)
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	writeJSONFields(w, r, toWiFiResponse(sec, password))
+}
+
+// Update handles PUT /secrets/wifi/{id}.
+func (h *WiFiHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+	id := chi.URLParam(r, "id")
+
+	var req wifiRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.BadRequest(w, r, "invalid request body")
+
+		return
+	}
+
+	if verrs := validateWiFiRequest(req); verrs.HasErrors() {
+		httperr.ValidationFailed(w, r, verrs)
+
+		return
+	}
+
+	ciphertext, keyID, err := h.wifis.EncryptPassword(req.Password)
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	sec := &wifi.Secret{
+		ID: id, Name: req.Name, SSID: req.SSID, Security: req.Security, Password: ciphertext,
+		KeyID: keyID, Hidden: req.Hidden, Metadata: req.Metadata,
+	}
+
+	if err := h.wifis.Update(r.Context(), userID, sec); err != nil {
+		h.writeGetErr(w, r, err)
+
+		return
+	}
+
+	writeJSON(w, toWiFiResponse(sec, req.Password))
+}
+
+// Delete handles DELETE /secrets/wifi/{id}.
+func (h *WiFiHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	if err := h.wifis.Delete(r.Context(), userID, chi.URLParam(r, "id")); err != nil {
+		h.writeGetErr(w, r, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// List handles GET /wifi. Supports ?selector= and ?metadata.<key>=<value>
+// filtering on metadata (see parseSelectorParam).
+func (h *WiFiHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+
+	sel, ok := parseSelectorParam(w, r)
+	if !ok {
+		return
+	}
+
+	secrets, err := h.wifis.List(r.Context(), userID)
+	if err != nil {
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	resp := make([]wifiResponse, 0, len(secrets))
+	for _, sec := range secrets {
+		if !sel.Matches(sec.Metadata) {
+			continue
+		}
+
+		resp = append(resp, wifiResponse{
+			ID: sec.ID, Name: sec.Name, SSID: sec.SSID, Security: sec.Security, Hidden: sec.Hidden,
+			Metadata: sec.Metadata, CreatedAt: sec.CreatedAt, UpdatedAt: sec.UpdatedAt,
+		})
+	}
+
+	writeJSONFields(w, r, api.NewListEnvelope(r, resp, ""))
+}
+
+type provisioningPayloadResponse struct {
+	Payload string `json:"payload"`
+}
+
+// ProvisioningPayload handles GET /wifi/{id}/qr, returning the standard
+// Wi-Fi QR payload string for the client to render as a scannable QR code.
+func (h *WiFiHandler) ProvisioningPayload(w http.ResponseWriter, r *http.Request) {
+	userID, _ := api.UserFromContext(r.Context())
+	id := chi.URLParam(r, "id")
+
+	payload, err := h.wifis.ProvisioningPayload(r.Context(), userID, id)
+	if err != nil {
+		if errors.Is(err, wifisvc.ErrAccessDenied) {
+			httperr.Forbidden(w, r, "access denied")
+
+			return
+		}
+
+		httperr.Internal(w, r)
+
+		return
+	}
+
+	writeJSON(w, provisioningPayloadResponse{Payload: payload})
+}
+
+func (h *WiFiHandler) writeGetErr(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, wifisvc.ErrAccessDenied):
+		httperr.Forbidden(w, r, "access denied")
+	case errors.Is(err, wifirepo.ErrNotFound):
+		httperr.NotFound(w, r, "secret not found")
+	default:
+		httperr.Internal(w, r)
+	}
+}
+
+func toWiFiResponse(sec *wifi.Secret, password string) wifiResponse {
+	return wifiResponse{
+		ID: sec.ID, Name: sec.Name, SSID: sec.SSID, Security: sec.Security, Password: password,
+		Hidden: sec.Hidden, Metadata: sec.Metadata, CreatedAt: sec.CreatedAt, UpdatedAt: sec.UpdatedAt,
+	}
+}
@@ -0,0 +1,123 @@
+// Package httperr provides a standard JSON error body for API responses.
+package httperr
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/andymarkow/gophkeeper/internal/api/validate"
+	"github.com/andymarkow/gophkeeper/internal/i18n"
+)
+
+// Response is the standard JSON error body returned by the API.
+type Response struct {
+	Code    string                `json:"code"`
+	Message string                `json:"message"`
+	Errors  []validate.FieldError `json:"errors,omitempty"`
+}
+
+// Write encodes a Response with the given status and code. message is used
+// verbatim unless r's Accept-Language header names a language i18n has a
+// translation for code in, in which case the translation is sent instead;
+// code itself is never translated, so callers that only switch on it are
+// unaffected by locale.
+func Write(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	lang := i18n.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+	message = i18n.Translate(lang, code, message)
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+
+	// Best-effort encode: the status line is already written, nothing
+	// useful can be done if this fails besides logging it upstream.
+	_ = json.NewEncoder(w).Encode(Response{Code: code, Message: message})
+}
+
+// BadRequest writes a 400 response with the given message.
+func BadRequest(w http.ResponseWriter, r *http.Request, message string) {
+	Write(w, r, http.StatusBadRequest, "bad_request", message)
+}
+
+// Unauthorized writes a 401 response with the given message.
+func Unauthorized(w http.ResponseWriter, r *http.Request, message string) {
+	Write(w, r, http.StatusUnauthorized, "unauthorized", message)
+}
+
+// Forbidden writes a 403 response with the given message.
+func Forbidden(w http.ResponseWriter, r *http.Request, message string) {
+	Write(w, r, http.StatusForbidden, "forbidden", message)
+}
+
+// NotFound writes a 404 response with the given message.
+func NotFound(w http.ResponseWriter, r *http.Request, message string) {
+	Write(w, r, http.StatusNotFound, "not_found", message)
+}
+
+// Conflict writes a 409 response with the given message.
+func Conflict(w http.ResponseWriter, r *http.Request, message string) {
+	Write(w, r, http.StatusConflict, "conflict", message)
+}
+
+// UnprocessableEntity writes a 422 response with the given message, for a
+// request that is well-formed but rejected by a business rule rather than
+// malformed syntax (use BadRequest for that).
+func UnprocessableEntity(w http.ResponseWriter, r *http.Request, message string) {
+	Write(w, r, http.StatusUnprocessableEntity, "unprocessable_entity", message)
+}
+
+// ValidationFailed writes a 422 response with one FieldError per invalid
+// field, so a client can highlight exactly which fields are wrong instead
+// of parsing a single opaque message. The top-level Message is still
+// translated via Accept-Language; individual FieldError messages are not,
+// since they're built from caller-supplied field names.
+func ValidationFailed(w http.ResponseWriter, r *http.Request, errs validate.Errors) {
+	lang := i18n.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+
+	_ = json.NewEncoder(w).Encode(Response{
+		Code:    "validation_failed",
+		Message: i18n.Translate(lang, "validation_failed", "request validation failed"),
+		Errors:  errs,
+	})
+}
+
+// TooManyRequests writes a 429 response with the given message.
+func TooManyRequests(w http.ResponseWriter, r *http.Request, message string) {
+	Write(w, r, http.StatusTooManyRequests, "rate_limited", message)
+}
+
+// TooLarge writes a 413 response with the given message.
+func TooLarge(w http.ResponseWriter, r *http.Request, message string) {
+	Write(w, r, http.StatusRequestEntityTooLarge, "request_entity_too_large", message)
+}
+
+// UnsupportedMediaType writes a 415 response with the given message.
+func UnsupportedMediaType(w http.ResponseWriter, r *http.Request, message string) {
+	Write(w, r, http.StatusUnsupportedMediaType, "unsupported_media_type", message)
+}
+
+// ObjectStorageUnavailable writes a 503 response with code
+// "object_storage_unavailable", distinguishing an unreachable object
+// storage backend from a generic internal error so clients can retry
+// instead of treating the request as permanently failed.
+func ObjectStorageUnavailable(w http.ResponseWriter, r *http.Request) {
+	Write(w, r, http.StatusServiceUnavailable, "object_storage_unavailable", "object storage is temporarily unavailable")
+}
+
+// Internal writes a 500 response with a fixed, non-leaking message.
+func Internal(w http.ResponseWriter, r *http.Request) {
+	Write(w, r, http.StatusInternalServerError, "internal_error", "internal server error")
+}
+
+// NotFoundHandler is registered on the router for unmatched routes.
+func NotFoundHandler(w http.ResponseWriter, r *http.Request) {
+	Write(w, r, http.StatusNotFound, "not_found", "the requested resource was not found")
+}
+
+// MethodNotAllowedHandler is registered on the router for routes matched by
+// path but not by method.
+func MethodNotAllowedHandler(w http.ResponseWriter, r *http.Request) {
+	Write(w, r, http.StatusMethodNotAllowed, "method_not_allowed", "method not allowed on this resource")
+}
@@ -0,0 +1,69 @@
+package middlewares
+
+import (
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// LoggerConfig controls which requests middlewares.Logger emits access log
+// lines for.
+type LoggerConfig struct {
+	// SkipPaths are exact paths never logged (e.g. "/healthz").
+	SkipPaths map[string]bool
+	// SuccessSampleRate is the fraction (0-1) of 2xx/3xx responses logged.
+	// 1 (the default, zero value) logs every success; lower values
+	// sample high-traffic success responses to cut log volume.
+	SuccessSampleRate float64
+}
+
+// Logger logs one line per request at a level chosen by status class: Warn
+// for 5xx, Info for 4xx, Debug for 2xx/3xx, honoring cfg's path exclusions
+// and success sampling.
+func Logger(base *slog.Logger, cfg LoggerConfig) func(http.Handler) http.Handler {
+	sampleRate := cfg.SuccessSampleRate
+	if sampleRate <= 0 {
+		sampleRate = 1
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if cfg.SkipPaths[r.URL.Path] {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			start := time.Now()
+			next.ServeHTTP(ww, r)
+			duration := time.Since(start)
+
+			status := ww.Status()
+
+			if status < 400 && sampleRate < 1 && rand.Float64() >= sampleRate {
+				return
+			}
+
+			level := slog.LevelDebug
+			switch {
+			case status >= 500:
+				level = slog.LevelWarn
+			case status >= 400:
+				level = slog.LevelInfo
+			}
+
+			base.Log(r.Context(), level, "http request",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", status,
+				"bytes", ww.BytesWritten(),
+				"duration_ms", duration.Milliseconds(),
+			)
+		})
+	}
+}
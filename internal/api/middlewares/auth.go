@@ -0,0 +1,127 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/api/httperr"
+	"github.com/andymarkow/gophkeeper/internal/api/reqctx"
+)
+
+// AuthCookieName is the HttpOnly cookie used to carry the session JWT when a
+// client opts into cookie transport instead of the Authorization header.
+const AuthCookieName = "gophkeeper_token"
+
+// SessionTokenHeader carries a freshly re-issued token back to the client
+// when Verifier is configured with a TokenRefresher and the active session
+// is still within its sliding expiration window. A client that doesn't look
+// for this header simply keeps using its current token until it expires, so
+// this is purely additive.
+const SessionTokenHeader = "X-Session-Token"
+
+// TokenVerifier verifies a JWT and returns the user ID it asserts. The
+// second return value is the issuing user's tenant ID, in multi-tenancy
+// deployments; Verifier doesn't use it directly, since tenant membership is
+// an account-level label enforced at token verification (a token whose
+// tenant claim no longer matches the user's current tenant is rejected
+// outright; see authsvc.Service.VerifyToken), not a per-request dimension
+// handlers need to branch on.
+type TokenVerifier interface {
+	VerifyToken(ctx context.Context, token string) (userID, tenantID string, err error)
+}
+
+// TokenRefresher extends a still-valid session token's expiry, capped at
+// some maximum session lifetime. authsvc.Service implements it; see
+// authsvc.Service.RefreshSlidingToken for the sliding expiration rules.
+type TokenRefresher interface {
+	RefreshSlidingToken(ctx context.Context, token string) (string, error)
+}
+
+// Verifier authenticates requests using a JWT taken from either the
+// Authorization header ("Bearer <token>") or the AuthCookieName cookie, and
+// places the resulting user ID on the context via reqctx.ContextWithUserID.
+//
+// If refresher is non-nil, a successfully authenticated request also tries
+// to slide the token's expiration forward, returning the new token via
+// SessionTokenHeader. Refresh failure (sliding disabled, or the session has
+// hit its maximum lifetime) is not an error for the request itself: it just
+// means no header is set and the caller keeps using its current token until
+// that expires on its own. Pass a nil refresher to disable sliding
+// expiration entirely, e.g. for routes that should always require a fresh
+// login within TokenTTL.
+func Verifier(tokens TokenVerifier, refresher TokenRefresher) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := bearerToken(r)
+			if token == "" {
+				if c, err := r.Cookie(AuthCookieName); err == nil {
+					token = c.Value
+				}
+			}
+
+			if token == "" {
+				httperr.Unauthorized(w, r, "missing authentication token")
+
+				return
+			}
+
+			userID, _, err := tokens.VerifyToken(r.Context(), token)
+			if err != nil {
+				httperr.Unauthorized(w, r, "invalid or expired token")
+
+				return
+			}
+
+			if refresher != nil {
+				if refreshed, err := refresher.RefreshSlidingToken(r.Context(), token); err == nil {
+					w.Header().Set(SessionTokenHeader, refreshed)
+				}
+			}
+
+			ctx := reqctx.ContextWithUserID(r.Context(), userID)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func bearerToken(r *http.Request) string {
+	const prefix = "Bearer "
+
+	h := r.Header.Get("Authorization")
+	if !strings.HasPrefix(h, prefix) {
+		return ""
+	}
+
+	return strings.TrimPrefix(h, prefix)
+}
+
+// SetAuthCookie writes the session token as an HttpOnly, Secure, SameSite=Lax
+// cookie, for clients that opt into cookie transport at login instead of
+// handling the bearer token themselves.
+func SetAuthCookie(w http.ResponseWriter, token string, ttl time.Duration) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     AuthCookieName,
+		Value:    token,
+		Path:     "/",
+		Expires:  time.Now().Add(ttl),
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// ClearAuthCookie expires the auth cookie, used on logout.
+func ClearAuthCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     AuthCookieName,
+		Value:    "",
+		Path:     "/",
+		Expires:  time.Unix(0, 0),
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
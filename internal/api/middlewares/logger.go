@@ -0,0 +1,35 @@
+package middlewares
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/andymarkow/gophkeeper/internal/api/reqctx"
+)
+
+// RequestLogger attaches a *slog.Logger carrying request_id, user_id (if
+// authenticated) and route to the request context, so every log line a
+// handler or service emits for this request is correlated automatically
+// without threading a logger through every function signature.
+func RequestLogger(base *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attrs := []any{
+				slog.String("request_id", middleware.GetReqID(r.Context())),
+				slog.String("route", r.URL.Path),
+				slog.String("method", r.Method),
+			}
+
+			if userID, ok := reqctx.UserFromContext(r.Context()); ok {
+				attrs = append(attrs, slog.String("user_id", userID))
+			}
+
+			logger := base.With(attrs...)
+			ctx := reqctx.ContextWithLogger(r.Context(), logger)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/andymarkow/gophkeeper/internal/api/httperr"
+)
+
+// RateLimiter reports whether a call identified by key is allowed right
+// now. ratelimit.FixedWindow and ratelimit.RedisLimiter both implement it.
+type RateLimiter interface {
+	Allow(ctx context.Context, key string) (bool, error)
+}
+
+// KeyFunc derives the rate limit key for a request, e.g. the client IP or
+// an authenticated user ID.
+type KeyFunc func(r *http.Request) string
+
+// RateLimit rejects requests with 429 once keyFunc(r) has exceeded
+// limiter's configured rate. A Limiter error (as opposed to a disallowed
+// result) fails open: the request proceeds, since a broken limiter
+// shouldn't take the whole API down with it.
+func RateLimit(limiter RateLimiter, keyFunc KeyFunc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			allowed, err := limiter.Allow(r.Context(), keyFunc(r))
+			if err == nil && !allowed {
+				httperr.TooManyRequests(w, r, "rate limit exceeded")
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package middlewares
+
+import (
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/andymarkow/gophkeeper/internal/reqrecorder"
+)
+
+// RecorderConfig controls what fraction of traffic middlewares.Recorder
+// captures and how much of each body it keeps.
+type RecorderConfig struct {
+	// SampleRate is the fraction (0-1) of requests recorded. 0 (the zero
+	// value) records nothing, so Recorder can be wired in unconditionally
+	// and gated purely on config, the same way faultinjector is.
+	SampleRate float64
+	// MaxBodyBytes caps how much of each request/response body is kept
+	// before redaction. 0 means no body capture at all, only the
+	// method/path/status/duration.
+	MaxBodyBytes int64
+}
+
+// Recorder samples a fraction of requests, storing a redacted copy of each
+// one's request/response bodies in rec for later inspection via the admin
+// port. It must only be wired in with cfg.SampleRate > 0 in a non-production
+// deployment, same rule as internal/faultinjector: a recorded body is a
+// copy of real user data sitting in process memory, sanitization or not.
+func Recorder(rec *reqrecorder.Recorder, cfg RecorderConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if cfg.SampleRate <= 0 || rand.Float64() >= cfg.SampleRate {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			reqBody := &capturedBody{max: cfg.MaxBodyBytes}
+			if r.Body != nil {
+				r.Body = io.NopCloser(io.TeeReader(r.Body, reqBody))
+			}
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			respBody := &capturedBody{max: cfg.MaxBodyBytes}
+			ww.Tee(respBody)
+
+			start := time.Now()
+			next.ServeHTTP(ww, r)
+			duration := time.Since(start)
+
+			rec.Add(reqrecorder.Entry{
+				Method:       r.Method,
+				Path:         r.URL.Path,
+				Query:        r.URL.RawQuery,
+				StatusCode:   ww.Status(),
+				RequestBody:  reqrecorder.RedactBody(reqBody.Bytes()),
+				ResponseBody: reqrecorder.RedactBody(respBody.Bytes()),
+				Duration:     duration,
+				RecordedAt:   start,
+			})
+		})
+	}
+}
+
+// capturedBody is an io.Writer that keeps at most max bytes written to it,
+// silently discarding the rest, so a large upload/download body can't blow
+// up the recorder's memory use.
+type capturedBody struct {
+	buf []byte
+	max int64
+}
+
+func (c *capturedBody) Write(p []byte) (int, error) {
+	if remaining := c.max - int64(len(c.buf)); remaining > 0 {
+		if int64(len(p)) > remaining {
+			c.buf = append(c.buf, p[:remaining]...)
+		} else {
+			c.buf = append(c.buf, p...)
+		}
+	}
+
+	return len(p), nil
+}
+
+func (c *capturedBody) Bytes() []byte {
+	return c.buf
+}
@@ -0,0 +1,41 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// TimeoutConfig controls the per-request deadline middlewares.Timeout
+// applies.
+type TimeoutConfig struct {
+	// Default is the deadline applied to requests not matched by LongPaths.
+	Default time.Duration
+	// Long is the deadline applied to requests matched by LongPaths. It
+	// should exceed Default to give upload/download handlers room to
+	// stream large payloads through Postgres/MinIO.
+	Long time.Duration
+	// LongPaths are exact request paths that get Long instead of Default
+	// (e.g. file upload/download endpoints).
+	LongPaths map[string]bool
+}
+
+// Timeout bounds each request's context to a deadline chosen from cfg, so a
+// hung Postgres or MinIO call fails the request instead of holding its
+// worker goroutine indefinitely. Handlers are responsible for honoring
+// ctx.Done(); Timeout does not itself write a response on expiry.
+func Timeout(cfg TimeoutConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			d := cfg.Default
+			if cfg.LongPaths[r.URL.Path] {
+				d = cfg.Long
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
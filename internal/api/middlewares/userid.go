@@ -0,0 +1,34 @@
+// Package middlewares holds chi-compatible HTTP middlewares shared by the
+// API handlers.
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/andymarkow/gophkeeper/internal/api/httperr"
+	"github.com/andymarkow/gophkeeper/internal/api/reqctx"
+)
+
+// UserIDHeader is the only place in the codebase allowed to read the raw
+// user identity header; every handler must go through api.UserFromContext
+// instead, so a route that forgets this middleware fails closed rather than
+// trusting a spoofable header.
+const UserIDHeader = "X-User-Id"
+
+// UserID reads UserIDHeader and places the user's ID on the request context
+// for downstream handlers to read via api.UserFromContext. Requests missing
+// the header are rejected with 401.
+func UserID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID := r.Header.Get(UserIDHeader)
+		if userID == "" {
+			httperr.Unauthorized(w, r, "missing user identity")
+
+			return
+		}
+
+		ctx := reqctx.ContextWithUserID(r.Context(), userID)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,19 @@
+// Package openapi embeds the hand-maintained OpenAPI 3 specification for the
+// public /api/v1 surface, served by the router alongside a Swagger UI page.
+//
+// This module has no swag/protoc-style generator wired in, so spec.yaml is
+// maintained by hand against
+// internal/api/handlers rather than generated from source annotations. It
+// can drift from the handlers if a route changes without a matching edit
+// here.
+package openapi
+
+import _ "embed"
+
+//go:embed spec.yaml
+var spec []byte
+
+// Spec returns the embedded OpenAPI 3 document, as YAML.
+func Spec() []byte {
+	return spec
+}
@@ -0,0 +1,50 @@
+// Package reqctx holds the request-context keys shared between package api
+// (which re-exports them for handlers) and package middlewares (which sets
+// them). It exists only to break the import cycle that would otherwise
+// result from middlewares needing to set values api's handlers read.
+package reqctx
+
+import (
+	"context"
+	"log/slog"
+)
+
+type contextKey int
+
+const (
+	userIDContextKey contextKey = iota
+	loggerContextKey
+)
+
+// ContextWithUserID returns a copy of ctx carrying the authenticated user's
+// ID. It is called by middlewares.UserID once per request; handlers should
+// not call it directly.
+func ContextWithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDContextKey, userID)
+}
+
+// UserFromContext returns the authenticated user's ID placed on the context
+// by middlewares.UserID. ok is false if no identity middleware ran for this
+// request.
+func UserFromContext(ctx context.Context) (userID string, ok bool) {
+	userID, ok = ctx.Value(userIDContextKey).(string)
+
+	return userID, ok
+}
+
+// ContextWithLogger returns a copy of ctx carrying logger. Called by
+// middlewares.RequestLogger once per request.
+func ContextWithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey, logger)
+}
+
+// LoggerFromContext returns the request-scoped logger placed on the context
+// by middlewares.RequestLogger, falling back to slog.Default() if none was
+// set (e.g. in tests that construct a bare context).
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerContextKey).(*slog.Logger); ok {
+		return logger
+	}
+
+	return slog.Default()
+}
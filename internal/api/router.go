@@ -0,0 +1,178 @@
+// Package api wires the HTTP router and exposes request-context helpers
+// shared by handlers.
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/pprof"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/andymarkow/gophkeeper/internal/api/httperr"
+	"github.com/andymarkow/gophkeeper/internal/api/middlewares"
+	"github.com/andymarkow/gophkeeper/internal/api/openapi"
+	"github.com/andymarkow/gophkeeper/internal/reqrecorder"
+)
+
+// RouterConfig controls the per-request deadlines NewRouter installs.
+type RouterConfig struct {
+	// RequestTimeout bounds ordinary JSON CRUD requests.
+	RequestTimeout time.Duration
+	// LongRequestTimeout bounds requests matched by LongPaths.
+	LongRequestTimeout time.Duration
+	// LongPaths are exact request paths given LongRequestTimeout instead
+	// of RequestTimeout (e.g. file upload/download endpoints).
+	LongPaths map[string]bool
+	// Recorder, if non-nil, samples requests into it via
+	// middlewares.Recorder using RecorderConfig. Leave nil (the default)
+	// outside dev/staging environments.
+	Recorder       *reqrecorder.Recorder
+	RecorderConfig middlewares.RecorderConfig
+}
+
+// NewRouter returns a chi.Router with the base middleware stack, a
+// per-request deadline, and the standard JSON error handlers for unmatched
+// routes and methods installed. It carries only the user-facing API;
+// operational endpoints (metrics, pprof, readiness) live on the separate
+// router from NewAdminRouter, meant for a second listener not reachable
+// from the public internet.
+func NewRouter(cfg RouterConfig) chi.Router {
+	r := chi.NewRouter()
+
+	r.Use(middleware.Recoverer)
+	r.Use(middlewares.Timeout(middlewares.TimeoutConfig{
+		Default:   cfg.RequestTimeout,
+		Long:      cfg.LongRequestTimeout,
+		LongPaths: cfg.LongPaths,
+	}))
+
+	if cfg.Recorder != nil {
+		r.Use(middlewares.Recorder(cfg.Recorder, cfg.RecorderConfig))
+	}
+
+	r.Get("/openapi.yaml", openapiSpecHandler)
+	r.Get("/docs", swaggerUIHandler)
+
+	r.NotFound(httperr.NotFoundHandler)
+	r.MethodNotAllowed(httperr.MethodNotAllowedHandler)
+
+	return r
+}
+
+// openapiSpecHandler serves the embedded OpenAPI 3 document.
+func openapiSpecHandler(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+	_, _ = w.Write(openapi.Spec())
+}
+
+// swaggerUIHandler serves a Swagger UI page rendering /openapi.yaml. It loads
+// the swagger-ui-dist bundle from a CDN rather than vendoring it, since this
+// module has no static asset pipeline.
+func swaggerUIHandler(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write([]byte(swaggerUIPage))
+}
+
+const swaggerUIPage = `<!DOCTYPE html>
+<html>
+<head>
+  <title>gophkeeper API docs</title>
+  <link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.onload = () => SwaggerUIBundle({url: 'openapi.yaml', dom_id: '#swagger-ui'});
+  </script>
+</body>
+</html>
+`
+
+// AdminRouterConfig configures the admin/ops router's optional endpoints.
+type AdminRouterConfig struct {
+	// Readyz, if non-nil, is mounted at GET /readyz.
+	Readyz http.HandlerFunc
+	// Recordings, if non-nil, is the reqrecorder.Recorder sampled request
+	// traffic is being written to (see middlewares.Recorder), exposed at
+	// GET /debug/recordings.
+	Recordings *reqrecorder.Recorder
+}
+
+// NewAdminRouter returns a chi.Router exposing Prometheus metrics, pprof
+// profiling and (if configured) readiness, meant to be served on a second
+// listener bound to localhost or a cluster-internal network, so these
+// operational surfaces are never exposed alongside the public API.
+func NewAdminRouter(cfg AdminRouterConfig) chi.Router {
+	r := chi.NewRouter()
+
+	r.Use(middleware.Recoverer)
+
+	r.Handle("/metrics", promhttp.Handler())
+
+	r.HandleFunc("/debug/pprof/", pprof.Index)
+	r.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	r.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	r.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	r.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	// Named profiles (heap, goroutine, block, ...) fall through to
+	// pprof.Index, which dispatches on the trailing path segment itself.
+	r.HandleFunc("/debug/pprof/*", pprof.Index)
+
+	if cfg.Readyz != nil {
+		r.Get("/readyz", cfg.Readyz)
+	}
+
+	if cfg.Recordings != nil {
+		r.Get("/debug/recordings", recordingsHandler(cfg.Recordings))
+	}
+
+	r.NotFound(httperr.NotFoundHandler)
+	r.MethodNotAllowed(httperr.MethodNotAllowedHandler)
+
+	return r
+}
+
+// recordingEntry is the JSON shape of one reqrecorder.Entry returned by
+// /debug/recordings. RequestBody/ResponseBody are already-redacted JSON (or
+// a quoted placeholder string), so they're embedded as raw messages rather
+// than double-encoded.
+type recordingEntry struct {
+	Method       string          `json:"method"`
+	Path         string          `json:"path"`
+	Query        string          `json:"query,omitempty"`
+	StatusCode   int             `json:"status_code"`
+	RequestBody  json.RawMessage `json:"request_body,omitempty"`
+	ResponseBody json.RawMessage `json:"response_body,omitempty"`
+	DurationMS   int64           `json:"duration_ms"`
+	RecordedAt   time.Time       `json:"recorded_at"`
+}
+
+// recordingsHandler serves the recorder's current buffer, newest first, so
+// an operator sees the most recently reproduced traffic without paging.
+func recordingsHandler(rec *reqrecorder.Recorder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entries := rec.Entries()
+
+		resp := make([]recordingEntry, len(entries))
+		for i, e := range entries {
+			resp[len(entries)-1-i] = recordingEntry{
+				Method:       e.Method,
+				Path:         e.Path,
+				Query:        e.Query,
+				StatusCode:   e.StatusCode,
+				RequestBody:  json.RawMessage(e.RequestBody),
+				ResponseBody: json.RawMessage(e.ResponseBody),
+				DurationMS:   e.Duration.Milliseconds(),
+				RecordedAt:   e.RecordedAt,
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_ = json.NewEncoder(w).Encode(resp)
+	}
+}
@@ -0,0 +1,50 @@
+// Package validate implements structured, per-field request validation
+// shared by API handlers, so a bad field is reported to clients as a
+// machine-readable {field, code, message} entry instead of one opaque
+// message.
+package validate
+
+import "strconv"
+
+// FieldError reports one invalid field in a request body.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Common Code values handlers can reuse so clients can switch on them
+// instead of parsing Message.
+const (
+	CodeRequired = "required"
+	CodeTooLong  = "too_long"
+	CodeInvalid  = "invalid"
+)
+
+// Errors accumulates FieldErrors across a request body's fields.
+type Errors []FieldError
+
+// Add appends a FieldError to e.
+func (e *Errors) Add(field, code, message string) {
+	*e = append(*e, FieldError{Field: field, Code: code, Message: message})
+}
+
+// Required appends a CodeRequired FieldError for field if value is empty.
+func (e *Errors) Required(field, value string) {
+	if value == "" {
+		e.Add(field, CodeRequired, field+" is required")
+	}
+}
+
+// MaxLen appends a CodeTooLong FieldError for field if value is longer than
+// max runes.
+func (e *Errors) MaxLen(field, value string, max int) {
+	if len([]rune(value)) > max {
+		e.Add(field, CodeTooLong, field+" must be at most "+strconv.Itoa(max)+" characters")
+	}
+}
+
+// HasErrors reports whether any FieldError was recorded.
+func (e Errors) HasErrors() bool {
+	return len(e) > 0
+}
@@ -0,0 +1,49 @@
+// Package audit records security-relevant actions (logins, secret access,
+// admin operations) to a structured, queryable trail.
+package audit
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Event is one audit trail entry.
+type Event struct {
+	Action    string
+	ActorID   string
+	Target    string
+	Detail    string
+	Timestamp time.Time
+}
+
+// Logger records audit events. Implementations must not block the caller on
+// a slow sink; they should buffer or log-and-drop on backpressure instead.
+type Logger interface {
+	Log(ctx context.Context, event Event)
+}
+
+// SlogLogger records audit events as structured log lines. It's the default
+// Logger until a dedicated queryable store exists.
+type SlogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlogLogger returns a Logger that writes events to logger at Info level.
+func NewSlogLogger(logger *slog.Logger) *SlogLogger {
+	return &SlogLogger{logger: logger}
+}
+
+func (l *SlogLogger) Log(ctx context.Context, event Event) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	l.logger.LogAttrs(ctx, slog.LevelInfo, "audit event",
+		slog.String("action", event.Action),
+		slog.String("actor_id", event.ActorID),
+		slog.String("target", event.Target),
+		slog.String("detail", event.Detail),
+		slog.Time("timestamp", event.Timestamp),
+	)
+}
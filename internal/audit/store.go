@@ -0,0 +1,118 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Filter narrows a Query to events matching every non-zero field.
+type Filter struct {
+	ActorID string
+	Action  string
+	Target  string
+	Since   time.Time
+	Until   time.Time
+	// Cursor resumes a previous Query from where it left off; pass the
+	// NextCursor it returned. Empty starts from the most recent event.
+	Cursor string
+	// Limit caps the number of events returned. Zero uses DefaultLimit.
+	Limit int
+}
+
+// DefaultLimit is the page size Query uses when Filter.Limit is zero.
+const DefaultLimit = 50
+
+// MemStore is an in-memory, queryable audit.Logger: it keeps every logged
+// event so Query can filter and page over the trail, in addition to
+// fulfilling the write-only Logger interface SlogLogger satisfies.
+// Unbounded retention is fine for the in-memory reference stores the rest
+// of this module uses; a persistent audit store would need its own
+// retention policy.
+type MemStore struct {
+	mu     sync.RWMutex
+	events []Event
+}
+
+// NewMemStore returns an empty in-memory audit store.
+func NewMemStore() *MemStore {
+	return &MemStore{}
+}
+
+func (s *MemStore) Log(_ context.Context, event Event) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, event)
+}
+
+// Query returns events matching filter, most recent first, along with a
+// cursor for the next page (empty once there are no more matches).
+func (s *MemStore) Query(_ context.Context, filter Filter) (events []Event, nextCursor string, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	start := len(s.events) - 1
+
+	if filter.Cursor != "" {
+		idx, err := strconv.Atoi(filter.Cursor)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid cursor %q", filter.Cursor)
+		}
+
+		start = idx
+	}
+
+	out := make([]Event, 0, limit)
+
+	i := start
+	for ; i >= 0 && len(out) < limit; i-- {
+		e := s.events[i]
+		if !matches(e, filter) {
+			continue
+		}
+
+		out = append(out, e)
+	}
+
+	if i >= 0 {
+		nextCursor = strconv.Itoa(i)
+	}
+
+	return out, nextCursor, nil
+}
+
+func matches(e Event, f Filter) bool {
+	if f.ActorID != "" && e.ActorID != f.ActorID {
+		return false
+	}
+
+	if f.Action != "" && e.Action != f.Action {
+		return false
+	}
+
+	if f.Target != "" && e.Target != f.Target {
+		return false
+	}
+
+	if !f.Since.IsZero() && e.Timestamp.Before(f.Since) {
+		return false
+	}
+
+	if !f.Until.IsZero() && e.Timestamp.After(f.Until) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,106 @@
+// Package bwlimit paces io.Reader/io.Writer byte throughput against a
+// token bucket, so upload/download streaming paths can be capped per
+// connection and, in aggregate, per user, without rejecting the request
+// outright the way internal/ratelimit does for request rate.
+package bwlimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// maxChunk bounds how many bytes a single Read/Write call paces at once,
+// so a caller passing a very large buffer still gets smooth pacing
+// instead of one long wait followed by an unthrottled burst.
+const maxChunk = 32 * 1024
+
+// Bucket is a token-bucket byte-rate limiter: at most bytesPerSecond bytes
+// are released per second on average, with an initial burst up to that
+// same amount available immediately.
+type Bucket struct {
+	mu         sync.Mutex
+	rate       float64
+	tokens     float64
+	lastRefill time.Time
+}
+
+// NewBucket returns a Bucket sustaining bytesPerSecond. Callers should not
+// construct a Bucket with bytesPerSecond <= 0; use a nil *Limiter (see
+// Limiter) to represent "unlimited" instead.
+func NewBucket(bytesPerSecond int64) *Bucket {
+	rate := float64(bytesPerSecond)
+
+	return &Bucket{rate: rate, tokens: rate, lastRefill: time.Now()}
+}
+
+// WaitN blocks until n bytes' worth of tokens are available, or ctx is
+// canceled first.
+func (b *Bucket) WaitN(ctx context.Context, n int) error {
+	for {
+		b.mu.Lock()
+		b.refillLocked()
+
+		if b.tokens >= float64(n) {
+			b.tokens -= float64(n)
+			b.mu.Unlock()
+
+			return nil
+		}
+
+		wait := time.Duration((float64(n) - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (b *Bucket) refillLocked() {
+	now := time.Now()
+	elapsed := now.Sub(b.lastRefill).Seconds()
+	b.lastRefill = now
+
+	b.tokens += elapsed * b.rate
+	if b.tokens > b.rate {
+		b.tokens = b.rate
+	}
+}
+
+// KeyedBucket hands out one shared Bucket per key, so every stream for the
+// same key (e.g. a user ID) is paced against one combined rate rather than
+// each getting its own. Like lock.KeyedMutex, buckets are never removed
+// once created, trading unbounded memory growth (one small Bucket per
+// distinct key ever seen) for simplicity.
+type KeyedBucket struct {
+	mu      sync.Mutex
+	rate    int64
+	buckets map[string]*Bucket
+}
+
+// NewKeyedBucket returns a KeyedBucket whose buckets all sustain
+// bytesPerSecond.
+func NewKeyedBucket(bytesPerSecond int64) *KeyedBucket {
+	return &KeyedBucket{rate: bytesPerSecond, buckets: make(map[string]*Bucket)}
+}
+
+// Get returns the shared Bucket for key, creating it on first use.
+func (k *KeyedBucket) Get(key string) *Bucket {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	b, ok := k.buckets[key]
+	if !ok {
+		b = NewBucket(k.rate)
+		k.buckets[key] = b
+	}
+
+	return b
+}
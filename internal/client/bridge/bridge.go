@@ -0,0 +1,102 @@
+// Package bridge implements a loopback-only HTTP API that lets a browser
+// extension look up and fill saved credentials without the client's vault
+// key ever leaving the local machine.
+package bridge
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+// CredentialStore matches credentials against a site URL. A real
+// implementation is backed by the client's local vault cache.
+type CredentialStore interface {
+	MatchByURL(siteURL string) ([]MatchedCredential, error)
+}
+
+// MatchedCredential is a credential offered to the extension for a site.
+// It carries no password, and /fill returns none either: it only asks the
+// Confirmer and answers 204 on approval.
+type MatchedCredential struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Login string `json:"login"`
+}
+
+// Confirmer asks the user to approve an extension's request to fill a
+// specific credential, e.g. via a desktop notification or CLI prompt.
+type Confirmer interface {
+	Confirm(credentialID, siteURL string) bool
+}
+
+// AllowedOrigins is the set of browser extension origins permitted to call
+// the bridge, e.g. "chrome-extension://<id>".
+type AllowedOrigins map[string]bool
+
+// Server is the loopback HTTP API consumed by the browser extension.
+type Server struct {
+	store   CredentialStore
+	confirm Confirmer
+	allowed AllowedOrigins
+}
+
+// NewServer returns a Server restricting requests to origins in allowed.
+func NewServer(store CredentialStore, confirm Confirmer, allowed AllowedOrigins) *Server {
+	return &Server{store: store, confirm: confirm, allowed: allowed}
+}
+
+// Handler returns the bridge's http.Handler, meant to be served on a
+// loopback-only listener (127.0.0.1:<port>), never exposed externally.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/match", s.checkOrigin(s.handleMatch))
+	mux.HandleFunc("/fill", s.checkOrigin(s.handleFill))
+
+	return mux
+}
+
+func (s *Server) checkOrigin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if !s.allowed[origin] {
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+func (s *Server) handleMatch(w http.ResponseWriter, r *http.Request) {
+	siteURL := r.URL.Query().Get("url")
+	if _, err := url.Parse(siteURL); siteURL == "" || err != nil {
+		http.Error(w, "invalid url", http.StatusBadRequest)
+
+		return
+	}
+
+	matches, err := s.store.MatchByURL(siteURL)
+	if err != nil {
+		http.Error(w, "lookup failed", http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(matches)
+}
+
+func (s *Server) handleFill(w http.ResponseWriter, r *http.Request) {
+	credentialID := r.URL.Query().Get("id")
+	siteURL := r.URL.Query().Get("url")
+
+	if !s.confirm.Confirm(credentialID, siteURL) {
+		http.Error(w, "user declined", http.StatusForbidden)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
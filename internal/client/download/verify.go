@@ -0,0 +1,63 @@
+// Package download implements client-side handling of downloaded secret
+// payloads, including integrity verification.
+package download
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+)
+
+// ErrChecksumMismatch is returned when downloaded content does not match
+// the checksum the API reported for it.
+type ErrChecksumMismatch struct {
+	Want string
+	Got  string
+}
+
+func (e *ErrChecksumMismatch) Error() string {
+	return fmt.Sprintf("checksum mismatch: want %s, got %s", e.Want, e.Got)
+}
+
+// VerifyingReader wraps an io.Reader, hashing everything read from it so the
+// accumulated checksum can be checked against the server-reported value
+// once the stream is fully consumed.
+type VerifyingReader struct {
+	r io.Reader
+	h interface{ Sum([]byte) []byte }
+}
+
+// NewVerifyingReader wraps r, computing a running SHA-256 as it is read.
+func NewVerifyingReader(r io.Reader) *VerifyingReader {
+	h := sha256.New()
+
+	return &VerifyingReader{r: io.TeeReader(r, h), h: h}
+}
+
+func (v *VerifyingReader) Read(p []byte) (int, error) {
+	return v.r.Read(p)
+}
+
+// Checksum returns the hex-encoded SHA-256 of everything read so far.
+func (v *VerifyingReader) Checksum() string {
+	return hex.EncodeToString(v.h.Sum(nil))
+}
+
+// Verify writes src to dst while computing its checksum, and fails loudly
+// with ErrChecksumMismatch rather than silently persisting corrupted data
+// if the result doesn't match wantChecksum (hex-encoded SHA-256, as
+// reported by the API alongside the download).
+func Verify(dst io.Writer, src io.Reader, wantChecksum string) error {
+	vr := NewVerifyingReader(src)
+
+	if _, err := io.Copy(dst, vr); err != nil {
+		return fmt.Errorf("copy downloaded content: %w", err)
+	}
+
+	if got := vr.Checksum(); wantChecksum != "" && got != wantChecksum {
+		return &ErrChecksumMismatch{Want: wantChecksum, Got: got}
+	}
+
+	return nil
+}
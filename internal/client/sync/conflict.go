@@ -0,0 +1,57 @@
+// Package sync reconciles the client's local cache with the server during a
+// sync run, including conflict detection and resolution.
+package sync
+
+import "time"
+
+// Strategy selects how a sync run resolves conflicting edits.
+type Strategy string
+
+const (
+	// StrategyServerWins discards the local edit in favor of the server's.
+	StrategyServerWins Strategy = "server-wins"
+	// StrategyLocalWins pushes the local edit, overwriting the server's.
+	StrategyLocalWins Strategy = "local-wins"
+	// StrategyDuplicate keeps both: the server's version under its
+	// original name, and the local edit saved as a new secret.
+	StrategyDuplicate Strategy = "duplicate"
+)
+
+// Record is the minimal state sync needs from a cached or server secret to
+// detect a conflict, independent of its concrete type.
+type Record struct {
+	ID        string
+	Name      string
+	UpdatedAt time.Time
+	Version   int
+}
+
+// Outcome describes what happened to one record during a sync run.
+type Outcome struct {
+	ID       string
+	Conflict bool
+	Strategy Strategy
+	// DuplicateID is set when Strategy is StrategyDuplicate, naming the
+	// newly created copy of the local edit.
+	DuplicateID string
+}
+
+// IsConflict reports whether a local edit and the server's current state
+// have diverged: both changed since the version the client last synced.
+func IsConflict(localBase, local, server Record) bool {
+	return local.Version != localBase.Version && server.Version != localBase.Version &&
+		server.Version != local.Version
+}
+
+// Resolve applies strategy to a conflicting (local, server) pair and
+// reports what happened. newID is used to name the duplicate when strategy
+// is StrategyDuplicate.
+func Resolve(local, server Record, strategy Strategy, newID func() string) Outcome {
+	out := Outcome{ID: server.ID, Conflict: true, Strategy: strategy}
+
+	if strategy == StrategyDuplicate {
+		out.DuplicateID = newID()
+	}
+
+	return out
+}
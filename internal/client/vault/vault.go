@@ -0,0 +1,162 @@
+// Package vault implements the client's local encrypted cache/export file
+// format: a versioned header, an argon2id-derived key, AEAD-sealed payload,
+// and atomic writes so a crash never leaves a corrupt file on disk.
+package vault
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+
+	"github.com/andymarkow/gophkeeper/internal/metrics"
+)
+
+// Format is the on-disk vault layout version. Bumped whenever the header or
+// KDF parameters change in a way that breaks older readers.
+const Format uint8 = 1
+
+const (
+	saltSize        = 16
+	argon2Time      = 3
+	argon2MemoryKiB = 64 * 1024
+	argon2Threads   = 4
+	keySize         = chacha20poly1305.KeySize
+)
+
+// ErrInvalidFormat is returned for files that aren't a recognized vault, or
+// whose integrity check fails (wrong passphrase or corruption).
+var ErrInvalidFormat = errors.New("vault: invalid or corrupt file")
+
+// deriveKey derives a symmetric key from passphrase and salt via argon2id.
+// Argon2id is memory-hard by design, so this is the dominant CPU cost of
+// opening or sealing a vault; it's instrumented separately from the AEAD
+// operation to tell the two apart.
+func deriveKey(passphrase, salt []byte) []byte {
+	start := time.Now()
+	defer metrics.ObserveCrypto("kdf", "argon2id", start, nil)
+
+	return argon2.IDKey(passphrase, salt, argon2Time, argon2MemoryKiB, argon2Threads, keySize)
+}
+
+// Seal encrypts plaintext under a key derived from passphrase and returns
+// the full vault file contents: [format][salt][nonce][ciphertext+tag].
+func Seal(plaintext, passphrase []byte) (_ []byte, err error) {
+	defer metrics.ObserveCrypto("encrypt", "xchacha20poly1305", time.Now(), &err)
+
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("generate salt: %w", err)
+	}
+
+	aead, err := chacha20poly1305.NewX(deriveKey(passphrase, salt))
+	if err != nil {
+		return nil, fmt.Errorf("init aead: %w", err)
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+
+	out := make([]byte, 0, 1+saltSize+len(nonce)+len(plaintext)+aead.Overhead())
+	out = append(out, Format)
+	out = append(out, salt...)
+	out = append(out, nonce...)
+	out = aead.Seal(out, nonce, plaintext, nil)
+
+	return out, nil
+}
+
+// Open decrypts a vault file produced by Seal, returning its plaintext.
+func Open(data, passphrase []byte) (_ []byte, err error) {
+	defer metrics.ObserveCrypto("decrypt", "xchacha20poly1305", time.Now(), &err)
+
+	if len(data) < 1+saltSize {
+		return nil, ErrInvalidFormat
+	}
+
+	if data[0] != Format {
+		return nil, fmt.Errorf("%w: unsupported format version %d", ErrInvalidFormat, data[0])
+	}
+
+	salt := data[1 : 1+saltSize]
+	rest := data[1+saltSize:]
+
+	aead, err := chacha20poly1305.NewX(deriveKey(passphrase, salt))
+	if err != nil {
+		return nil, fmt.Errorf("init aead: %w", err)
+	}
+
+	if len(rest) < aead.NonceSize() {
+		return nil, ErrInvalidFormat
+	}
+
+	nonce, ciphertext := rest[:aead.NonceSize()], rest[aead.NonceSize():]
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrInvalidFormat
+	}
+
+	return plaintext, nil
+}
+
+// WriteFile atomically writes a sealed vault to path: the sealed content is
+// written to a temp file in the same directory, then renamed into place, so
+// a crash mid-write never leaves a truncated or mixed-content vault.
+func WriteFile(path string, plaintext, passphrase []byte, perm os.FileMode) error {
+	sealed, err := Seal(plaintext, passphrase)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+
+	tmp, err := os.CreateTemp(dir, ".vault-*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(sealed); err != nil {
+		tmp.Close()
+
+		return fmt.Errorf("write temp file: %w", err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+
+		return fmt.Errorf("sync temp file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temp file: %w", err)
+	}
+
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return fmt.Errorf("chmod temp file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("rename temp file into place: %w", err)
+	}
+
+	return nil
+}
+
+// ReadFile reads and decrypts the vault at path.
+func ReadFile(path string, passphrase []byte) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read vault file: %w", err)
+	}
+
+	return Open(data, passphrase)
+}
@@ -0,0 +1,40 @@
+package vault_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/andymarkow/gophkeeper/internal/client/vault"
+)
+
+func TestSealOpen_RoundTrip(t *testing.T) {
+	plaintext := []byte("super secret vault contents")
+	passphrase := []byte("correct horse battery staple")
+
+	sealed, err := vault.Seal(plaintext, passphrase)
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+
+	got, err := vault.Open(sealed, passphrase)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	if !bytes.Equal(got, plaintext) {
+		t.Fatalf("Open() = %q, want %q", got, plaintext)
+	}
+}
+
+func TestOpen_WrongPassphrase(t *testing.T) {
+	sealed, err := vault.Seal([]byte("data"), []byte("right"))
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+
+	_, err = vault.Open(sealed, []byte("wrong"))
+	if !errors.Is(err, vault.ErrInvalidFormat) {
+		t.Fatalf("Open() error = %v, want ErrInvalidFormat", err)
+	}
+}
@@ -0,0 +1,90 @@
+package webdav
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+
+	xwebdav "golang.org/x/net/webdav"
+)
+
+// fileInfoAdapter satisfies os.FileInfo for one file secret.
+type fileInfoAdapter struct{ FileInfo }
+
+func (a fileInfoAdapter) Name() string       { return a.FileInfo.Name }
+func (a fileInfoAdapter) Size() int64        { return a.FileInfo.Size }
+func (a fileInfoAdapter) Mode() fs.FileMode  { return 0o600 }
+func (a fileInfoAdapter) ModTime() time.Time { return a.FileInfo.UpdatedAt }
+func (a fileInfoAdapter) IsDir() bool        { return false }
+func (a fileInfoAdapter) Sys() any           { return nil }
+
+// readFile adapts a streamed download to xwebdav.File for read-only access.
+// Seek is unsupported since secret downloads are single-pass streams;
+// the API offers no random access either, only a preview of the first bytes.
+type readFile struct {
+	io.ReadCloser
+	info FileInfo
+}
+
+func (f *readFile) Write([]byte) (int, error)          { return 0, os.ErrPermission }
+func (f *readFile) Seek(int64, int) (int64, error)     { return 0, os.ErrInvalid }
+func (f *readFile) Readdir(int) ([]os.FileInfo, error) { return nil, os.ErrInvalid }
+func (f *readFile) Stat() (os.FileInfo, error)         { return fileInfoAdapter{f.info}, nil }
+
+// writeFile buffers writes in memory and uploads on Close, since filesvc's
+// upload API is a single streamed call rather than a random-access write
+// target.
+type writeFile struct {
+	fs   *FileSystem
+	ctx  context.Context
+	name string
+	buf  bytes.Buffer
+}
+
+func (f *writeFile) Write(p []byte) (int, error) { return f.buf.Write(p) }
+func (f *writeFile) Read([]byte) (int, error)    { return 0, os.ErrPermission }
+func (f *writeFile) Seek(int64, int) (int64, error) {
+	return 0, os.ErrInvalid
+}
+func (f *writeFile) Readdir(int) ([]os.FileInfo, error) { return nil, os.ErrInvalid }
+func (f *writeFile) Stat() (os.FileInfo, error)         { return nil, os.ErrInvalid }
+
+func (f *writeFile) Close() error {
+	return f.fs.secrets.Upload(f.ctx, f.fs.userID, f.name, bytes.NewReader(f.buf.Bytes()), int64(f.buf.Len()))
+}
+
+// dirFile lists every file secret as the contents of the (single, flat)
+// root directory.
+type dirFile struct {
+	fs  *FileSystem
+	ctx context.Context
+}
+
+func (d *dirFile) Read([]byte) (int, error)       { return 0, os.ErrPermission }
+func (d *dirFile) Write([]byte) (int, error)      { return 0, os.ErrPermission }
+func (d *dirFile) Seek(int64, int) (int64, error) { return 0, nil }
+func (d *dirFile) Close() error                   { return nil }
+func (d *dirFile) Stat() (os.FileInfo, error) {
+	return fileInfoAdapter{FileInfo{Name: "/", UpdatedAt: time.Now()}}, nil
+}
+
+func (d *dirFile) Readdir(count int) ([]os.FileInfo, error) {
+	infos, err := d.fs.secrets.List(d.ctx, d.fs.userID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]os.FileInfo, 0, len(infos))
+	for _, info := range infos {
+		out = append(out, fileInfoAdapter{info})
+	}
+
+	return out, nil
+}
+
+var _ xwebdav.File = (*readFile)(nil)
+var _ xwebdav.File = (*writeFile)(nil)
+var _ xwebdav.File = (*dirFile)(nil)
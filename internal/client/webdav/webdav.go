@@ -0,0 +1,104 @@
+// Package webdav exposes a user's file secrets as a WebDAV share, backed by
+// filesvc, so they can be mounted as a network drive without a custom
+// client.
+package webdav
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+
+	"golang.org/x/net/webdav"
+)
+
+// FileSecrets is the subset of filesvc needed to back a WebDAV share.
+type FileSecrets interface {
+	List(ctx context.Context, userID string) ([]FileInfo, error)
+	Open(ctx context.Context, userID, name string) (io.ReadCloser, FileInfo, error)
+	Upload(ctx context.Context, userID, name string, r io.Reader, size int64) error
+	Delete(ctx context.Context, userID, name string) error
+}
+
+// FileInfo is the WebDAV-relevant metadata for one file secret.
+type FileInfo struct {
+	Name      string
+	Size      int64
+	UpdatedAt time.Time
+}
+
+// FileSystem adapts FileSecrets to webdav.FileSystem for a single user,
+// flattening every file secret into one directory (gophkeeper has no
+// folder hierarchy, only named secrets).
+type FileSystem struct {
+	secrets FileSecrets
+	userID  string
+}
+
+// NewFileSystem returns a webdav.FileSystem view of userID's file secrets.
+func NewFileSystem(secrets FileSecrets, userID string) *FileSystem {
+	return &FileSystem{secrets: secrets, userID: userID}
+}
+
+func (fs *FileSystem) Mkdir(context.Context, string, os.FileMode) error {
+	return os.ErrPermission // no folder hierarchy to create
+}
+
+func (fs *FileSystem) RemoveAll(ctx context.Context, name string) error {
+	return fs.secrets.Delete(ctx, fs.userID, trimSlash(name))
+}
+
+func (fs *FileSystem) Rename(context.Context, string, string) error {
+	return os.ErrPermission // renames go through the regular update API
+}
+
+func (fs *FileSystem) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	info, err := fs.findInfo(ctx, trimSlash(name))
+	if err != nil {
+		return nil, err
+	}
+
+	return fileInfoAdapter{info}, nil
+}
+
+func (fs *FileSystem) OpenFile(ctx context.Context, name string, flag int, _ os.FileMode) (webdav.File, error) {
+	name = trimSlash(name)
+
+	if name == "" {
+		return &dirFile{fs: fs, ctx: ctx}, nil
+	}
+
+	if flag&os.O_WRONLY != 0 || flag&os.O_RDWR != 0 {
+		return &writeFile{fs: fs, ctx: ctx, name: name}, nil
+	}
+
+	rc, info, err := fs.secrets.Open(ctx, fs.userID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &readFile{ReadCloser: rc, info: info}, nil
+}
+
+func (fs *FileSystem) findInfo(ctx context.Context, name string) (FileInfo, error) {
+	infos, err := fs.secrets.List(ctx, fs.userID)
+	if err != nil {
+		return FileInfo{}, err
+	}
+
+	for _, info := range infos {
+		if info.Name == name {
+			return info, nil
+		}
+	}
+
+	return FileInfo{}, os.ErrNotExist
+}
+
+func trimSlash(name string) string {
+	for len(name) > 0 && name[0] == '/' {
+		name = name[1:]
+	}
+
+	return name
+}
@@ -0,0 +1,238 @@
+// Package config loads gophkeeper server configuration from defaults, a
+// config file, and environment variables (in increasing precedence), using
+// a single KEEPER_ environment prefix instead of per-key bindings.
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// EnvPrefix is the prefix viper uses to auto-bind environment variables,
+// e.g. KEEPER_SERVER_ADDRESS binds to server.address.
+const EnvPrefix = "KEEPER"
+
+// Config is the effective, fully-resolved server configuration.
+type Config struct {
+	Server         ServerConfig         `mapstructure:"server"`
+	DB             DBConfig             `mapstructure:"db"`
+	S3             S3Config             `mapstructure:"s3"`
+	Auth           AuthConfig           `mapstructure:"auth"`
+	Limits         LimitsConfig         `mapstructure:"limits"`
+	Crypto         CryptoConfig         `mapstructure:"crypto"`
+	Telegram       TelegramConfig       `mapstructure:"telegram"`
+	FaultInjection FaultInjectionConfig `mapstructure:"fault_injection"`
+	MultiTenancy   MultiTenancyConfig   `mapstructure:"multi_tenancy"`
+	Retention      RetentionConfig      `mapstructure:"retention"`
+	Debug          DebugConfig          `mapstructure:"debug"`
+}
+
+type ServerConfig struct {
+	Address string `mapstructure:"address"`
+	// AdminAddress, if set, serves metrics, pprof and readiness (see
+	// api.NewAdminRouter) on a second listener, meant to be bound to
+	// localhost or a cluster-internal network rather than Address. Empty
+	// disables the admin listener entirely.
+	AdminAddress string `mapstructure:"admin_address"`
+	// RequestTimeout bounds ordinary JSON CRUD requests.
+	RequestTimeout time.Duration `mapstructure:"request_timeout"`
+	// LongRequestTimeout bounds the file upload requests, which need more
+	// room to stream large payloads through Postgres/MinIO. File downloads
+	// are not covered: their paths carry the secret ID and the long paths
+	// are matched exactly, so they get RequestTimeout.
+	LongRequestTimeout time.Duration `mapstructure:"long_request_timeout"`
+}
+
+type DBConfig struct {
+	DSN string `mapstructure:"dsn"`
+}
+
+type S3Config struct {
+	Endpoint  string `mapstructure:"endpoint"`
+	Bucket    string `mapstructure:"bucket"`
+	AccessKey string `mapstructure:"access_key"`
+	SecretKey string `mapstructure:"secret_key"`
+	UseSSL    bool   `mapstructure:"use_ssl"`
+}
+
+type AuthConfig struct {
+	JWTSecret string        `mapstructure:"jwt_secret"`
+	TokenTTL  time.Duration `mapstructure:"token_ttl"`
+	// MaxLifetime enables sliding expiration (see authsvc.RefreshSlidingToken)
+	// when greater than zero: an actively-used session keeps getting its
+	// token re-issued with a fresh TokenTTL window, up to MaxLifetime after
+	// the original login. 0 (the default) disables sliding expiration, so
+	// every token simply expires TokenTTL after issuance regardless of use.
+	MaxLifetime time.Duration `mapstructure:"max_lifetime"`
+	// BcryptCost is the work factor used to hash new and rehashed passwords.
+	// 0 (the default) falls back to passwordhash.DefaultCost (bcrypt's own
+	// DefaultCost). Raising it after accounts already exist rehashes each
+	// one opportunistically the next time it logs in successfully, rather
+	// than requiring a bulk migration.
+	BcryptCost int `mapstructure:"bcrypt_cost"`
+}
+
+type LimitsConfig struct {
+	MaxUploadSizeBytes int64 `mapstructure:"max_upload_size_bytes"`
+	// MaxListPageSize caps how many secrets a list endpoint will decrypt
+	// and return in one response when the caller asks for ?include=data.
+	MaxListPageSize int `mapstructure:"max_list_page_size"`
+	// AllowedContentTypes, if non-empty, is the exhaustive set of content
+	// types file secret uploads may declare; anything else is rejected.
+	AllowedContentTypes []string `mapstructure:"allowed_content_types"`
+	// DeniedContentTypes is rejected regardless of AllowedContentTypes,
+	// e.g. to block executables in a corporate deployment.
+	DeniedContentTypes []string `mapstructure:"denied_content_types"`
+	// MaxFileVersions caps how many prior versions of a file secret's
+	// content are retained after a re-upload; the oldest is purged,
+	// including its object storage payload, once a new upload would push
+	// the count past this limit.
+	MaxFileVersions int `mapstructure:"max_file_versions"`
+	// PerConnectionBytesPerSecond caps a single file upload/download
+	// stream's throughput. 0 disables per-connection throttling.
+	PerConnectionBytesPerSecond int64 `mapstructure:"per_connection_bytes_per_second"`
+	// PerUserBytesPerSecond caps a user's combined upload/download
+	// throughput across concurrent streams. 0 disables it.
+	PerUserBytesPerSecond int64 `mapstructure:"per_user_bytes_per_second"`
+	// MaxSecretsPerType caps how many secrets of one type (credential,
+	// generic, etc.) a single user may own, as an anti-abuse measure for
+	// public deployments. 0 disables the cap.
+	MaxSecretsPerType int `mapstructure:"max_secrets_per_type"`
+}
+
+// CryptoConfig supplies the at-rest encryption keyring: a primary key used
+// for all new writes, plus any number of legacy keys kept only so older
+// records can still be decrypted during a rollover. Keys are hex-encoded
+// AES-256 keys (32 bytes).
+type CryptoConfig struct {
+	PrimaryKeyID string            `mapstructure:"primary_key_id"`
+	Keys         map[string]string `mapstructure:"keys"`
+}
+
+// TelegramConfig configures the optional Telegram bot integration. Bot is
+// disabled unless BotToken is set.
+type TelegramConfig struct {
+	BotToken string `mapstructure:"bot_token"`
+}
+
+// FaultInjectionConfig configures synthetic latency/errors on storage
+// dependencies. It exists for dev/test environments exercising retry and
+// sync logic and must be left at its zero value (disabled) in production.
+type FaultInjectionConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	LatencyMin time.Duration `mapstructure:"latency_min"`
+	LatencyMax time.Duration `mapstructure:"latency_max"`
+	ErrorRate  float64       `mapstructure:"error_rate"`
+}
+
+// MultiTenancyConfig controls whether new accounts are labeled with a
+// tenant ID (see user.User.TenantID). This is account- and token-level
+// grouping only: a user's JWT is rejected once their tenant label changes,
+// and SCIM-provisioned accounts inherit the tenant their identity provider
+// integration is configured for. It does not partition secret data,
+// object storage, or quotas by tenant. Disabled by default: every
+// account's TenantID is empty and behaves as a single shared tenant.
+type MultiTenancyConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// RetentionConfig controls how long trashed (soft-deleted) secrets are kept
+// before the purge job deletes them permanently. Accounts may override
+// DefaultTrashRetention individually.
+type RetentionConfig struct {
+	DefaultTrashRetention time.Duration `mapstructure:"default_trash_retention"`
+}
+
+// DebugConfig controls the sampled request/response recorder (see
+// internal/reqrecorder and middlewares.Recorder), exposed for reproducing
+// client-reported issues from the admin port. It exists for dev/staging
+// environments and must be left at its zero value (recording disabled) in
+// production, the same rule as FaultInjectionConfig: even redacted, a
+// recorded body is a copy of real user data sitting in process memory.
+type DebugConfig struct {
+	// RecordSampleRate is the fraction (0-1) of requests recorded. 0 (the
+	// default) disables recording entirely.
+	RecordSampleRate float64 `mapstructure:"record_sample_rate"`
+	// RecordBufferSize caps how many recorded request/response pairs are
+	// kept in memory at once; the oldest is evicted once it fills up.
+	RecordBufferSize int `mapstructure:"record_buffer_size"`
+	// RecordMaxBodyBytes caps how much of each request/response body is
+	// captured before redaction.
+	RecordMaxBodyBytes int64 `mapstructure:"record_max_body_bytes"`
+}
+
+func defaults() Config {
+	return Config{
+		Server: ServerConfig{
+			Address:            "localhost:8080",
+			RequestTimeout:     10 * time.Second,
+			LongRequestTimeout: 5 * time.Minute,
+		},
+		Auth:      AuthConfig{TokenTTL: 24 * time.Hour},
+		Limits:    LimitsConfig{MaxUploadSizeBytes: 100 << 20, MaxListPageSize: 100, MaxFileVersions: 5},
+		Retention: RetentionConfig{DefaultTrashRetention: 30 * 24 * time.Hour},
+	}
+}
+
+// Load reads configuration from, in increasing precedence: built-in
+// defaults, the file at configPath (if non-empty; format inferred from its
+// extension, e.g. .yaml/.yml/.toml), and KEEPER_-prefixed environment
+// variables, where nested keys use an underscore (KEEPER_SERVER_ADDRESS ->
+// server.address).
+func Load(configPath string) (*Config, error) {
+	v := viper.New()
+
+	def := defaults()
+	v.SetDefault("server", def.Server)
+	v.SetDefault("auth", def.Auth)
+	v.SetDefault("limits", def.Limits)
+	v.SetDefault("retention", def.Retention)
+
+	if configPath != "" {
+		v.SetConfigFile(configPath)
+
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("read config file: %w", err)
+		}
+	}
+
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	bindNestedEnvKeys(v)
+
+	cfg := &Config{}
+	if err := v.Unmarshal(cfg); err != nil {
+		return nil, fmt.Errorf("unmarshal config: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// bindNestedEnvKeys binds each of Config's nested keys listed below so
+// viper.AutomaticEnv picks up keys like server.address even when no config
+// file set them first (viper otherwise only auto-binds keys it already knows
+// about). crypto.keys, a map, is not bound.
+func bindNestedEnvKeys(v *viper.Viper) {
+	for _, key := range []string{
+		"server.address", "server.admin_address", "server.request_timeout", "server.long_request_timeout",
+		"db.dsn",
+		"s3.endpoint", "s3.bucket", "s3.access_key", "s3.secret_key", "s3.use_ssl",
+		"auth.jwt_secret", "auth.token_ttl", "auth.max_lifetime", "auth.bcrypt_cost",
+		"limits.max_upload_size_bytes", "limits.max_list_page_size",
+		"limits.allowed_content_types", "limits.denied_content_types", "limits.max_file_versions",
+		"limits.per_connection_bytes_per_second", "limits.per_user_bytes_per_second", "limits.max_secrets_per_type",
+		"telegram.bot_token",
+		"fault_injection.enabled", "fault_injection.latency_min", "fault_injection.latency_max", "fault_injection.error_rate",
+		"crypto.primary_key_id",
+		"multi_tenancy.enabled",
+		"retention.default_trash_retention",
+		"debug.record_sample_rate", "debug.record_buffer_size", "debug.record_max_body_bytes",
+	} {
+		_ = v.BindEnv(key)
+	}
+}
@@ -0,0 +1,57 @@
+package cryptutils
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/contentinfo"
+)
+
+// Encrypt encrypts plaintext with key using the same AES-CTR construction as
+// EncryptStream (random IV prepended to ciphertext), for small in-memory
+// fields like a credential's login/password where a full stream pipeline
+// would be overkill. Metrics are recorded by EncryptStream.
+func Encrypt(plaintext, key []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := EncryptStream(&buf, bytes.NewReader(plaintext), key, contentinfo.CompressionNone); err != nil {
+		return nil, fmt.Errorf("encrypt field: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Decrypt reverses Encrypt. Metrics are recorded by DecryptStream.
+func Decrypt(ciphertext, key []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := DecryptStream(&buf, bytes.NewReader(ciphertext), key, contentinfo.CompressionNone); err != nil {
+		return nil, fmt.Errorf("decrypt field: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// BlindIndex returns a deterministic, non-reversible HMAC-SHA256 of value
+// under key, hex-encoded, for equality lookups on otherwise-encrypted
+// fields (e.g. "find the credential secret whose login is X") without
+// decrypting every row to compare. Callers should normalize value (e.g.
+// lowercase, trim) before calling so lookups aren't sensitive to casing the
+// encrypted field itself doesn't care about. Like field encryption, the
+// index is only as stable as the key it was computed under: rotating key
+// invalidates indexes computed under the retired key, same as Encrypt.
+func BlindIndex(value string, key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// NormalizeLogin lowercases and trims a login value before it's encrypted,
+// hashed into a BlindIndex, or compared, so "Alice@Example.com" and
+// " alice@example.com " resolve to the same record.
+func NormalizeLogin(login string) string {
+	return strings.ToLower(strings.TrimSpace(login))
+}
@@ -0,0 +1,74 @@
+package cryptutils
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// Keyring holds the primary encryption key plus retired keys still needed
+// to decrypt older records, enabling key rollover without downtime: new
+// writes always use the primary, while reads select by KeyID (falling back
+// to the primary for records written before key IDs existed).
+type Keyring struct {
+	primaryID string
+	keys      map[string][]byte
+}
+
+// NewKeyring returns a Keyring whose primary key is primaryID, plus any
+// additional legacy keys still needed to decrypt older records. It returns
+// an error if primaryID isn't present in keys.
+func NewKeyring(primaryID string, keys map[string][]byte) (*Keyring, error) {
+	if _, ok := keys[primaryID]; !ok {
+		return nil, fmt.Errorf("cryptutils: primary key id %q not found in keys", primaryID)
+	}
+
+	clone := make(map[string][]byte, len(keys))
+	for id, key := range keys {
+		clone[id] = key
+	}
+
+	return &Keyring{primaryID: primaryID, keys: clone}, nil
+}
+
+// NewKeyringFromHex is NewKeyring for hex-encoded key material, as supplied
+// by config.
+func NewKeyringFromHex(primaryID string, hexKeys map[string]string) (*Keyring, error) {
+	keys := make(map[string][]byte, len(hexKeys))
+
+	for id, hexKey := range hexKeys {
+		key, err := hex.DecodeString(hexKey)
+		if err != nil {
+			return nil, fmt.Errorf("decode key %q: %w", id, err)
+		}
+
+		keys[id] = key
+	}
+
+	return NewKeyring(primaryID, keys)
+}
+
+// PrimaryKeyID returns the key ID all new writes should be recorded under.
+func (k *Keyring) PrimaryKeyID() string {
+	return k.primaryID
+}
+
+// PrimaryKey returns the primary encryption key, for new writes.
+func (k *Keyring) PrimaryKey() []byte {
+	return k.keys[k.primaryID]
+}
+
+// Key returns the key for keyID, so decryption can select the key a record
+// was actually encrypted under. An empty keyID is treated as the primary,
+// for records written before key IDs existed.
+func (k *Keyring) Key(keyID string) ([]byte, error) {
+	if keyID == "" {
+		keyID = k.primaryID
+	}
+
+	key, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("cryptutils: unknown key id %q", keyID)
+	}
+
+	return key, nil
+}
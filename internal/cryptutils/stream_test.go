@@ -0,0 +1,106 @@
+package cryptutils_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/andymarkow/gophkeeper/internal/cryptutils"
+	"github.com/andymarkow/gophkeeper/internal/domain/contentinfo"
+)
+
+func testKey() []byte {
+	return bytes.Repeat([]byte{0x42}, 32)
+}
+
+func TestEncryptDecryptStream_RoundTrip(t *testing.T) {
+	plaintext := strings.Repeat("the quick brown fox jumps over the lazy dog ", 200)
+
+	tests := []struct {
+		name string
+		algo contentinfo.CompressionAlgo
+	}{
+		{"none", contentinfo.CompressionNone},
+		{"gzip", contentinfo.CompressionGzip},
+		{"zstd", contentinfo.CompressionZstd},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := testKey()
+
+			var encrypted bytes.Buffer
+			if err := cryptutils.EncryptStream(&encrypted, strings.NewReader(plaintext), key, tt.algo); err != nil {
+				t.Fatalf("EncryptStream() error = %v", err)
+			}
+
+			var decrypted bytes.Buffer
+			if err := cryptutils.DecryptStream(&decrypted, &encrypted, key, tt.algo); err != nil {
+				t.Fatalf("DecryptStream() error = %v", err)
+			}
+
+			if decrypted.String() != plaintext {
+				t.Fatalf("round trip mismatch: got %d bytes, want %d bytes", decrypted.Len(), len(plaintext))
+			}
+		})
+	}
+}
+
+func TestDecryptStream_WrongKeyProducesGarbage(t *testing.T) {
+	plaintext := "some secret content that should not survive under the wrong key"
+
+	var encrypted bytes.Buffer
+	if err := cryptutils.EncryptStream(&encrypted, strings.NewReader(plaintext), testKey(), contentinfo.CompressionNone); err != nil {
+		t.Fatalf("EncryptStream() error = %v", err)
+	}
+
+	wrongKey := bytes.Repeat([]byte{0x99}, 32)
+
+	var decrypted bytes.Buffer
+	// AES-CTR has no authentication tag, so decrypting with the wrong key
+	// doesn't itself error out; it just can't reproduce the plaintext.
+	// This is why object storage content must never be trusted without a
+	// separate integrity check (see internal/service/integritysvc).
+	if err := cryptutils.DecryptStream(&decrypted, &encrypted, wrongKey, contentinfo.CompressionNone); err != nil {
+		t.Fatalf("DecryptStream() error = %v", err)
+	}
+
+	if decrypted.String() == plaintext {
+		t.Fatal("DecryptStream() with wrong key reproduced the original plaintext")
+	}
+}
+
+func TestDecryptStream_UnsupportedCompressionAlgo(t *testing.T) {
+	var encrypted bytes.Buffer
+	if err := cryptutils.EncryptStream(&encrypted, strings.NewReader("data"), testKey(), contentinfo.CompressionNone); err != nil {
+		t.Fatalf("EncryptStream() error = %v", err)
+	}
+
+	var decrypted bytes.Buffer
+	if err := cryptutils.DecryptStream(&decrypted, &encrypted, testKey(), contentinfo.CompressionAlgo("lz4")); err == nil {
+		t.Fatal("DecryptStream() with unsupported algo error = nil, want error")
+	}
+}
+
+func TestDecryptStreamN_TruncatesWithoutReadingWholeStream(t *testing.T) {
+	plaintext := strings.Repeat("abcdefghij", 1000)
+	key := testKey()
+
+	var encrypted bytes.Buffer
+	if err := cryptutils.EncryptStream(&encrypted, strings.NewReader(plaintext), key, contentinfo.CompressionNone); err != nil {
+		t.Fatalf("EncryptStream() error = %v", err)
+	}
+
+	var preview bytes.Buffer
+	if err := cryptutils.DecryptStreamN(&preview, &encrypted, key, 50); err != nil {
+		t.Fatalf("DecryptStreamN() error = %v", err)
+	}
+
+	if preview.String() != plaintext[:50] {
+		t.Fatalf("DecryptStreamN() = %q, want %q", preview.String(), plaintext[:50])
+	}
+
+	if got, want := encrypted.Len(), len(plaintext)-50; got != want {
+		t.Fatalf("DecryptStreamN() left %d ciphertext bytes unread, want %d", got, want)
+	}
+}
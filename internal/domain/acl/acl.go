@@ -0,0 +1,74 @@
+// Package acl defines per-secret access control entries shared by every
+// secret domain type.
+package acl
+
+import "strings"
+
+// Permission is a level of access an ACL entry grants.
+type Permission string
+
+const (
+	PermissionRead  Permission = "read"
+	PermissionWrite Permission = "write"
+)
+
+// Principal identifies who an entry applies to: "user:<id>" or
+// "role:<name>".
+type Principal string
+
+// UserPrincipal returns the Principal for a specific user ID.
+func UserPrincipal(userID string) Principal {
+	return Principal("user:" + userID)
+}
+
+// RolePrincipal returns the Principal for a role name.
+func RolePrincipal(role string) Principal {
+	return Principal("role:" + role)
+}
+
+// Entry grants principal the given permission on a secret.
+type Entry struct {
+	Principal  Principal
+	Permission Permission
+}
+
+// List is the set of ACL entries attached to a secret, beyond the implicit
+// full access its owner always has.
+type List []Entry
+
+// Allows reports whether any entry in the list grants principal at least
+// perm. Write implies read.
+func (l List) Allows(principal Principal, perm Permission) bool {
+	for _, e := range l {
+		if e.Principal != principal {
+			continue
+		}
+
+		if e.Permission == perm || e.Permission == PermissionWrite {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AllowsRole reports whether any of roles grants perm via a role entry.
+func (l List) AllowsRole(roles []string, perm Permission) bool {
+	for _, role := range roles {
+		if l.Allows(RolePrincipal(role), perm) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String returns "user:<id>" or "role:<name>" unchanged, for display.
+func (p Principal) String() string {
+	return string(p)
+}
+
+// IsRole reports whether p identifies a role rather than a user.
+func (p Principal) IsRole() bool {
+	return strings.HasPrefix(string(p), "role:")
+}
@@ -0,0 +1,60 @@
+// Package apitoken defines the API token secret domain type, for credentials
+// issued by a third-party service (a PAT, a signing key, a service account
+// token) rather than a username/password pair.
+package apitoken
+
+import (
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/acl"
+	"github.com/andymarkow/gophkeeper/internal/domain/metadata"
+)
+
+// Secret is an encrypted API token owned by a user.
+type Secret struct {
+	ID     string
+	UserID string
+	Name   string
+	// ServiceName identifies what issued the token (e.g. "GitHub", "Stripe"),
+	// shown alongside the masked token in list responses.
+	ServiceName string
+	Token       []byte // encrypted
+	// Scopes lists the permissions the token was issued with, as reported
+	// by the issuing service (e.g. "repo", "read:user"). Not secret on its
+	// own, so it is stored and returned in plaintext.
+	Scopes []string
+	// ExpiresAt is when the issuing service stops honoring the token, if it
+	// reported one. Nil means the token doesn't expire or the expiry is
+	// unknown.
+	ExpiresAt *time.Time
+	// ExpiryNotifiedAt records when the expiring-soon notification job last
+	// warned this token's owner, so it isn't re-sent every run. Nil if no
+	// warning has been sent since the token was last created or replaced.
+	ExpiryNotifiedAt *time.Time
+	// KeyID identifies which master/KEK version encrypted Token, so
+	// decryption selects the matching key and rotation jobs can find
+	// records still under a retired key.
+	KeyID     string
+	Metadata  metadata.Bag
+	ACL       acl.List
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// SetMetadata replaces the secret's metadata entirely. patch must not
+// contain system-namespaced keys; see metadata.SystemKeyPrefix.
+func (s *Secret) SetMetadata(patch metadata.Bag) error {
+	return s.Metadata.SetUser(patch)
+}
+
+// MergeMetadata merges patch into the secret's existing metadata, overwriting
+// keys present in both. patch must not contain system-namespaced keys; see
+// metadata.SystemKeyPrefix.
+func (s *Secret) MergeMetadata(patch metadata.Bag) error {
+	return s.Metadata.MergeUser(patch)
+}
+
+// RemoveMetadata deletes the given keys from the secret's metadata.
+func (s *Secret) RemoveMetadata(keys ...string) {
+	s.Metadata.Remove(keys...)
+}
@@ -0,0 +1,50 @@
+// Package bankcard defines the bank card secret domain type.
+package bankcard
+
+import (
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/acl"
+	"github.com/andymarkow/gophkeeper/internal/domain/metadata"
+)
+
+// Secret is an encrypted bank card owned by a user.
+type Secret struct {
+	ID         string
+	UserID     string
+	Name       string
+	Number     []byte // encrypted
+	Holder     []byte // encrypted
+	ExpiryDate []byte // encrypted
+	CVV        []byte // encrypted
+	// KeyID identifies which master/KEK version encrypted the fields
+	// above, so decryption selects the matching key and rotation jobs
+	// can find records still under a retired key.
+	KeyID     string
+	Metadata  metadata.Bag
+	ACL       acl.List
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// ExpiryNotifiedAt records when the expiring-soon notification job last
+	// warned this card's owner, so it isn't re-sent every run. Nil if no
+	// warning has been sent since the card was last created or replaced.
+	ExpiryNotifiedAt *time.Time
+}
+
+// SetMetadata replaces the secret's metadata entirely. patch must not
+// contain system-namespaced keys; see metadata.SystemKeyPrefix.
+func (s *Secret) SetMetadata(patch metadata.Bag) error {
+	return s.Metadata.SetUser(patch)
+}
+
+// MergeMetadata merges patch into the secret's existing metadata, overwriting
+// keys present in both. patch must not contain system-namespaced keys; see
+// metadata.SystemKeyPrefix.
+func (s *Secret) MergeMetadata(patch metadata.Bag) error {
+	return s.Metadata.MergeUser(patch)
+}
+
+// RemoveMetadata deletes the given keys from the secret's metadata.
+func (s *Secret) RemoveMetadata(keys ...string) {
+	s.Metadata.Remove(keys...)
+}
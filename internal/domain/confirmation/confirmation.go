@@ -0,0 +1,18 @@
+// Package confirmation defines the one-time ticket a "prepare" call issues
+// and a destructive action later consumes, so an accidental click or a
+// replayed request can't repeat the action a second time.
+package confirmation
+
+import "time"
+
+// Ticket is a single-use confirmation scoped to one action against one
+// target, issued by a prior prepare call and consumed by the guarded
+// action itself.
+type Ticket struct {
+	ID         string
+	Action     string
+	Target     string
+	IssuedBy   string
+	ExpiresAt  time.Time
+	ConsumedAt *time.Time
+}
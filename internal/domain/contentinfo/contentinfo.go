@@ -0,0 +1,80 @@
+// Package contentinfo describes the object-storage-backed payload of a file
+// or text secret: where it lives and how it was encoded before upload.
+package contentinfo
+
+import (
+	"strings"
+	"time"
+)
+
+// CompressionAlgo identifies a compression codec applied before encryption.
+type CompressionAlgo string
+
+const (
+	CompressionNone CompressionAlgo = ""
+	CompressionGzip CompressionAlgo = "gzip"
+	CompressionZstd CompressionAlgo = "zstd"
+)
+
+// ChecksumAlgo identifies the hash algorithm a Checksum was computed with.
+type ChecksumAlgo string
+
+// ChecksumSHA256 is the only algorithm this codebase computes checksums
+// with today.
+const ChecksumSHA256 ChecksumAlgo = "sha256"
+
+// Checksum is a canonical "algo:hex" content checksum, e.g.
+// "sha256:2cf24dba5fb0a30e...". The zero value means no checksum recorded.
+// Tying the algorithm to the value keeps it unambiguous if a second
+// algorithm (e.g. a faster non-cryptographic one for dedup) is ever added
+// alongside SHA-256.
+type Checksum string
+
+// NewChecksum returns the canonical Checksum combining algo and a digest
+// already hex-encoded by the caller (e.g. via hex.EncodeToString).
+func NewChecksum(algo ChecksumAlgo, hexDigest string) Checksum {
+	return Checksum(string(algo) + ":" + hexDigest)
+}
+
+// Algo returns c's algorithm component, or "" if c is empty or carries no
+// "algo:" prefix (e.g. a pre-migration bare-hex value).
+func (c Checksum) Algo() ChecksumAlgo {
+	algo, _, ok := strings.Cut(string(c), ":")
+	if !ok {
+		return ""
+	}
+
+	return ChecksumAlgo(algo)
+}
+
+// Hex returns c's hex digest component. For a pre-migration bare-hex value
+// with no "algo:" prefix, it returns c unchanged, so callers comparing
+// digests don't need a separate migration path for old data.
+func (c Checksum) Hex() string {
+	_, hexDigest, ok := strings.Cut(string(c), ":")
+	if !ok {
+		return string(c)
+	}
+
+	return hexDigest
+}
+
+// String returns c's canonical "algo:hex" representation.
+func (c Checksum) String() string {
+	return string(c)
+}
+
+// ContentInfo records where a secret's payload lives in object storage and
+// how it was transformed before upload, so downloads can reverse the same
+// pipeline in order.
+type ContentInfo struct {
+	ObjectKey       string
+	Size            int64
+	Checksum        Checksum
+	CompressionAlgo CompressionAlgo
+	// KeyID identifies which master/KEK version encrypted this payload,
+	// so decryption selects the matching key and rotation jobs can find
+	// records still under a retired key.
+	KeyID     string
+	CreatedAt time.Time
+}
@@ -0,0 +1,57 @@
+// Package credential defines the login/password secret domain type.
+package credential
+
+import (
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/acl"
+	"github.com/andymarkow/gophkeeper/internal/domain/metadata"
+)
+
+// Secret is an encrypted login/password pair owned by a user.
+type Secret struct {
+	ID       string
+	UserID   string
+	Name     string
+	Login    []byte // encrypted
+	Password []byte // encrypted
+	// KeyID identifies which master/KEK version encrypted Login and
+	// Password, so decryption selects the matching key and rotation jobs
+	// can find records still under a retired key.
+	KeyID string
+	// LoginIndex is an HMAC blind index of the normalized plaintext Login
+	// (see cryptutils.BlindIndex), letting a lookup by login find this
+	// secret by equality without decrypting Login first. Like KeyID, it
+	// goes stale if the key it was computed under is later retired.
+	LoginIndex string
+	Metadata   metadata.Bag
+	ACL        acl.List
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// SetMetadata replaces the secret's metadata entirely. patch must not
+// contain system-namespaced keys; see metadata.SystemKeyPrefix.
+func (s *Secret) SetMetadata(patch metadata.Bag) error {
+	return s.Metadata.SetUser(patch)
+}
+
+// MergeMetadata merges patch into the secret's existing metadata, overwriting
+// keys present in both. patch must not contain system-namespaced keys; see
+// metadata.SystemKeyPrefix.
+func (s *Secret) MergeMetadata(patch metadata.Bag) error {
+	return s.Metadata.MergeUser(patch)
+}
+
+// RemoveMetadata deletes the given keys from the secret's metadata.
+func (s *Secret) RemoveMetadata(keys ...string) {
+	s.Metadata.Remove(keys...)
+}
+
+// AddMetadata merges patch into the secret's existing metadata.
+//
+// Deprecated: kept as an alias of MergeMetadata for callers that predate the
+// explicit Set/Merge/Remove split; previously this replaced the whole map.
+func (s *Secret) AddMetadata(patch metadata.Bag) error {
+	return s.MergeMetadata(patch)
+}
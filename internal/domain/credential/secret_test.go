@@ -0,0 +1,31 @@
+package credential_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/credential"
+	"github.com/andymarkow/gophkeeper/internal/domain/metadata"
+)
+
+func TestSecret_AddMetadata_Merges(t *testing.T) {
+	s := &credential.Secret{Metadata: metadata.Bag{"a": "1"}}
+
+	s.AddMetadata(metadata.Bag{"b": "2"})
+
+	want := metadata.Bag{"a": "1", "b": "2"}
+	if !reflect.DeepEqual(s.Metadata, want) {
+		t.Fatalf("AddMetadata() left Metadata = %v, want %v", s.Metadata, want)
+	}
+}
+
+func TestSecret_SetMetadata_Replaces(t *testing.T) {
+	s := &credential.Secret{Metadata: metadata.Bag{"a": "1"}}
+
+	s.SetMetadata(metadata.Bag{"b": "2"})
+
+	want := metadata.Bag{"b": "2"}
+	if !reflect.DeepEqual(s.Metadata, want) {
+		t.Fatalf("SetMetadata() left Metadata = %v, want %v", s.Metadata, want)
+	}
+}
@@ -0,0 +1,22 @@
+// Package deadletter defines the record kept for a notification that failed
+// delivery after every retry was exhausted, so it can be inspected and
+// replayed instead of silently dropped.
+package deadletter
+
+import "time"
+
+// Letter is one failed notification delivery attempt chain.
+type Letter struct {
+	ID       string
+	UserID   string
+	Channel  string
+	Kind     string
+	Address  string
+	Subject  string
+	Body     string
+	Error    string
+	Attempts int
+
+	CreatedAt     time.Time
+	LastAttemptAt time.Time
+}
@@ -0,0 +1,57 @@
+// Package generic defines the generic key/value secret domain type, for
+// data that doesn't fit any of the purpose-built secret types: a
+// user-declared schema describing the fields, and a single encrypted blob
+// holding their values as JSON.
+package generic
+
+import (
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/acl"
+	"github.com/andymarkow/gophkeeper/internal/domain/metadata"
+)
+
+// FieldSpec describes one field of a generic secret's user-declared schema.
+// Type is a free-form hint ("string", "number", "bool", "date", ...) for
+// clients to render an appropriate input; the server does not validate
+// Payload against it.
+type FieldSpec struct {
+	Key  string
+	Type string
+}
+
+// Secret is an encrypted arbitrary key/value payload owned by a user, with
+// a schema describing its shape.
+type Secret struct {
+	ID      string
+	UserID  string
+	Name    string
+	Schema  []FieldSpec
+	Payload []byte // encrypted JSON object keyed by each FieldSpec.Key
+	// KeyID identifies which master/KEK version encrypted Payload, so
+	// decryption selects the matching key and rotation jobs can find
+	// records still under a retired key.
+	KeyID     string
+	Metadata  metadata.Bag
+	ACL       acl.List
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// SetMetadata replaces the secret's metadata entirely. patch must not
+// contain system-namespaced keys; see metadata.SystemKeyPrefix.
+func (s *Secret) SetMetadata(patch metadata.Bag) error {
+	return s.Metadata.SetUser(patch)
+}
+
+// MergeMetadata merges patch into the secret's existing metadata, overwriting
+// keys present in both. patch must not contain system-namespaced keys; see
+// metadata.SystemKeyPrefix.
+func (s *Secret) MergeMetadata(patch metadata.Bag) error {
+	return s.Metadata.MergeUser(patch)
+}
+
+// RemoveMetadata deletes the given keys from the secret's metadata.
+func (s *Secret) RemoveMetadata(keys ...string) {
+	s.Metadata.Remove(keys...)
+}
@@ -0,0 +1,23 @@
+package metadata
+
+// HighSecurityKey is the system metadata key marking a secret as requiring
+// step-up authentication before its decrypted data is returned. It lives
+// under SystemKeyPrefix since only the server (via the step-up endpoint)
+// should be able to set or clear it.
+const HighSecurityKey = SystemKeyPrefix + "high_security"
+
+// IsHighSecurity reports whether b marks its secret as high security.
+func IsHighSecurity(b Bag) bool {
+	return b[HighSecurityKey] == "true"
+}
+
+// MarkHighSecurity sets or clears the high-security flag on b, allocating
+// the bag if needed.
+func MarkHighSecurity(b *Bag, highSecurity bool) {
+	value := "false"
+	if highSecurity {
+		value = "true"
+	}
+
+	b.MergeSystem(Bag{HighSecurityKey: value})
+}
@@ -0,0 +1,131 @@
+// Package metadata provides a small key/value bag shared by every secret
+// domain type (credential, bankcard, text, file) so that metadata handling
+// behaves identically across them.
+package metadata
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SystemKeyPrefix marks a metadata key as reserved for the server's own use
+// (import source, template id, client version, ...). Keys under this
+// namespace can only be written through MergeSystem/SetSystem; MergeUser and
+// SetUser reject them so a user can never forge a system annotation by
+// submitting it through the regular metadata update path.
+const SystemKeyPrefix = "system."
+
+// ErrReservedKey is returned by MergeUser/SetUser when patch contains a key
+// under SystemKeyPrefix.
+var ErrReservedKey = errors.New("metadata: key uses the reserved \"system.\" prefix")
+
+// IsReservedKey reports whether key belongs to the system namespace.
+func IsReservedKey(key string) bool {
+	return strings.HasPrefix(key, SystemKeyPrefix)
+}
+
+// Bag is a set of key/value pairs attached to a secret. Most entries are
+// user-defined; entries under SystemKeyPrefix are written by the server
+// itself, see MergeSystem.
+type Bag map[string]string
+
+// Clone returns a deep copy of the bag. A nil bag clones to nil.
+func (b Bag) Clone() Bag {
+	if b == nil {
+		return nil
+	}
+
+	clone := make(Bag, len(b))
+	for k, v := range b {
+		clone[k] = v
+	}
+
+	return clone
+}
+
+// Merge copies every key from patch into the bag, overwriting existing keys
+// and allocating the bag if it is nil. The receiver must be passed as a
+// pointer so a nil bag can be initialized in place.
+func (b *Bag) Merge(patch Bag) {
+	if len(patch) == 0 {
+		return
+	}
+
+	if *b == nil {
+		*b = make(Bag, len(patch))
+	}
+
+	for k, v := range patch {
+		(*b)[k] = v
+	}
+}
+
+// Set replaces the bag entirely with patch, discarding any existing keys.
+func (b *Bag) Set(patch Bag) {
+	*b = patch.Clone()
+}
+
+// Remove deletes the given keys from the bag, if present.
+func (b *Bag) Remove(keys ...string) {
+	if *b == nil {
+		return
+	}
+
+	for _, k := range keys {
+		delete(*b, k)
+	}
+}
+
+// MergeUser merges patch as if by Merge, rejecting it with ErrReservedKey if
+// it contains any system-namespaced key. Use this for metadata supplied by
+// a user request; use MergeSystem for annotations the server writes itself.
+func (b *Bag) MergeUser(patch Bag) error {
+	if err := validateUser(patch); err != nil {
+		return err
+	}
+
+	b.Merge(patch)
+
+	return nil
+}
+
+// SetUser replaces the user-writable portion of the bag with patch,
+// rejecting patch with ErrReservedKey if it contains any system-namespaced
+// key. Existing system-namespaced entries (written via MergeSystem) are
+// carried over unchanged instead of being discarded, since a user-initiated
+// full replace must not be able to erase server-owned annotations.
+func (b *Bag) SetUser(patch Bag) error {
+	if err := validateUser(patch); err != nil {
+		return err
+	}
+
+	system := make(Bag)
+	for k, v := range *b {
+		if IsReservedKey(k) {
+			system[k] = v
+		}
+	}
+
+	b.Set(patch)
+	b.MergeSystem(system)
+
+	return nil
+}
+
+// MergeSystem merges patch into the bag without restriction, for the server
+// to write system-namespaced annotations such as import source or template
+// id.
+func (b *Bag) MergeSystem(patch Bag) {
+	b.Merge(patch)
+}
+
+func validateUser(patch Bag) error {
+	for k := range patch {
+		if IsReservedKey(k) {
+			return fmt.Errorf("%w: %q", ErrReservedKey, k)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package metadata_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/metadata"
+)
+
+func TestBag_Merge(t *testing.T) {
+	b := metadata.Bag{"a": "1"}
+
+	b.Merge(metadata.Bag{"b": "2", "a": "3"})
+
+	want := metadata.Bag{"a": "3", "b": "2"}
+	if !reflect.DeepEqual(b, want) {
+		t.Fatalf("Merge() = %v, want %v", b, want)
+	}
+}
+
+func TestBag_Merge_NilReceiver(t *testing.T) {
+	var b metadata.Bag
+
+	b.Merge(metadata.Bag{"a": "1"})
+
+	want := metadata.Bag{"a": "1"}
+	if !reflect.DeepEqual(b, want) {
+		t.Fatalf("Merge() = %v, want %v", b, want)
+	}
+}
+
+func TestBag_Set(t *testing.T) {
+	b := metadata.Bag{"a": "1", "b": "2"}
+
+	b.Set(metadata.Bag{"c": "3"})
+
+	want := metadata.Bag{"c": "3"}
+	if !reflect.DeepEqual(b, want) {
+		t.Fatalf("Set() = %v, want %v", b, want)
+	}
+}
+
+func TestBag_Remove(t *testing.T) {
+	b := metadata.Bag{"a": "1", "b": "2"}
+
+	b.Remove("a", "missing")
+
+	want := metadata.Bag{"b": "2"}
+	if !reflect.DeepEqual(b, want) {
+		t.Fatalf("Remove() = %v, want %v", b, want)
+	}
+}
+
+func TestBag_MergeUser_RejectsReservedKey(t *testing.T) {
+	b := metadata.Bag{"a": "1"}
+
+	err := b.MergeUser(metadata.Bag{"system.template_id": "abc"})
+	if !errors.Is(err, metadata.ErrReservedKey) {
+		t.Fatalf("MergeUser() error = %v, want ErrReservedKey", err)
+	}
+
+	want := metadata.Bag{"a": "1"}
+	if !reflect.DeepEqual(b, want) {
+		t.Fatalf("MergeUser() mutated bag on rejection: %v, want %v", b, want)
+	}
+}
+
+func TestBag_SetUser_RejectsReservedKey(t *testing.T) {
+	var b metadata.Bag
+
+	err := b.SetUser(metadata.Bag{"system.source": "import"})
+	if !errors.Is(err, metadata.ErrReservedKey) {
+		t.Fatalf("SetUser() error = %v, want ErrReservedKey", err)
+	}
+}
+
+func TestBag_SetUser_PreservesExistingSystemKeys(t *testing.T) {
+	b := metadata.Bag{"a": "1", "system.high_security": "true"}
+
+	if err := b.SetUser(metadata.Bag{"b": "2"}); err != nil {
+		t.Fatalf("SetUser() error = %v, want nil", err)
+	}
+
+	want := metadata.Bag{"b": "2", "system.high_security": "true"}
+	if !reflect.DeepEqual(b, want) {
+		t.Fatalf("SetUser() = %v, want %v", b, want)
+	}
+}
+
+func TestBag_MergeSystem_AllowsReservedKey(t *testing.T) {
+	b := metadata.Bag{"a": "1"}
+
+	b.MergeSystem(metadata.Bag{"system.source": "import"})
+
+	want := metadata.Bag{"a": "1", "system.source": "import"}
+	if !reflect.DeepEqual(b, want) {
+		t.Fatalf("MergeSystem() = %v, want %v", b, want)
+	}
+}
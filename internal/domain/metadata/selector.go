@@ -0,0 +1,94 @@
+package metadata
+
+import (
+	"fmt"
+	"strings"
+)
+
+// op is a single selector requirement's comparison.
+type op string
+
+const (
+	opEquals    op = "="
+	opNotEquals op = "!="
+)
+
+// requirement is one comma-separated term of a Selector, e.g. "env=prod" or
+// "team!=infra".
+type requirement struct {
+	key   string
+	op    op
+	value string
+}
+
+// Selector is a parsed Kubernetes-style label selector over a Bag, e.g.
+// "env=prod,team!=infra". It is evaluated with Matches against a secret's
+// metadata on list endpoints.
+type Selector struct {
+	requirements []requirement
+}
+
+// ParseSelector parses a comma-separated selector expression. An empty
+// expression parses to a Selector that matches everything.
+func ParseSelector(expr string) (Selector, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return Selector{}, nil
+	}
+
+	terms := strings.Split(expr, ",")
+	reqs := make([]requirement, 0, len(terms))
+
+	for _, term := range terms {
+		term = strings.TrimSpace(term)
+
+		switch {
+		case strings.Contains(term, "!="):
+			parts := strings.SplitN(term, "!=", 2)
+			reqs = append(reqs, requirement{key: strings.TrimSpace(parts[0]), op: opNotEquals, value: strings.TrimSpace(parts[1])})
+		case strings.Contains(term, "="):
+			parts := strings.SplitN(term, "=", 2)
+			reqs = append(reqs, requirement{key: strings.TrimSpace(parts[0]), op: opEquals, value: strings.TrimSpace(parts[1])})
+		default:
+			return Selector{}, fmt.Errorf("invalid selector term %q", term)
+		}
+
+		if reqs[len(reqs)-1].key == "" {
+			return Selector{}, fmt.Errorf("invalid selector term %q: empty key", term)
+		}
+	}
+
+	return Selector{requirements: reqs}, nil
+}
+
+// AddEquals returns a copy of s with an additional "key=value" requirement,
+// for building a Selector programmatically (e.g. from individual
+// "metadata.key=value" query parameters) alongside or instead of parsing an
+// expression string with ParseSelector.
+func (s Selector) AddEquals(key, value string) Selector {
+	reqs := make([]requirement, len(s.requirements), len(s.requirements)+1)
+	copy(reqs, s.requirements)
+
+	return Selector{requirements: append(reqs, requirement{key: key, op: opEquals, value: value})}
+}
+
+// Matches reports whether b satisfies every requirement in the selector. A
+// zero-value Selector matches any Bag, including a nil one.
+func (s Selector) Matches(b Bag) bool {
+	for _, req := range s.requirements {
+		v, ok := b[req.key]
+
+		switch req.op {
+		case opEquals:
+			if !ok || v != req.value {
+				return false
+			}
+		case opNotEquals:
+			if ok && v == req.value {
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,50 @@
+package metadata_test
+
+import (
+	"testing"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/metadata"
+)
+
+func TestSelector_Matches(t *testing.T) {
+	sel, err := metadata.ParseSelector("env=prod,team!=infra")
+	if err != nil {
+		t.Fatalf("ParseSelector() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		bag  metadata.Bag
+		want bool
+	}{
+		{"matches", metadata.Bag{"env": "prod", "team": "payments"}, true},
+		{"wrong env", metadata.Bag{"env": "staging", "team": "payments"}, false},
+		{"excluded team", metadata.Bag{"env": "prod", "team": "infra"}, false},
+		{"missing key", metadata.Bag{"team": "payments"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sel.Matches(tt.bag); got != tt.want {
+				t.Errorf("Matches(%v) = %v, want %v", tt.bag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelector_Matches_Empty(t *testing.T) {
+	sel, err := metadata.ParseSelector("")
+	if err != nil {
+		t.Fatalf("ParseSelector() error = %v", err)
+	}
+
+	if !sel.Matches(nil) {
+		t.Error("empty selector should match a nil bag")
+	}
+}
+
+func TestParseSelector_Invalid(t *testing.T) {
+	if _, err := metadata.ParseSelector("bogus"); err == nil {
+		t.Error("expected error for term without an operator")
+	}
+}
@@ -0,0 +1,60 @@
+// Package otp defines the TOTP secret domain type: a shared seed the server
+// can generate time-based one-time codes from, for accounts that want
+// gophkeeper itself to act as their authenticator rather than just storing
+// the seed for an external app.
+package otp
+
+import (
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/acl"
+	"github.com/andymarkow/gophkeeper/internal/domain/metadata"
+)
+
+// Secret is an encrypted TOTP seed owned by a user, plus the parameters
+// (RFC 6238) needed to derive codes from it.
+type Secret struct {
+	ID     string
+	UserID string
+	Name   string
+	// Issuer and AccountName mirror the fields encoded in an otpauth:// URI,
+	// kept alongside the seed so the client can re-render one without
+	// asking the user to re-enter them.
+	Issuer      string
+	AccountName string
+	Seed        []byte // encrypted base32-decoded TOTP seed
+	// KeyID identifies which master/KEK version encrypted Seed, so
+	// decryption selects the matching key and rotation jobs can find
+	// records still under a retired key.
+	KeyID string
+	// Algorithm is the HMAC hash backing code generation: "SHA1", "SHA256"
+	// or "SHA512". Defaults to "SHA1", the value virtually every
+	// authenticator app assumes.
+	Algorithm string
+	// Digits is the code length, typically 6.
+	Digits int
+	// Period is how long each code remains valid, typically 30 seconds.
+	Period    time.Duration
+	Metadata  metadata.Bag
+	ACL       acl.List
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// SetMetadata replaces the secret's metadata entirely. patch must not
+// contain system-namespaced keys; see metadata.SystemKeyPrefix.
+func (s *Secret) SetMetadata(patch metadata.Bag) error {
+	return s.Metadata.SetUser(patch)
+}
+
+// MergeMetadata merges patch into the secret's existing metadata, overwriting
+// keys present in both. patch must not contain system-namespaced keys; see
+// metadata.SystemKeyPrefix.
+func (s *Secret) MergeMetadata(patch metadata.Bag) error {
+	return s.Metadata.MergeUser(patch)
+}
+
+// RemoveMetadata deletes the given keys from the secret's metadata.
+func (s *Secret) RemoveMetadata(keys ...string) {
+	s.Metadata.Remove(keys...)
+}
@@ -0,0 +1,23 @@
+// Package relation defines links between secrets of any type, e.g. a
+// credential linked to the file containing its recovery codes. Secrets live
+// in separate per-type repositories with no common table, so a relation
+// references each side by its (type, id) pair rather than a foreign key.
+package relation
+
+import "time"
+
+// Ref identifies one side of a relation: a secret type name (matching the
+// domain package, e.g. "credential", "file") and its ID within that type's
+// storage.
+type Ref struct {
+	Type string
+	ID   string
+}
+
+// Relation links From to To. Relations are undirected in meaning but stored
+// with a fixed From/To order so Create is idempotent for a given pair.
+type Relation struct {
+	From      Ref
+	To        Ref
+	CreatedAt time.Time
+}
@@ -0,0 +1,57 @@
+// Package reminder defines the reminder rule domain type: a user-configured
+// notification attached to one of their secrets, firing either at an
+// absolute date or N days before that secret's own expiration.
+package reminder
+
+import "time"
+
+// SecretType identifies which secret type a Rule is attached to, so the
+// delivery job knows which repository to consult for that secret's own
+// expiration when evaluating a DaysBeforeExpiry rule.
+type SecretType string
+
+const (
+	SecretTypeBankCard SecretType = "bankcard"
+	SecretTypeAPIToken SecretType = "apitoken"
+)
+
+// Rule is a user-configured reminder attached to one secret. Exactly one of
+// RemindAt or DaysBeforeExpiry is set.
+type Rule struct {
+	ID         string
+	UserID     string
+	SecretType SecretType
+	SecretID   string
+	// RemindAt fires the reminder once, at this absolute time.
+	RemindAt *time.Time
+	// DaysBeforeExpiry fires the reminder N days before the secret's own
+	// expiration date, once that becomes known (e.g. a bank card's
+	// decrypted ExpiryDate). Meaningless for secret types with no
+	// expiration of their own.
+	DaysBeforeExpiry *int
+	// FiredAt records when the delivery job last sent this reminder, so it
+	// isn't re-sent every run. Nil if it hasn't fired yet.
+	FiredAt   *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Due reports whether the rule should fire now, given the current time and,
+// for a DaysBeforeExpiry rule, the secret's own expiration if known. A rule
+// that has already fired is never due again.
+func (r *Rule) Due(now time.Time, expiresAt *time.Time) bool {
+	if r.FiredAt != nil {
+		return false
+	}
+
+	switch {
+	case r.RemindAt != nil:
+		return !now.Before(*r.RemindAt)
+	case r.DaysBeforeExpiry != nil && expiresAt != nil:
+		fireAt := expiresAt.Add(-time.Duration(*r.DaysBeforeExpiry) * 24 * time.Hour)
+
+		return !now.Before(fireAt)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,45 @@
+// Package text defines the arbitrary text secret domain type.
+package text
+
+import (
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/acl"
+	"github.com/andymarkow/gophkeeper/internal/domain/contentinfo"
+	"github.com/andymarkow/gophkeeper/internal/domain/metadata"
+)
+
+// Secret is an encrypted blob of text owned by a user, stored in object
+// storage and referenced here by its content location.
+type Secret struct {
+	ID        string
+	UserID    string
+	Name      string
+	Content   contentinfo.ContentInfo
+	Metadata  metadata.Bag
+	ACL       acl.List
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// DeletedAt is set when the secret is trashed (see textsvc.Delete) and
+	// cleared on restore. A trashed secret is hidden from List but its
+	// content is untouched until retentionsvc permanently purges it.
+	DeletedAt *time.Time
+}
+
+// SetMetadata replaces the secret's metadata entirely. patch must not
+// contain system-namespaced keys; see metadata.SystemKeyPrefix.
+func (s *Secret) SetMetadata(patch metadata.Bag) error {
+	return s.Metadata.SetUser(patch)
+}
+
+// MergeMetadata merges patch into the secret's existing metadata, overwriting
+// keys present in both. patch must not contain system-namespaced keys; see
+// metadata.SystemKeyPrefix.
+func (s *Secret) MergeMetadata(patch metadata.Bag) error {
+	return s.Metadata.MergeUser(patch)
+}
+
+// RemoveMetadata deletes the given keys from the secret's metadata.
+func (s *Secret) RemoveMetadata(keys ...string) {
+	s.Metadata.Remove(keys...)
+}
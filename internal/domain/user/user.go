@@ -0,0 +1,54 @@
+// Package user defines the account domain type.
+package user
+
+import "time"
+
+// User is an account holder authenticating against gophkeeper.
+type User struct {
+	ID             string
+	Login          string
+	HashedPassword string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	// LastLoginAt is nil until the user has authenticated at least once.
+	LastLoginAt *time.Time
+	// Email is used to deliver account notifications (e.g. new-device
+	// login alerts). It is optional; notifications are skipped if unset.
+	Email string
+	// NotifyOnNewLogin controls whether a login from an unrecognized
+	// IP/device triggers a notification email. Defaults to true; users
+	// opt out via the profile endpoint.
+	NotifyOnNewLogin bool
+	// Disabled marks an account deprovisioned (e.g. by an identity
+	// provider via SCIM). Disabled accounts fail authentication but keep
+	// their data, distinguishing deprovisioning from deletion.
+	Disabled bool
+	// TokenVersion is embedded in every JWT issued for this account and
+	// checked on every request. Bumping it (on password change or
+	// logout-all) immediately invalidates every token issued before the
+	// bump, without needing a server-side token blocklist.
+	TokenVersion int
+	// SearchIndexEnabled opts the account into server-side search over its
+	// text secrets: a blind-indexed token per word is kept alongside the
+	// encrypted content so search.Search can match without decrypting
+	// every secret. Defaults to false, trading searchability for the
+	// stronger privacy of an unindexed vault.
+	SearchIndexEnabled bool
+	// TenantID labels which organization this account belongs to, when the
+	// instance runs in multi-tenancy mode. Empty in single-tenant
+	// deployments. It is set at account creation and immutable thereafter.
+	// This is an account/token-scoping label only: authsvc.Service rejects
+	// a session token once its tenant claim no longer matches the current
+	// value, but secret storage, object storage and quotas are not
+	// partitioned by it.
+	TenantID string
+	// TrashRetention overrides how long this account's trashed secrets are
+	// kept before the retention purge job deletes them permanently. Zero
+	// means use the instance-wide default.
+	TrashRetention time.Duration
+	// NotifyChannels lists, in notifysvc channel names ("email", "telegram",
+	// "webhook"), where this account receives notifications (login alerts,
+	// expiry warnings, share invitations). Nil or empty defaults to
+	// ["email"].
+	NotifyChannels []string
+}
@@ -0,0 +1,51 @@
+// Package wifi defines the Wi-Fi network secret domain type.
+package wifi
+
+import (
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/acl"
+	"github.com/andymarkow/gophkeeper/internal/domain/metadata"
+)
+
+// Secret is an encrypted Wi-Fi network credential owned by a user.
+type Secret struct {
+	ID     string
+	UserID string
+	Name   string
+	SSID   string
+	// Security is the network's authentication mode, as encoded in a
+	// provisioning QR payload: "WPA" (covers WPA/WPA2/WPA3), "WEP", or
+	// "nopass" for an open network.
+	Security string
+	Password []byte // encrypted; empty when Security is "nopass"
+	// Hidden marks a network that doesn't broadcast its SSID, which changes
+	// how the provisioning payload is built.
+	Hidden bool
+	// KeyID identifies which master/KEK version encrypted Password, so
+	// decryption selects the matching key and rotation jobs can find
+	// records still under a retired key.
+	KeyID     string
+	Metadata  metadata.Bag
+	ACL       acl.List
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// SetMetadata replaces the secret's metadata entirely. patch must not
+// contain system-namespaced keys; see metadata.SystemKeyPrefix.
+func (s *Secret) SetMetadata(patch metadata.Bag) error {
+	return s.Metadata.SetUser(patch)
+}
+
+// MergeMetadata merges patch into the secret's existing metadata, overwriting
+// keys present in both. patch must not contain system-namespaced keys; see
+// metadata.SystemKeyPrefix.
+func (s *Secret) MergeMetadata(patch metadata.Bag) error {
+	return s.Metadata.MergeUser(patch)
+}
+
+// RemoveMetadata deletes the given keys from the secret's metadata.
+func (s *Secret) RemoveMetadata(keys ...string) {
+	s.Metadata.Remove(keys...)
+}
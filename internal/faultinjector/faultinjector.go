@@ -0,0 +1,70 @@
+// Package faultinjector adds configurable latency and error rates to a
+// storage dependency, so retry, outbox and client-sync logic can be
+// exercised deterministically in dev/test builds. It must never be wired in
+// for production configs.
+package faultinjector
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// ErrInjected is returned in place of the wrapped call's result when a
+// fault is injected.
+var ErrInjected = errors.New("faultinjector: injected failure")
+
+// Config controls how often and how badly a decorated dependency misbehaves.
+type Config struct {
+	// LatencyMin and LatencyMax bound a uniformly random delay added
+	// before every call.
+	LatencyMin, LatencyMax time.Duration
+	// ErrorRate is the probability (0..1) that a call fails with
+	// ErrInjected instead of reaching the wrapped dependency.
+	ErrorRate float64
+}
+
+// Enabled reports whether cfg would do anything; a zero Config is a no-op,
+// so callers can wrap unconditionally and gate behavior on config alone.
+func (c Config) Enabled() bool {
+	return c.LatencyMax > 0 || c.ErrorRate > 0
+}
+
+// FromServerConfig builds a Config from the server's fault_injection
+// settings, returning the zero Config (a no-op) unless explicitly enabled.
+func FromServerConfig(enabled bool, latencyMin, latencyMax time.Duration, errorRate float64) Config {
+	if !enabled {
+		return Config{}
+	}
+
+	return Config{LatencyMin: latencyMin, LatencyMax: latencyMax, ErrorRate: errorRate}
+}
+
+// Inject sleeps a random duration within cfg's bounds, then returns
+// ErrInjected with probability cfg.ErrorRate. It returns ctx.Err() if ctx is
+// canceled while sleeping.
+func Inject(ctx context.Context, cfg Config) error {
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	if cfg.LatencyMax > 0 {
+		delay := cfg.LatencyMin
+		if cfg.LatencyMax > cfg.LatencyMin {
+			delay += time.Duration(rand.Int64N(int64(cfg.LatencyMax - cfg.LatencyMin)))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+
+	if cfg.ErrorRate > 0 && rand.Float64() < cfg.ErrorRate {
+		return ErrInjected
+	}
+
+	return nil
+}
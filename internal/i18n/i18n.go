@@ -0,0 +1,83 @@
+// Package i18n translates the stable, machine-readable error codes in
+// internal/api/httperr into a human-readable message for the caller's
+// negotiated language, driven by the request's Accept-Language header.
+// Codes themselves never change: a client that only reads Response.Code
+// is unaffected regardless of locale.
+package i18n
+
+import "strings"
+
+// Lang is a supported locale tag.
+type Lang string
+
+const (
+	// LangEN is the default locale. English messages live as the
+	// fallback message callers already pass to httperr, not in catalog.
+	LangEN Lang = "en"
+	LangRU Lang = "ru"
+)
+
+// catalog maps a stable httperr Response.Code to its translation, per
+// supported non-English language.
+var catalog = map[Lang]map[string]string{
+	LangRU: {
+		"bad_request":                "неверный запрос",
+		"unauthorized":               "требуется аутентификация",
+		"forbidden":                  "доступ запрещён",
+		"not_found":                  "ресурс не найден",
+		"conflict":                   "конфликт данных",
+		"rate_limited":               "превышен лимит запросов",
+		"request_entity_too_large":   "размер запроса превышает допустимый",
+		"unsupported_media_type":     "неподдерживаемый тип содержимого",
+		"object_storage_unavailable": "хранилище объектов временно недоступно",
+		"internal_error":             "внутренняя ошибка сервера",
+		"unprocessable_entity":       "невозможно обработать запрос",
+		"validation_failed":          "ошибка валидации запроса",
+		"method_not_allowed":         "метод не поддерживается для этого ресурса",
+	},
+}
+
+// ParseAcceptLanguage picks the best Lang this package has a catalog for
+// from an Accept-Language header value (e.g. "ru-RU,ru;q=0.9,en;q=0.8"),
+// defaulting to LangEN if the header is empty or names no supported
+// language. It ignores q-weights and takes languages in the order listed,
+// which RFC 9110 already requires a well-behaved client to sort by
+// preference.
+func ParseAcceptLanguage(header string) Lang {
+	for _, part := range strings.Split(header, ",") {
+		tag := strings.TrimSpace(part)
+		if i := strings.IndexByte(tag, ';'); i >= 0 {
+			tag = tag[:i]
+		}
+
+		tag = strings.ToLower(tag)
+		if i := strings.IndexByte(tag, '-'); i >= 0 {
+			tag = tag[:i]
+		}
+
+		switch Lang(tag) {
+		case LangRU:
+			return LangRU
+		case LangEN:
+			return LangEN
+		}
+	}
+
+	return LangEN
+}
+
+// Translate returns code's message in lang, falling back to defaultMessage
+// if lang is LangEN or has no catalog entry for code.
+func Translate(lang Lang, code, defaultMessage string) string {
+	bundle, ok := catalog[lang]
+	if !ok {
+		return defaultMessage
+	}
+
+	msg, ok := bundle[code]
+	if !ok {
+		return defaultMessage
+	}
+
+	return msg
+}
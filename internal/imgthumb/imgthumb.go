@@ -0,0 +1,79 @@
+// Package imgthumb generates small JPEG thumbnails from uploaded image
+// files, so clients can render galleries without downloading and decrypting
+// full-size originals.
+package imgthumb
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // register GIF decoding with image.Decode
+	"image/jpeg"
+	_ "image/png" // register PNG decoding with image.Decode
+)
+
+// MaxDimension is the longest side, in pixels, of a generated thumbnail.
+const MaxDimension = 256
+
+// JPEGQuality is the quality passed to the JPEG encoder for thumbnails.
+const JPEGQuality = 85
+
+// Generate decodes an image from r and returns a JPEG-encoded thumbnail
+// whose longest side is at most MaxDimension, preserving aspect ratio. It
+// returns an error if r does not contain a decodable image.
+func Generate(r []byte) ([]byte, error) {
+	src, _, err := image.Decode(bytes.NewReader(r))
+	if err != nil {
+		return nil, fmt.Errorf("decode image: %w", err)
+	}
+
+	dst := resize(src, MaxDimension)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: JPEGQuality}); err != nil {
+		return nil, fmt.Errorf("encode thumbnail: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// resize scales src down so its longest side is at most maxDim, using
+// nearest-neighbor sampling. Images already within maxDim are returned
+// unchanged.
+func resize(src image.Image, maxDim int) image.Image {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	if w <= maxDim && h <= maxDim {
+		return src
+	}
+
+	var newW, newH int
+	if w >= h {
+		newW = maxDim
+		newH = h * maxDim / w
+	} else {
+		newH = maxDim
+		newW = w * maxDim / h
+	}
+
+	if newW < 1 {
+		newW = 1
+	}
+	if newH < 1 {
+		newH = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, newW, newH))
+
+	for y := 0; y < newH; y++ {
+		srcY := bounds.Min.Y + y*h/newH
+		for x := 0; x < newW; x++ {
+			srcX := bounds.Min.X + x*w/newW
+			dst.Set(x, y, color.RGBAModel.Convert(src.At(srcX, srcY)))
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,50 @@
+// Package lock provides per-key advisory locking so concurrent operations
+// on the same logical resource (e.g. a secret's upload) can't interleave,
+// without needing a distributed lock for the in-process MemStorage-backed
+// deployments this repository currently ships.
+package lock
+
+import "sync"
+
+// KeyedMutex hands out a distinct lock per key, so callers locking
+// different keys never block each other. It is safe for concurrent use.
+// Locks are never removed once created, trading unbounded memory growth
+// (one small mutex per distinct key ever locked) for simplicity; this is
+// acceptable for the process-lifetime key cardinality (secret IDs) this
+// package is used for.
+type KeyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// NewKeyedMutex returns an empty KeyedMutex.
+func NewKeyedMutex() *KeyedMutex {
+	return &KeyedMutex{locks: make(map[string]*sync.Mutex)}
+}
+
+// Lock acquires the lock for key, blocking until it is available.
+func (k *KeyedMutex) Lock(key string) {
+	k.mu.Lock()
+	l, ok := k.locks[key]
+	if !ok {
+		l = &sync.Mutex{}
+		k.locks[key] = l
+	}
+	k.mu.Unlock()
+
+	l.Lock()
+}
+
+// Unlock releases the lock for key. It panics if key is not locked, per the
+// same contract as sync.Mutex.Unlock.
+func (k *KeyedMutex) Unlock(key string) {
+	k.mu.Lock()
+	l, ok := k.locks[key]
+	k.mu.Unlock()
+
+	if !ok {
+		panic("lock: Unlock of unlocked key " + key)
+	}
+
+	l.Unlock()
+}
@@ -0,0 +1,90 @@
+// Package slogger builds the application's *slog.Logger from configuration,
+// including the output destination and rotation policy.
+package slogger
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"log/syslog"
+	"os"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// Output selects where log lines are written.
+type Output string
+
+const (
+	OutputStdout Output = "stdout"
+	OutputStderr Output = "stderr"
+	OutputFile   Output = "file"
+	// OutputSyslog and OutputJournald both deliver to the local system
+	// logger over the syslog protocol; journald listens on the same
+	// /dev/log socket, so both are implemented identically here.
+	OutputSyslog   Output = "syslog"
+	OutputJournald Output = "journald"
+)
+
+// Config controls the logger's destination, rotation and format.
+type Config struct {
+	Level  slog.Level
+	JSON   bool
+	Output Output
+
+	// File-only settings, used when Output == OutputFile.
+	FilePath   string
+	MaxSizeMB  int // max size of a log file before it's rotated
+	MaxBackups int // max number of rotated files to retain
+	MaxAgeDays int // max age in days of a rotated file
+	Compress   bool
+}
+
+// New builds a *slog.Logger per cfg.
+func New(cfg Config) (*slog.Logger, error) {
+	w, err := writer(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &slog.HandlerOptions{Level: cfg.Level}
+
+	var handler slog.Handler
+	if cfg.JSON {
+		handler = slog.NewJSONHandler(w, opts)
+	} else {
+		handler = slog.NewTextHandler(w, opts)
+	}
+
+	return slog.New(handler), nil
+}
+
+func writer(cfg Config) (io.Writer, error) {
+	switch cfg.Output {
+	case OutputStdout, "":
+		return os.Stdout, nil
+	case OutputStderr:
+		return os.Stderr, nil
+	case OutputFile:
+		if cfg.FilePath == "" {
+			return nil, fmt.Errorf("slogger: file output requires FilePath")
+		}
+
+		return &lumberjack.Logger{
+			Filename:   cfg.FilePath,
+			MaxSize:    cfg.MaxSizeMB,
+			MaxBackups: cfg.MaxBackups,
+			MaxAge:     cfg.MaxAgeDays,
+			Compress:   cfg.Compress,
+		}, nil
+	case OutputSyslog, OutputJournald:
+		sw, err := syslog.New(syslog.LOG_INFO|syslog.LOG_DAEMON, "gophkeeper")
+		if err != nil {
+			return nil, fmt.Errorf("slogger: dial syslog: %w", err)
+		}
+
+		return sw, nil
+	default:
+		return nil, fmt.Errorf("slogger: unsupported output %q", cfg.Output)
+	}
+}
@@ -0,0 +1,82 @@
+// Package metrics defines the Prometheus collectors shared across packages,
+// registered against the default registry so a single /metrics handler
+// exposes all of them.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// CryptoOperationsTotal counts encrypt/decrypt/KDF operations by outcome, to
+// spot a sudden rise in failures (e.g. a bad key rollout).
+var CryptoOperationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "gophkeeper",
+	Subsystem: "crypto",
+	Name:      "operations_total",
+	Help:      "Total number of cryptographic operations performed.",
+}, []string{"operation", "algo", "result"})
+
+// CryptoOperationDuration measures how long encrypt/decrypt/KDF operations
+// take, to quantify the CPU cost (e.g. argon2's memory-hard KDF) spent per
+// request.
+var CryptoOperationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "gophkeeper",
+	Subsystem: "crypto",
+	Name:      "operation_duration_seconds",
+	Help:      "Duration of cryptographic operations in seconds.",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"operation", "algo"})
+
+// ObserveCrypto records one operation's outcome and duration. Callers defer
+// this at the top of the instrumented function:
+//
+//	defer metrics.ObserveCrypto("encrypt", string(algo), time.Now(), &err)
+func ObserveCrypto(operation, algo string, start time.Time, err *error) {
+	result := "ok"
+	if err != nil && *err != nil {
+		result = "error"
+	}
+
+	CryptoOperationsTotal.WithLabelValues(operation, algo, result).Inc()
+	CryptoOperationDuration.WithLabelValues(operation, algo).Observe(time.Since(start).Seconds())
+}
+
+// CryptoObjectSizeBytes records the size of each streamed encrypt/decrypt
+// payload, for capacity planning on the object storage and crypto path.
+var CryptoObjectSizeBytes = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "gophkeeper",
+	Subsystem: "crypto",
+	Name:      "object_size_bytes",
+	Help:      "Size of streamed encrypt/decrypt payloads in bytes.",
+	Buckets:   prometheus.ExponentialBuckets(1<<10, 4, 10), // 1 KiB .. 256 MiB
+}, []string{"operation"})
+
+// CryptoThroughputMBps records each streamed encrypt/decrypt operation's
+// throughput, to separate a slow run caused by a large payload from one
+// caused by CPU contention or a slow compression codec.
+var CryptoThroughputMBps = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "gophkeeper",
+	Subsystem: "crypto",
+	Name:      "object_throughput_mbps",
+	Help:      "Throughput of streamed encrypt/decrypt operations in MB/s.",
+	Buckets:   prometheus.ExponentialBuckets(1, 2, 10), // 1 .. 512 MB/s
+}, []string{"operation"})
+
+// ObserveCryptoStreamSize records a successfully streamed operation's
+// payload size and resulting throughput. Callers call this once io.Copy
+// returns a byte count with no error:
+//
+//	n, err := io.Copy(dst, src)
+//	if err == nil {
+//	    metrics.ObserveCryptoStreamSize("encrypt", start, n)
+//	}
+func ObserveCryptoStreamSize(operation string, start time.Time, bytesProcessed int64) {
+	CryptoObjectSizeBytes.WithLabelValues(operation).Observe(float64(bytesProcessed))
+
+	if elapsed := time.Since(start).Seconds(); elapsed > 0 {
+		CryptoThroughputMBps.WithLabelValues(operation).Observe(float64(bytesProcessed) / (1 << 20) / elapsed)
+	}
+}
@@ -0,0 +1,16 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// IntegrityChecksTotal counts object integrity audit checks by outcome, so
+// operators can alert when stored objects start failing checksum
+// verification.
+var IntegrityChecksTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "gophkeeper",
+	Subsystem: "integrity",
+	Name:      "checks_total",
+	Help:      "Total number of object integrity audit checks performed, by outcome.",
+}, []string{"result"})
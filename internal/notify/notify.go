@@ -0,0 +1,12 @@
+// Package notify delivers transactional notifications to users over email,
+// Telegram or a webhook.
+package notify
+
+import "context"
+
+// Notifier sends a plain-text message to a recipient. What to identifies
+// depends on the implementation: an email address for SMTPNotifier, a chat
+// ID for TelegramNotifier.
+type Notifier interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
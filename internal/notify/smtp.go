@@ -0,0 +1,48 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+)
+
+// SMTPConfig configures the outgoing mail relay.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPNotifier sends email over SMTP with PLAIN auth.
+type SMTPNotifier struct {
+	cfg SMTPConfig
+	// dial is overridable in tests; defaults to smtp.SendMail.
+	dial func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewSMTPNotifier returns an SMTPNotifier for cfg.
+func NewSMTPNotifier(cfg SMTPConfig) *SMTPNotifier {
+	return &SMTPNotifier{cfg: cfg, dial: smtp.SendMail}
+}
+
+// Send delivers a plain-text email. SMTP has no first-class context support,
+// so ctx is only honored for cancellation before the call is made.
+func (n *SMTPNotifier) Send(ctx context.Context, to, subject, body string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	addr := fmt.Sprintf("%s:%d", n.cfg.Host, n.cfg.Port)
+	auth := smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.cfg.Host)
+
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s\r\n",
+		n.cfg.From, to, subject, body)
+
+	if err := n.dial(addr, auth, n.cfg.From, []string{to}, []byte(msg)); err != nil {
+		return fmt.Errorf("send mail: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/andymarkow/gophkeeper/internal/telegrambot"
+)
+
+// TelegramNotifier delivers notifications as Telegram direct messages. The
+// recipient (to) is the account's bound chat ID, formatted as a decimal
+// string.
+type TelegramNotifier struct {
+	api *telegrambot.API
+}
+
+// NewTelegramNotifier returns a TelegramNotifier sending through api.
+func NewTelegramNotifier(api *telegrambot.API) *TelegramNotifier {
+	return &TelegramNotifier{api: api}
+}
+
+// Send posts subject and body as a single Telegram message to the chat ID
+// encoded in to.
+func (n *TelegramNotifier) Send(ctx context.Context, to, subject, body string) error {
+	chatID, err := strconv.ParseInt(to, 10, 64)
+	if err != nil {
+		return fmt.Errorf("parse chat id %q: %w", to, err)
+	}
+
+	return n.api.SendMessage(ctx, chatID, subject+"\n\n"+body)
+}
@@ -0,0 +1,80 @@
+package objrepo
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// BlobRefs tracks content-addressable reference counts, keyed per user.
+type BlobRefs interface {
+	Acquire(ctx context.Context, userID, contentHash string) (objectKey string, found bool, err error)
+	CreateOrAcquire(ctx context.Context, userID, contentHash, objectKey string) (winningKey string, err error)
+	Release(ctx context.Context, userID, contentHash string) (deleted bool, err error)
+}
+
+// DedupStorage wraps a Storage with content-addressable deduplication: if a
+// user has already uploaded a blob with the same content hash, the existing
+// object is reused and only its reference count is bumped.
+type DedupStorage struct {
+	Storage
+	refs BlobRefs
+}
+
+// NewDedupStorage wraps storage with deduplication backed by refs.
+func NewDedupStorage(storage Storage, refs BlobRefs) *DedupStorage {
+	return &DedupStorage{Storage: storage, refs: refs}
+}
+
+// PutDedup uploads r under a content-addressed key derived from
+// (userID, contentHash), skipping the upload entirely if that content is
+// already stored for this user. It returns the object key to record against
+// the owning secret.
+//
+// The Acquire-miss -> upload -> CreateOrAcquire sequence below can still
+// race two first uploads of identical content against each other, but
+// CreateOrAcquire resolves that race atomically: whichever caller loses it
+// gets back the winner's object key instead of a unique-violation error,
+// and cleans up the object it uploaded so it doesn't get orphaned.
+func (d *DedupStorage) PutDedup(ctx context.Context, userID, contentHash, newKey string, r io.Reader, size int64, tags Tags) (string, error) {
+	if key, found, err := d.refs.Acquire(ctx, userID, contentHash); err != nil {
+		return "", fmt.Errorf("acquire blob ref: %w", err)
+	} else if found {
+		return key, nil
+	}
+
+	if err := d.Storage.Put(ctx, newKey, r, size, tags); err != nil {
+		return "", fmt.Errorf("put object: %w", err)
+	}
+
+	winningKey, err := d.refs.CreateOrAcquire(ctx, userID, contentHash, newKey)
+	if err != nil {
+		return "", fmt.Errorf("create or acquire blob ref: %w", err)
+	}
+
+	if winningKey != newKey {
+		if err := d.Storage.Delete(ctx, newKey); err != nil {
+			return "", fmt.Errorf("delete orphaned object %q: %w", newKey, err)
+		}
+	}
+
+	return winningKey, nil
+}
+
+// DeleteDedup releases the reference held by (userID, contentHash) and only
+// deletes the underlying object once that was the last reference.
+func (d *DedupStorage) DeleteDedup(ctx context.Context, userID, contentHash, objectKey string) error {
+	deleted, err := d.refs.Release(ctx, userID, contentHash)
+	if err != nil {
+		return fmt.Errorf("release blob ref: %w", err)
+	}
+	if !deleted {
+		return nil
+	}
+
+	if err := d.Storage.Delete(ctx, objectKey); err != nil {
+		return fmt.Errorf("delete object: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+package objrepo_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/andymarkow/gophkeeper/internal/objrepo"
+)
+
+type fakeStorage struct {
+	objrepo.Storage
+	puts    map[string]int
+	deletes map[string]int
+}
+
+func newFakeStorage() *fakeStorage {
+	return &fakeStorage{puts: map[string]int{}, deletes: map[string]int{}}
+}
+
+func (s *fakeStorage) Put(_ context.Context, key string, r io.Reader, _ int64, _ objrepo.Tags) error {
+	s.puts[key]++
+
+	_, err := io.Copy(io.Discard, r)
+
+	return err
+}
+
+func (s *fakeStorage) Delete(_ context.Context, key string) error {
+	s.deletes[key]++
+
+	return nil
+}
+
+type fakeBlobRefs struct {
+	// existing, if non-empty, is the object key Acquire reports as already
+	// present for every lookup.
+	existing string
+	// winner, if non-empty, is the object key CreateOrAcquire reports as
+	// the race winner, overriding whatever key the caller passed in (used
+	// to simulate a concurrent caller winning the insert race).
+	winner string
+
+	acquireCalls         int
+	createOrAcquireCalls int
+	releaseResult        bool
+	releaseErr           error
+}
+
+func (r *fakeBlobRefs) Acquire(context.Context, string, string) (string, bool, error) {
+	r.acquireCalls++
+
+	if r.existing == "" {
+		return "", false, nil
+	}
+
+	return r.existing, true, nil
+}
+
+func (r *fakeBlobRefs) CreateOrAcquire(_ context.Context, _, _, objectKey string) (string, error) {
+	r.createOrAcquireCalls++
+
+	if r.winner != "" {
+		return r.winner, nil
+	}
+
+	return objectKey, nil
+}
+
+func (r *fakeBlobRefs) Release(context.Context, string, string) (bool, error) {
+	return r.releaseResult, r.releaseErr
+}
+
+func TestPutDedup_UploadsOnFirstUse(t *testing.T) {
+	storage := newFakeStorage()
+	refs := &fakeBlobRefs{}
+
+	d := objrepo.NewDedupStorage(storage, refs)
+
+	key, err := d.PutDedup(context.Background(), "user-1", "hash-1", "obj-1", strings.NewReader(""), 0, objrepo.Tags{})
+	if err != nil {
+		t.Fatalf("PutDedup() error = %v", err)
+	}
+
+	if key != "obj-1" {
+		t.Fatalf("PutDedup() key = %q, want %q", key, "obj-1")
+	}
+
+	if storage.puts["obj-1"] != 1 {
+		t.Fatalf("Put called %d times for obj-1, want 1", storage.puts["obj-1"])
+	}
+
+	if refs.createOrAcquireCalls != 1 {
+		t.Fatalf("CreateOrAcquire called %d times, want 1", refs.createOrAcquireCalls)
+	}
+}
+
+func TestPutDedup_SkipsUploadWhenAlreadyAcquired(t *testing.T) {
+	storage := newFakeStorage()
+	refs := &fakeBlobRefs{existing: "obj-existing"}
+
+	d := objrepo.NewDedupStorage(storage, refs)
+
+	key, err := d.PutDedup(context.Background(), "user-1", "hash-1", "obj-new", strings.NewReader(""), 0, objrepo.Tags{})
+	if err != nil {
+		t.Fatalf("PutDedup() error = %v", err)
+	}
+
+	if key != "obj-existing" {
+		t.Fatalf("PutDedup() key = %q, want %q", key, "obj-existing")
+	}
+
+	if len(storage.puts) != 0 {
+		t.Fatalf("Put called for %v, want no uploads", storage.puts)
+	}
+}
+
+func TestPutDedup_LosingConcurrentUploadReusesWinnerAndCleansUpOrphan(t *testing.T) {
+	storage := newFakeStorage()
+	refs := &fakeBlobRefs{winner: "obj-winner"}
+
+	d := objrepo.NewDedupStorage(storage, refs)
+
+	key, err := d.PutDedup(context.Background(), "user-1", "hash-1", "obj-loser", strings.NewReader(""), 0, objrepo.Tags{})
+	if err != nil {
+		t.Fatalf("PutDedup() error = %v", err)
+	}
+
+	if key != "obj-winner" {
+		t.Fatalf("PutDedup() key = %q, want %q", key, "obj-winner")
+	}
+
+	if storage.deletes["obj-loser"] != 1 {
+		t.Fatalf("Delete called %d times for the losing upload obj-loser, want 1 (orphan leak)", storage.deletes["obj-loser"])
+	}
+}
+
+func TestDeleteDedup_DeletesObjectOnlyOnLastRelease(t *testing.T) {
+	storage := newFakeStorage()
+	refs := &fakeBlobRefs{releaseResult: false}
+
+	d := objrepo.NewDedupStorage(storage, refs)
+
+	if err := d.DeleteDedup(context.Background(), "user-1", "hash-1", "obj-1"); err != nil {
+		t.Fatalf("DeleteDedup() error = %v", err)
+	}
+
+	if storage.deletes["obj-1"] != 0 {
+		t.Fatalf("Delete called while refs remain, want no delete")
+	}
+
+	refs.releaseResult = true
+
+	if err := d.DeleteDedup(context.Background(), "user-1", "hash-1", "obj-1"); err != nil {
+		t.Fatalf("DeleteDedup() error = %v", err)
+	}
+
+	if storage.deletes["obj-1"] != 1 {
+		t.Fatalf("Delete called %d times after last release, want 1", storage.deletes["obj-1"])
+	}
+}
+
+func TestDeleteDedup_PropagatesReleaseError(t *testing.T) {
+	storage := newFakeStorage()
+	refs := &fakeBlobRefs{releaseErr: errors.New("boom")}
+
+	d := objrepo.NewDedupStorage(storage, refs)
+
+	if err := d.DeleteDedup(context.Background(), "user-1", "hash-1", "obj-1"); err == nil {
+		t.Fatal("DeleteDedup() error = nil, want error")
+	}
+}
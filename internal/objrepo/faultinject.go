@@ -0,0 +1,79 @@
+package objrepo
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/faultinjector"
+)
+
+// FaultInjecting wraps a Storage with configurable latency and error
+// injection, for exercising retry and sync logic in dev/test. It must never
+// be wired into a production config.
+type FaultInjecting struct {
+	next Storage
+	cfg  faultinjector.Config
+}
+
+// NewFaultInjecting returns a Storage that injects faults per cfg before
+// delegating to next.
+func NewFaultInjecting(next Storage, cfg faultinjector.Config) *FaultInjecting {
+	return &FaultInjecting{next: next, cfg: cfg}
+}
+
+func (f *FaultInjecting) Put(ctx context.Context, key string, r io.Reader, size int64, tags Tags) error {
+	if err := faultinjector.Inject(ctx, f.cfg); err != nil {
+		return err
+	}
+
+	return f.next.Put(ctx, key, r, size, tags)
+}
+
+func (f *FaultInjecting) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := faultinjector.Inject(ctx, f.cfg); err != nil {
+		return nil, err
+	}
+
+	return f.next.Get(ctx, key)
+}
+
+func (f *FaultInjecting) Delete(ctx context.Context, key string) error {
+	if err := faultinjector.Inject(ctx, f.cfg); err != nil {
+		return err
+	}
+
+	return f.next.Delete(ctx, key)
+}
+
+func (f *FaultInjecting) Copy(ctx context.Context, srcKey, dstKey string) error {
+	if err := faultinjector.Inject(ctx, f.cfg); err != nil {
+		return err
+	}
+
+	return f.next.Copy(ctx, srcKey, dstKey)
+}
+
+func (f *FaultInjecting) Compose(ctx context.Context, srcKeys []string, dstKey string) error {
+	if err := faultinjector.Inject(ctx, f.cfg); err != nil {
+		return err
+	}
+
+	return f.next.Compose(ctx, srcKeys, dstKey)
+}
+
+func (f *FaultInjecting) PresignedPutURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	if err := faultinjector.Inject(ctx, f.cfg); err != nil {
+		return "", err
+	}
+
+	return f.next.PresignedPutURL(ctx, key, expiry)
+}
+
+func (f *FaultInjecting) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	if err := faultinjector.Inject(ctx, f.cfg); err != nil {
+		return nil, err
+	}
+
+	return f.next.List(ctx, prefix)
+}
@@ -0,0 +1,290 @@
+package objrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+)
+
+// classify wraps err with ErrUnavailable when it indicates the backend
+// couldn't be reached at all (connection refused, DNS failure, dial/read
+// timeout), as opposed to an error MinIO itself returned for a request it
+// received. Leaves nil and backend-returned errors untouched.
+func classify(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var urlErr *url.Error
+
+	var netErr net.Error
+
+	if errors.As(err, &urlErr) || errors.As(err, &netErr) {
+		return fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+
+	return err
+}
+
+// SSEMode selects the server-side encryption scheme applied to objects
+// independently of gophkeeper's own application-level encryption.
+type SSEMode string
+
+const (
+	SSENone SSEMode = ""
+	SSEC    SSEMode = "sse-c"
+	SSEKMS  SSEMode = "sse-kms"
+)
+
+// SSEConfig configures server-side encryption for a MinioClient.
+type SSEConfig struct {
+	Mode SSEMode
+	// CustomerKey is the 32-byte SSE-C customer key, required when Mode
+	// is SSEC.
+	CustomerKey []byte
+	// KMSKeyID is the KMS master key ID, required when Mode is SSEKMS.
+	KMSKeyID string
+}
+
+func (c SSEConfig) serverSide() (encrypt.ServerSide, error) {
+	switch c.Mode {
+	case SSENone:
+		return nil, nil
+	case SSEC:
+		sse, err := encrypt.NewSSEC(c.CustomerKey)
+		if err != nil {
+			return nil, fmt.Errorf("build sse-c key: %w", err)
+		}
+
+		return sse, nil
+	case SSEKMS:
+		sse, err := encrypt.NewSSEKMS(c.KMSKeyID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("build sse-kms context: %w", err)
+		}
+
+		return sse, nil
+	default:
+		return nil, fmt.Errorf("unsupported sse mode: %q", c.Mode)
+	}
+}
+
+// MinioClient is a Storage implementation backed by a MinIO/S3 bucket.
+type MinioClient struct {
+	client *minio.Client
+	bucket string
+	sse    SSEConfig
+}
+
+// NewMinioClient returns a MinioClient that stores objects in bucket,
+// applying the given server-side encryption config to every object.
+func NewMinioClient(client *minio.Client, bucket string, sse SSEConfig) *MinioClient {
+	return &MinioClient{client: client, bucket: bucket, sse: sse}
+}
+
+func (c *MinioClient) Put(ctx context.Context, key string, r io.Reader, size int64, tags Tags) error {
+	sse, err := c.sse.serverSide()
+	if err != nil {
+		return err
+	}
+
+	_, err = c.client.PutObject(ctx, c.bucket, key, r, size, minio.PutObjectOptions{
+		ServerSideEncryption: sse,
+		UserTags:             tags.toMap(),
+	})
+	if err := classify(err); err != nil {
+		return fmt.Errorf("put object %q: %w", key, err)
+	}
+
+	return nil
+}
+
+func (c *MinioClient) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	sse, err := c.sse.serverSide()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := minio.GetObjectOptions{}
+	if sse != nil {
+		opts.ServerSideEncryption = sse
+	}
+
+	obj, err := c.client.GetObject(ctx, c.bucket, key, opts)
+	if err := classify(err); err != nil {
+		return nil, fmt.Errorf("get object %q: %w", key, err)
+	}
+
+	return obj, nil
+}
+
+func (c *MinioClient) Delete(ctx context.Context, key string) error {
+	if err := classify(c.client.RemoveObject(ctx, c.bucket, key, minio.RemoveObjectOptions{})); err != nil {
+		return fmt.Errorf("delete object %q: %w", key, err)
+	}
+
+	return nil
+}
+
+// Copy server-side copies the object at srcKey to dstKey, without
+// downloading and re-uploading the bytes through the app.
+func (c *MinioClient) Copy(ctx context.Context, srcKey, dstKey string) error {
+	sse, err := c.sse.serverSide()
+	if err != nil {
+		return err
+	}
+
+	dst := minio.CopyDestOptions{
+		Bucket:     c.bucket,
+		Object:     dstKey,
+		Encryption: sse,
+	}
+
+	src := minio.CopySrcOptions{
+		Bucket: c.bucket,
+		Object: srcKey,
+	}
+
+	_, err = c.client.CopyObject(ctx, dst, src)
+	if err := classify(err); err != nil {
+		return fmt.Errorf("copy object %q to %q: %w", srcKey, dstKey, err)
+	}
+
+	return nil
+}
+
+// Compose server-side concatenates the objects at srcKeys, in order, into
+// dstKey, without downloading and re-uploading the bytes through the app.
+func (c *MinioClient) Compose(ctx context.Context, srcKeys []string, dstKey string) error {
+	sse, err := c.sse.serverSide()
+	if err != nil {
+		return err
+	}
+
+	dst := minio.CopyDestOptions{
+		Bucket:     c.bucket,
+		Object:     dstKey,
+		Encryption: sse,
+	}
+
+	srcs := make([]minio.CopySrcOptions, len(srcKeys))
+	for i, key := range srcKeys {
+		srcs[i] = minio.CopySrcOptions{Bucket: c.bucket, Object: key}
+	}
+
+	_, err = c.client.ComposeObject(ctx, dst, srcs...)
+	if err := classify(err); err != nil {
+		return fmt.Errorf("compose objects into %q: %w", dstKey, err)
+	}
+
+	return nil
+}
+
+// PresignedPutURL returns a presigned URL valid for expiry that a client can
+// PUT an object to directly, bypassing the app server's data path.
+func (c *MinioClient) PresignedPutURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	u, err := c.client.PresignedPutObject(ctx, c.bucket, key, expiry)
+	if err := classify(err); err != nil {
+		return "", fmt.Errorf("presign put object %q: %w", key, err)
+	}
+
+	return u.String(), nil
+}
+
+// List returns metadata for every object under prefix in the bucket.
+func (c *MinioClient) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+
+	for obj := range c.client.ListObjects(ctx, c.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if err := classify(obj.Err); err != nil {
+			return nil, fmt.Errorf("list objects under %q: %w", prefix, err)
+		}
+
+		objects = append(objects, ObjectInfo{Key: obj.Key, Size: obj.Size})
+	}
+
+	return objects, nil
+}
+
+// BucketPolicy configures lifecycle behavior applied to the bucket at
+// startup, on top of plain creation.
+type BucketPolicy struct {
+	// Versioning enables object versioning on the bucket.
+	Versioning bool
+	// AbortIncompleteMultipartUploadDays aborts stale multipart uploads
+	// after this many days. Zero disables the rule.
+	AbortIncompleteMultipartUploadDays int
+	// TrashExpireDays expires objects under the "trash/" prefix after
+	// this many days. Zero disables the rule.
+	TrashExpireDays int
+}
+
+// InitBucket creates the bucket if it does not already exist and applies
+// policy's versioning/lifecycle configuration, so operators don't have to
+// manage bucket lifecycle out-of-band.
+func (c *MinioClient) InitBucket(ctx context.Context, policy BucketPolicy) error {
+	exists, err := c.client.BucketExists(ctx, c.bucket)
+	if err := classify(err); err != nil {
+		return fmt.Errorf("check bucket exists: %w", err)
+	}
+	if !exists {
+		if err := classify(c.client.MakeBucket(ctx, c.bucket, minio.MakeBucketOptions{})); err != nil {
+			return fmt.Errorf("make bucket: %w", err)
+		}
+	}
+
+	if policy.Versioning {
+		if err := classify(c.client.EnableVersioning(ctx, c.bucket)); err != nil {
+			return fmt.Errorf("enable versioning: %w", err)
+		}
+	}
+
+	if err := c.applyLifecycle(ctx, policy); err != nil {
+		return fmt.Errorf("apply lifecycle: %w", err)
+	}
+
+	return nil
+}
+
+func (c *MinioClient) applyLifecycle(ctx context.Context, policy BucketPolicy) error {
+	if policy.AbortIncompleteMultipartUploadDays == 0 && policy.TrashExpireDays == 0 {
+		return nil
+	}
+
+	cfg := lifecycle.NewConfiguration()
+
+	if policy.AbortIncompleteMultipartUploadDays > 0 {
+		cfg.Rules = append(cfg.Rules, lifecycle.Rule{
+			ID:     "abort-incomplete-multipart-uploads",
+			Status: "Enabled",
+			AbortIncompleteMultipartUpload: lifecycle.AbortIncompleteMultipartUpload{
+				DaysAfterInitiation: lifecycle.ExpirationDays(policy.AbortIncompleteMultipartUploadDays),
+			},
+		})
+	}
+
+	if policy.TrashExpireDays > 0 {
+		cfg.Rules = append(cfg.Rules, lifecycle.Rule{
+			ID:         "expire-trash",
+			Status:     "Enabled",
+			RuleFilter: lifecycle.Filter{Prefix: "trash/"},
+			Expiration: lifecycle.Expiration{
+				Days: lifecycle.ExpirationDays(policy.TrashExpireDays),
+			},
+		})
+	}
+
+	if err := classify(c.client.SetBucketLifecycle(ctx, c.bucket, cfg)); err != nil {
+		return fmt.Errorf("set bucket lifecycle: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+// Package objrepo abstracts the object storage backend used to hold
+// encrypted file/text payloads, independent of any particular provider.
+package objrepo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrUnavailable is returned, wrapped, by a Storage implementation when the
+// backend could not be reached at all (connection refused, DNS failure,
+// timeout), as opposed to an error the backend itself returned. Callers use
+// this to fail a request fast with a distinct "unavailable" response
+// instead of treating every object storage error alike.
+var ErrUnavailable = errors.New("object storage unavailable")
+
+// Tags identifies the secret an object belongs to, attached as object-store
+// tags so bucket-level tooling and the orphan GC can reason about objects
+// without parsing key paths.
+type Tags struct {
+	UserID     string
+	SecretID   string
+	SecretType string // "file" or "text"
+}
+
+func (t Tags) toMap() map[string]string {
+	return map[string]string{
+		"user_id":     t.UserID,
+		"secret_id":   t.SecretID,
+		"secret_type": t.SecretType,
+	}
+}
+
+// Storage is the object storage contract used by the secret services.
+type Storage interface {
+	// Put uploads size bytes from r under key, overwriting any existing
+	// object at that key, tagged with the owning secret's identifiers. A
+	// size of -1 streams an object of unknown length.
+	Put(ctx context.Context, key string, r io.Reader, size int64, tags Tags) error
+	// Get returns a reader for the object at key. Callers must close it.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object at key. It is not an error if key does
+	// not exist.
+	Delete(ctx context.Context, key string) error
+	// Copy server-side copies the object at srcKey to dstKey without
+	// proxying bytes through the app, e.g. for duplicate-secret and
+	// key-rotation workflows.
+	Copy(ctx context.Context, srcKey, dstKey string) error
+	// Compose server-side concatenates the objects at srcKeys, in order,
+	// into dstKey, e.g. to assemble a chunked upload's parts.
+	Compose(ctx context.Context, srcKeys []string, dstKey string) error
+	// PresignedPutURL returns a URL the caller may PUT an object to
+	// directly at key, valid for expiry, so large uploads can bypass the
+	// app server's data path.
+	PresignedPutURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+	// List returns metadata for every object whose key starts with
+	// prefix (all objects, if prefix is empty), for operator tooling
+	// (fsck, orphan sweeps) that needs to enumerate the whole bucket.
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+}
+
+// ObjectInfo is the metadata List returns for one stored object.
+type ObjectInfo struct {
+	Key  string
+	Size int64
+}
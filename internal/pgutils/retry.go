@@ -0,0 +1,106 @@
+// Package pgutils provides small helpers shared by Postgres repository
+// implementations.
+package pgutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// RetryPolicy configures WithRetry's attempt count, backoff and which
+// errors are considered worth retrying.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values < 1 are treated as 1 (no retry).
+	MaxAttempts int
+	// BaseDelay is the backoff before the second attempt; each
+	// subsequent attempt doubles it.
+	BaseDelay time.Duration
+	// MaxDelay caps the computed backoff.
+	MaxDelay time.Duration
+	// IsRetryable decides whether err is worth retrying. Defaults to
+	// IsTransient when nil.
+	IsRetryable func(error) bool
+}
+
+// DefaultRetryPolicy is a conservative policy suitable for most repository
+// calls: 3 attempts, 100ms base backoff doubling up to 2s.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    2 * time.Second,
+		IsRetryable: IsTransient,
+	}
+}
+
+// transientPgCodes are Postgres error codes worth retrying: connection
+// failures and serialization/deadlock conflicts under concurrent load.
+var transientPgCodes = map[string]bool{
+	"08000": true, // connection_exception
+	"08003": true, // connection_does_not_exist
+	"08006": true, // connection_failure
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+}
+
+// IsTransient reports whether err is a Postgres error worth retrying.
+func IsTransient(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return transientPgCodes[pgErr.Code]
+	}
+
+	return false
+}
+
+// WithRetry runs fn, retrying per policy while ctx is not done and fn's
+// error is retryable. It returns the last error on exhaustion, or
+// ctx.Err() if the context is cancelled while waiting to retry.
+func WithRetry(ctx context.Context, policy RetryPolicy, fn func(ctx context.Context) error) error {
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	isRetryable := policy.IsRetryable
+	if isRetryable == nil {
+		isRetryable = IsTransient
+	}
+
+	delay := policy.BaseDelay
+
+	var lastErr error
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		lastErr = fn(ctx)
+		if lastErr == nil {
+			return nil
+		}
+
+		if attempt == maxAttempts || !isRetryable(lastErr) {
+			return lastErr
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+			delay = policy.MaxDelay
+		}
+	}
+
+	return fmt.Errorf("with retry: %w", lastErr)
+}
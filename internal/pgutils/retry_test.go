@@ -0,0 +1,69 @@
+package pgutils_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/pgutils"
+)
+
+func TestWithRetry_SucceedsAfterTransientFailures(t *testing.T) {
+	var calls int
+
+	err := pgutils.WithRetry(context.Background(), pgutils.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		IsRetryable: func(error) bool { return true },
+	}, func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("transient")
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithRetry() error = %v, want nil", err)
+	}
+	if calls != 3 {
+		t.Fatalf("calls = %d, want 3", calls)
+	}
+}
+
+func TestWithRetry_StopsOnNonRetryableError(t *testing.T) {
+	var calls int
+
+	wantErr := errors.New("permanent")
+
+	err := pgutils.WithRetry(context.Background(), pgutils.RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   time.Millisecond,
+		IsRetryable: func(error) bool { return false },
+	}, func(context.Context) error {
+		calls++
+
+		return wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("WithRetry() error = %v, want %v", err, wantErr)
+	}
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1", calls)
+	}
+}
+
+func TestWithRetry_StopsOnContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := pgutils.WithRetry(ctx, pgutils.DefaultRetryPolicy(), func(context.Context) error {
+		t.Fatal("fn should not be called with a cancelled context")
+
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("WithRetry() error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,57 @@
+// Package ratelimit implements per-key rate limiting. FixedWindow limits
+// against process-local memory; RedisLimiter shares counters across
+// replicas via Redis, falling back to a local FixedWindow when Redis is
+// unreachable so a limiter outage fails open to degraded-but-working
+// rather than down.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter reports whether a call identified by key is allowed right now.
+type Limiter interface {
+	Allow(ctx context.Context, key string) (bool, error)
+}
+
+// FixedWindow is a process-local fixed-window rate limiter: at most Limit
+// calls per key within each Window-sized bucket. It resets abruptly at each
+// window boundary rather than smoothing traffic, trading precision for a
+// single counter increment per call.
+type FixedWindow struct {
+	mu      sync.Mutex
+	limit   int
+	window  time.Duration
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	count     int
+	expiresAt time.Time
+}
+
+// NewFixedWindow returns a FixedWindow allowing limit calls per key per
+// window.
+func NewFixedWindow(limit int, window time.Duration) *FixedWindow {
+	return &FixedWindow{limit: limit, window: window, buckets: make(map[string]*bucket)}
+}
+
+// Allow never returns an error; it exists to satisfy Limiter.
+func (f *FixedWindow) Allow(_ context.Context, key string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+
+	b, ok := f.buckets[key]
+	if !ok || now.After(b.expiresAt) {
+		b = &bucket{count: 0, expiresAt: now.Add(f.window)}
+		f.buckets[key] = b
+	}
+
+	b.count++
+
+	return b.count <= f.limit, nil
+}
@@ -0,0 +1,153 @@
+package ratelimit
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// errRedisUnavailable wraps any error talking to Redis, so RedisLimiter can
+// recognize it and fall back to the local limiter instead of failing the
+// request.
+var errRedisUnavailable = errors.New("redis unavailable")
+
+// RedisLimiter is a fixed-window limiter sharing its counters across
+// replicas via Redis INCR/EXPIRE, for deployments running more than one
+// API instance behind a load balancer. There is no Redis client in this
+// module's dependencies (go.mod declares none), so RedisLimiter speaks just
+// enough of the RESP protocol over a plain TCP connection to issue those
+// two commands.
+type RedisLimiter struct {
+	addr     string
+	limit    int
+	window   time.Duration
+	fallback *FixedWindow
+
+	mu   sync.Mutex
+	conn net.Conn
+	rd   *bufio.Reader
+}
+
+// NewRedisLimiter returns a RedisLimiter connecting to addr (host:port),
+// allowing limit calls per key per window. fallback serves Allow whenever
+// Redis can't be reached, so a Redis outage degrades to per-instance limits
+// instead of taking the limiter down entirely.
+func NewRedisLimiter(addr string, limit int, window time.Duration, fallback *FixedWindow) *RedisLimiter {
+	return &RedisLimiter{addr: addr, limit: limit, window: window, fallback: fallback}
+}
+
+func (r *RedisLimiter) Allow(ctx context.Context, key string) (bool, error) {
+	count, err := r.incrWithExpire(ctx, key)
+	if err != nil {
+		return r.fallback.Allow(ctx, key)
+	}
+
+	return count <= int64(r.limit), nil
+}
+
+// incrWithExpire increments key and, the first time it's seen in this
+// window, sets it to expire after r.window, so the counter resets without a
+// separate cleanup process.
+func (r *RedisLimiter) incrWithExpire(ctx context.Context, key string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.conn == nil {
+		if err := r.dialLocked(ctx); err != nil {
+			return 0, err
+		}
+	}
+
+	count, err := r.doIntCommand("INCR", key)
+	if err != nil {
+		r.closeLocked()
+
+		return 0, err
+	}
+
+	if count == 1 {
+		if _, err := r.doIntCommand("EXPIRE", key, strconv.Itoa(int(r.window.Seconds()))); err != nil {
+			r.closeLocked()
+
+			return 0, err
+		}
+	}
+
+	return count, nil
+}
+
+func (r *RedisLimiter) dialLocked(ctx context.Context) error {
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", r.addr)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errRedisUnavailable, err)
+	}
+
+	r.conn = conn
+	r.rd = bufio.NewReader(conn)
+
+	return nil
+}
+
+func (r *RedisLimiter) closeLocked() {
+	if r.conn != nil {
+		r.conn.Close()
+	}
+
+	r.conn = nil
+	r.rd = nil
+}
+
+// doIntCommand sends a RESP-encoded command and reads back an integer
+// reply, the only reply shape INCR and EXPIRE return.
+func (r *RedisLimiter) doIntCommand(args ...string) (int64, error) {
+	if err := r.conn.SetDeadline(time.Now().Add(2 * time.Second)); err != nil {
+		return 0, fmt.Errorf("%w: %w", errRedisUnavailable, err)
+	}
+
+	if _, err := r.conn.Write(encodeCommand(args)); err != nil {
+		return 0, fmt.Errorf("%w: %w", errRedisUnavailable, err)
+	}
+
+	line, err := r.rd.ReadString('\n')
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", errRedisUnavailable, err)
+	}
+
+	line = strings.TrimRight(line, "\r\n")
+	if len(line) == 0 {
+		return 0, fmt.Errorf("%w: empty reply", errRedisUnavailable)
+	}
+
+	switch line[0] {
+	case ':':
+		n, err := strconv.ParseInt(line[1:], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: parse integer reply: %w", errRedisUnavailable, err)
+		}
+
+		return n, nil
+	case '-':
+		return 0, fmt.Errorf("%w: redis error: %s", errRedisUnavailable, line[1:])
+	default:
+		return 0, fmt.Errorf("%w: unexpected reply %q", errRedisUnavailable, line)
+	}
+}
+
+// encodeCommand renders args as a RESP array of bulk strings.
+func encodeCommand(args []string) []byte {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+
+	for _, a := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(a), a)
+	}
+
+	return []byte(b.String())
+}
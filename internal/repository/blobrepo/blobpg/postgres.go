@@ -0,0 +1,88 @@
+// Package blobpg is the Postgres-backed implementation of blobrepo.Storage.
+package blobpg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Storage is a Postgres-backed blobrepo.Storage implementation.
+type Storage struct {
+	pool *pgxpool.Pool
+}
+
+// NewStorage returns a Storage backed by the given connection pool.
+func NewStorage(pool *pgxpool.Pool) *Storage {
+	return &Storage{pool: pool}
+}
+
+func (s *Storage) Acquire(ctx context.Context, userID, contentHash string) (string, bool, error) {
+	const query = `
+		UPDATE blob_refs
+		SET ref_count = ref_count + 1
+		WHERE user_id = $1 AND content_hash = $2
+		RETURNING object_key`
+
+	var objectKey string
+
+	err := s.pool.QueryRow(ctx, query, userID, contentHash).Scan(&objectKey)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("acquire blob ref: %w", err)
+	}
+
+	return objectKey, true, nil
+}
+
+func (s *Storage) CreateOrAcquire(ctx context.Context, userID, contentHash, objectKey string) (string, error) {
+	const query = `
+		INSERT INTO blob_refs (user_id, content_hash, object_key, ref_count)
+		VALUES ($1, $2, $3, 1)
+		ON CONFLICT (user_id, content_hash) DO UPDATE
+		SET ref_count = blob_refs.ref_count + 1
+		RETURNING object_key`
+
+	var winningKey string
+
+	if err := s.pool.QueryRow(ctx, query, userID, contentHash, objectKey).Scan(&winningKey); err != nil {
+		return "", fmt.Errorf("create or acquire blob ref: %w", err)
+	}
+
+	return winningKey, nil
+}
+
+func (s *Storage) Release(ctx context.Context, userID, contentHash string) (bool, error) {
+	const updateQuery = `
+		UPDATE blob_refs
+		SET ref_count = ref_count - 1
+		WHERE user_id = $1 AND content_hash = $2
+		RETURNING ref_count`
+
+	var refCount int
+
+	err := s.pool.QueryRow(ctx, updateQuery, userID, contentHash).Scan(&refCount)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("release blob ref: %w", err)
+	}
+
+	if refCount > 0 {
+		return false, nil
+	}
+
+	const deleteQuery = `DELETE FROM blob_refs WHERE user_id = $1 AND content_hash = $2`
+
+	if _, err := s.pool.Exec(ctx, deleteQuery, userID, contentHash); err != nil {
+		return false, fmt.Errorf("delete exhausted blob ref: %w", err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,36 @@
+// Package blobrepo tracks reference counts for content-addressable objects
+// so that uploading identical content twice does not duplicate storage, and
+// a blob is only deleted from object storage once its last reference goes.
+package blobrepo
+
+import "context"
+
+// Ref is a content-addressable object and how many secrets point at it.
+type Ref struct {
+	UserID      string
+	ContentHash string // hex-encoded SHA-256 of the plaintext
+	ObjectKey   string
+	RefCount    int
+}
+
+// Storage tracks blob references per user. Content-addressing is scoped to
+// the user, not global, so one user's dedup bookkeeping can't leak whether
+// another user holds identical content.
+type Storage interface {
+	// Acquire finds an existing object for (userID, contentHash) and
+	// increments its ref count, or reports found=false if none exists
+	// yet, in which case the caller uploads the object and calls
+	// CreateOrAcquire.
+	Acquire(ctx context.Context, userID, contentHash string) (objectKey string, found bool, err error)
+	// CreateOrAcquire atomically registers objectKey as the ref for
+	// (userID, contentHash) with count 1, or — if a concurrent caller
+	// already registered one first — increments that ref's count instead
+	// and returns its object key. The caller must compare the returned
+	// key against objectKey: a mismatch means it lost the race and should
+	// delete the object it just uploaded rather than leak it.
+	CreateOrAcquire(ctx context.Context, userID, contentHash, objectKey string) (winningKey string, err error)
+	// Release decrements the ref count for (userID, contentHash) and
+	// reports whether it reached zero, in which case the caller should
+	// delete the underlying object.
+	Release(ctx context.Context, userID, contentHash string) (deleted bool, err error)
+}
@@ -0,0 +1,67 @@
+// Package confirmationrepo defines the storage interface for one-time
+// confirmation tickets and an in-memory reference implementation.
+package confirmationrepo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/confirmation"
+)
+
+// ErrInvalidTicket is returned by Consume when id doesn't exist, doesn't
+// match the given action/target, has expired, or was already consumed. The
+// caller never learns which, so a replay attempt can't be used to probe
+// for a ticket's real action or target.
+var ErrInvalidTicket = errors.New("invalid or already-used confirmation ticket")
+
+// Storage is the persistence contract for one-time confirmation tickets.
+type Storage interface {
+	Create(ctx context.Context, ticket *confirmation.Ticket) error
+	// Consume atomically validates and marks ticket id consumed for
+	// action/target at now, returning ErrInvalidTicket otherwise. Doing
+	// validation and marking in one call closes the race a separate
+	// Get-then-Update pair would leave between two concurrent replays of
+	// the same ticket.
+	Consume(ctx context.Context, id, action, target string, now time.Time) error
+}
+
+// MemStorage is an in-memory Storage implementation safe for concurrent use.
+type MemStorage struct {
+	mu      sync.Mutex
+	tickets map[string]*confirmation.Ticket
+}
+
+// NewMemStorage returns an empty in-memory confirmation ticket storage.
+func NewMemStorage() *MemStorage {
+	return &MemStorage{tickets: make(map[string]*confirmation.Ticket)}
+}
+
+func (s *MemStorage) Create(_ context.Context, ticket *confirmation.Ticket) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tickets[ticket.ID] = ticket
+
+	return nil
+}
+
+func (s *MemStorage) Consume(_ context.Context, id, action, target string, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ticket, ok := s.tickets[id]
+	if !ok {
+		return ErrInvalidTicket
+	}
+
+	if ticket.Action != action || ticket.Target != target || ticket.ConsumedAt != nil || !now.Before(ticket.ExpiresAt) {
+		return ErrInvalidTicket
+	}
+
+	ticket.ConsumedAt = &now
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package credrepo
+
+import (
+	"context"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/credential"
+	"github.com/andymarkow/gophkeeper/internal/faultinjector"
+)
+
+// FaultInjecting wraps a Storage with configurable latency and error
+// injection, for exercising retry logic in dev/test. It must never be wired
+// into a production config.
+type FaultInjecting struct {
+	next Storage
+	cfg  faultinjector.Config
+}
+
+// NewFaultInjecting returns a Storage that injects faults per cfg before
+// delegating to next.
+func NewFaultInjecting(next Storage, cfg faultinjector.Config) *FaultInjecting {
+	return &FaultInjecting{next: next, cfg: cfg}
+}
+
+func (f *FaultInjecting) Create(ctx context.Context, s *credential.Secret) error {
+	if err := faultinjector.Inject(ctx, f.cfg); err != nil {
+		return err
+	}
+
+	return f.next.Create(ctx, s)
+}
+
+func (f *FaultInjecting) Get(ctx context.Context, id string) (*credential.Secret, error) {
+	if err := faultinjector.Inject(ctx, f.cfg); err != nil {
+		return nil, err
+	}
+
+	return f.next.Get(ctx, id)
+}
+
+func (f *FaultInjecting) Update(ctx context.Context, s *credential.Secret) error {
+	if err := faultinjector.Inject(ctx, f.cfg); err != nil {
+		return err
+	}
+
+	return f.next.Update(ctx, s)
+}
+
+func (f *FaultInjecting) Delete(ctx context.Context, id string) error {
+	if err := faultinjector.Inject(ctx, f.cfg); err != nil {
+		return err
+	}
+
+	return f.next.Delete(ctx, id)
+}
+
+func (f *FaultInjecting) List(ctx context.Context, userID string) ([]*credential.Secret, error) {
+	if err := faultinjector.Inject(ctx, f.cfg); err != nil {
+		return nil, err
+	}
+
+	return f.next.List(ctx, userID)
+}
+
+func (f *FaultInjecting) GetSecrets(ctx context.Context, userID string, names []string) ([]*credential.Secret, error) {
+	if err := faultinjector.Inject(ctx, f.cfg); err != nil {
+		return nil, err
+	}
+
+	return f.next.GetSecrets(ctx, userID, names)
+}
+
+func (f *FaultInjecting) CreateMany(ctx context.Context, secrets []*credential.Secret) error {
+	if err := faultinjector.Inject(ctx, f.cfg); err != nil {
+		return err
+	}
+
+	return f.next.CreateMany(ctx, secrets)
+}
+
+func (f *FaultInjecting) GetByLoginIndex(ctx context.Context, userID, index string) (*credential.Secret, error) {
+	if err := faultinjector.Inject(ctx, f.cfg); err != nil {
+		return nil, err
+	}
+
+	return f.next.GetByLoginIndex(ctx, userID, index)
+}
@@ -0,0 +1,160 @@
+// Package credrepo defines the storage interface for credential secrets
+// and an in-memory reference implementation.
+package credrepo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/credential"
+)
+
+// ErrNotFound is returned when a secret lookup matches no record.
+var ErrNotFound = errors.New("credential secret not found")
+
+// Storage is the persistence contract for credential secrets.
+type Storage interface {
+	Create(ctx context.Context, s *credential.Secret) error
+	Get(ctx context.Context, id string) (*credential.Secret, error)
+	Update(ctx context.Context, s *credential.Secret) error
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context, userID string) ([]*credential.Secret, error)
+	// GetSecrets returns userID's secrets matching any of names, in a
+	// single lookup instead of N sequential Gets. Used by batch delete,
+	// archive download and export endpoints.
+	GetSecrets(ctx context.Context, userID string, names []string) ([]*credential.Secret, error)
+	// CreateMany inserts secrets as a single batch instead of one Create
+	// call per secret, for the bulk import endpoint. A failure partway
+	// through still leaves every secret before it inserted; callers that
+	// need all-or-nothing semantics must check the returned error and roll
+	// back themselves.
+	CreateMany(ctx context.Context, secrets []*credential.Secret) error
+	// GetByLoginIndex returns userID's secret whose LoginIndex equals index,
+	// or ErrNotFound if none matches, so a lookup by login doesn't need to
+	// decrypt and compare every one of userID's secrets.
+	GetByLoginIndex(ctx context.Context, userID, index string) (*credential.Secret, error)
+}
+
+// MemStorage is an in-memory Storage implementation safe for concurrent use.
+type MemStorage struct {
+	mu      sync.RWMutex
+	secrets map[string]*credential.Secret
+}
+
+// NewMemStorage returns an empty in-memory credential storage.
+func NewMemStorage() *MemStorage {
+	return &MemStorage{secrets: make(map[string]*credential.Secret)}
+}
+
+func (s *MemStorage) Create(_ context.Context, sec *credential.Secret) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	sec.CreatedAt = now
+	sec.UpdatedAt = now
+	s.secrets[sec.ID] = sec
+
+	return nil
+}
+
+func (s *MemStorage) Get(_ context.Context, id string) (*credential.Secret, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sec, ok := s.secrets[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return sec, nil
+}
+
+func (s *MemStorage) Update(_ context.Context, sec *credential.Secret) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.secrets[sec.ID]; !ok {
+		return ErrNotFound
+	}
+
+	sec.UpdatedAt = time.Now()
+	s.secrets[sec.ID] = sec
+
+	return nil
+}
+
+func (s *MemStorage) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.secrets[id]; !ok {
+		return ErrNotFound
+	}
+
+	delete(s.secrets, id)
+
+	return nil
+}
+
+func (s *MemStorage) List(_ context.Context, userID string) ([]*credential.Secret, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]*credential.Secret, 0)
+	for _, sec := range s.secrets {
+		if sec.UserID == userID {
+			out = append(out, sec)
+		}
+	}
+
+	return out, nil
+}
+
+func (s *MemStorage) CreateMany(_ context.Context, secrets []*credential.Secret) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, sec := range secrets {
+		sec.CreatedAt = now
+		sec.UpdatedAt = now
+		s.secrets[sec.ID] = sec
+	}
+
+	return nil
+}
+
+func (s *MemStorage) GetByLoginIndex(_ context.Context, userID, index string) (*credential.Secret, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, sec := range s.secrets {
+		if sec.UserID == userID && sec.LoginIndex != "" && sec.LoginIndex == index {
+			return sec, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+func (s *MemStorage) GetSecrets(_ context.Context, userID string, names []string) ([]*credential.Secret, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	out := make([]*credential.Secret, 0, len(names))
+	for _, sec := range s.secrets {
+		if sec.UserID == userID && wanted[sec.Name] {
+			out = append(out, sec)
+		}
+	}
+
+	return out, nil
+}
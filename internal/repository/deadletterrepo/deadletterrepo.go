@@ -0,0 +1,103 @@
+// Package deadletterrepo defines the storage interface for dead-lettered
+// notifications and an in-memory reference implementation.
+package deadletterrepo
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/deadletter"
+)
+
+// ErrNotFound is returned when a letter lookup matches no record.
+var ErrNotFound = errors.New("dead letter not found")
+
+// Storage is the persistence contract for dead-lettered notifications.
+type Storage interface {
+	Create(ctx context.Context, letter *deadletter.Letter) error
+	Get(ctx context.Context, id string) (*deadletter.Letter, error)
+	// Update overwrites an existing letter, e.g. to bump Attempts after a
+	// failed replay. It leaves CreatedAt untouched.
+	Update(ctx context.Context, letter *deadletter.Letter) error
+	Delete(ctx context.Context, id string) error
+	// List returns every dead letter, oldest first, for an admin to triage.
+	List(ctx context.Context) ([]*deadletter.Letter, error)
+}
+
+// MemStorage is an in-memory Storage implementation safe for concurrent use.
+type MemStorage struct {
+	mu      sync.RWMutex
+	letters map[string]*deadletter.Letter
+}
+
+// NewMemStorage returns an empty in-memory dead letter storage.
+func NewMemStorage() *MemStorage {
+	return &MemStorage{letters: make(map[string]*deadletter.Letter)}
+}
+
+func (s *MemStorage) Create(_ context.Context, letter *deadletter.Letter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	letter.CreatedAt = time.Now()
+	s.letters[letter.ID] = letter
+
+	return nil
+}
+
+func (s *MemStorage) Get(_ context.Context, id string) (*deadletter.Letter, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	letter, ok := s.letters[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return letter, nil
+}
+
+func (s *MemStorage) Update(_ context.Context, letter *deadletter.Letter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.letters[letter.ID]
+	if !ok {
+		return ErrNotFound
+	}
+
+	letter.CreatedAt = existing.CreatedAt
+	s.letters[letter.ID] = letter
+
+	return nil
+}
+
+func (s *MemStorage) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.letters[id]; !ok {
+		return ErrNotFound
+	}
+
+	delete(s.letters, id)
+
+	return nil
+}
+
+func (s *MemStorage) List(_ context.Context) ([]*deadletter.Letter, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]*deadletter.Letter, 0, len(s.letters))
+	for _, letter := range s.letters {
+		out = append(out, letter)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+
+	return out, nil
+}
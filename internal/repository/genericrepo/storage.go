@@ -0,0 +1,138 @@
+// Package genericrepo defines the storage interface for generic key/value
+// secrets and an in-memory reference implementation.
+package genericrepo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/generic"
+)
+
+// ErrNotFound is returned when a secret lookup matches no record.
+var ErrNotFound = errors.New("generic secret not found")
+
+// Storage is the persistence contract for generic key/value secrets.
+type Storage interface {
+	Create(ctx context.Context, s *generic.Secret) error
+	Get(ctx context.Context, id string) (*generic.Secret, error)
+	Update(ctx context.Context, s *generic.Secret) error
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context, userID string) ([]*generic.Secret, error)
+	// GetSecrets returns userID's secrets matching any of names, in a single
+	// lookup instead of N sequential Gets. Used by batch delete, archive
+	// download and export endpoints.
+	GetSecrets(ctx context.Context, userID string, names []string) ([]*generic.Secret, error)
+	// ListAll returns every secret across all users, for operator tooling
+	// (fsck, integrity audits) that has no per-request user scope.
+	ListAll(ctx context.Context) ([]*generic.Secret, error)
+}
+
+// MemStorage is an in-memory Storage implementation safe for concurrent use.
+type MemStorage struct {
+	mu      sync.RWMutex
+	secrets map[string]*generic.Secret
+}
+
+// NewMemStorage returns an empty in-memory generic secret storage.
+func NewMemStorage() *MemStorage {
+	return &MemStorage{secrets: make(map[string]*generic.Secret)}
+}
+
+func (s *MemStorage) Create(_ context.Context, sec *generic.Secret) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	sec.CreatedAt = now
+	sec.UpdatedAt = now
+	s.secrets[sec.ID] = sec
+
+	return nil
+}
+
+func (s *MemStorage) Get(_ context.Context, id string) (*generic.Secret, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sec, ok := s.secrets[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return sec, nil
+}
+
+func (s *MemStorage) Update(_ context.Context, sec *generic.Secret) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.secrets[sec.ID]; !ok {
+		return ErrNotFound
+	}
+
+	sec.UpdatedAt = time.Now()
+	s.secrets[sec.ID] = sec
+
+	return nil
+}
+
+func (s *MemStorage) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.secrets[id]; !ok {
+		return ErrNotFound
+	}
+
+	delete(s.secrets, id)
+
+	return nil
+}
+
+func (s *MemStorage) List(_ context.Context, userID string) ([]*generic.Secret, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]*generic.Secret, 0)
+	for _, sec := range s.secrets {
+		if sec.UserID == userID {
+			out = append(out, sec)
+		}
+	}
+
+	return out, nil
+}
+
+func (s *MemStorage) GetSecrets(_ context.Context, userID string, names []string) ([]*generic.Secret, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	out := make([]*generic.Secret, 0, len(names))
+	for _, sec := range s.secrets {
+		if sec.UserID == userID && wanted[sec.Name] {
+			out = append(out, sec)
+		}
+	}
+
+	return out, nil
+}
+
+func (s *MemStorage) ListAll(_ context.Context) ([]*generic.Secret, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]*generic.Secret, 0, len(s.secrets))
+	for _, sec := range s.secrets {
+		out = append(out, sec)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,86 @@
+// Package relationrepo defines the storage interface for secret relations
+// and an in-memory reference implementation.
+package relationrepo
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/relation"
+)
+
+// Storage is the persistence contract for secret relations. Callers are
+// responsible for calling DeleteAllFor when a secret is deleted, so no
+// relation is left pointing at a secret that no longer exists.
+type Storage interface {
+	Create(ctx context.Context, rel *relation.Relation) error
+	Delete(ctx context.Context, from, to relation.Ref) error
+	// ListFor returns every relation where ref appears on either side.
+	ListFor(ctx context.Context, ref relation.Ref) ([]*relation.Relation, error)
+	// DeleteAllFor removes every relation where ref appears on either side,
+	// maintaining integrity when the secret identified by ref is deleted.
+	DeleteAllFor(ctx context.Context, ref relation.Ref) error
+}
+
+// MemStorage is an in-memory Storage implementation safe for concurrent use.
+type MemStorage struct {
+	mu        sync.RWMutex
+	relations map[string]*relation.Relation
+}
+
+// NewMemStorage returns an empty in-memory relation storage.
+func NewMemStorage() *MemStorage {
+	return &MemStorage{relations: make(map[string]*relation.Relation)}
+}
+
+func relKey(from, to relation.Ref) string {
+	return from.Type + ":" + from.ID + "|" + to.Type + ":" + to.ID
+}
+
+func (s *MemStorage) Create(_ context.Context, rel *relation.Relation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rel.CreatedAt = time.Now()
+	s.relations[relKey(rel.From, rel.To)] = rel
+
+	return nil
+}
+
+func (s *MemStorage) Delete(_ context.Context, from, to relation.Ref) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.relations, relKey(from, to))
+	delete(s.relations, relKey(to, from))
+
+	return nil
+}
+
+func (s *MemStorage) ListFor(_ context.Context, ref relation.Ref) ([]*relation.Relation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]*relation.Relation, 0)
+	for _, rel := range s.relations {
+		if rel.From == ref || rel.To == ref {
+			out = append(out, rel)
+		}
+	}
+
+	return out, nil
+}
+
+func (s *MemStorage) DeleteAllFor(_ context.Context, ref relation.Ref) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, rel := range s.relations {
+		if rel.From == ref || rel.To == ref {
+			delete(s.relations, key)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+// Package reminderrepo defines the storage interface for reminder rules and
+// an in-memory reference implementation.
+package reminderrepo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/reminder"
+)
+
+// ErrNotFound is returned when a rule lookup matches no record.
+var ErrNotFound = errors.New("reminder rule not found")
+
+// Storage is the persistence contract for reminder rules.
+type Storage interface {
+	Create(ctx context.Context, rule *reminder.Rule) error
+	Get(ctx context.Context, id string) (*reminder.Rule, error)
+	Update(ctx context.Context, rule *reminder.Rule) error
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context, userID string) ([]*reminder.Rule, error)
+	// ListAll returns every rule across all users, for the delivery job,
+	// which has no per-request user scope.
+	ListAll(ctx context.Context) ([]*reminder.Rule, error)
+}
+
+// MemStorage is an in-memory Storage implementation safe for concurrent use.
+type MemStorage struct {
+	mu    sync.RWMutex
+	rules map[string]*reminder.Rule
+}
+
+// NewMemStorage returns an empty in-memory reminder rule storage.
+func NewMemStorage() *MemStorage {
+	return &MemStorage{rules: make(map[string]*reminder.Rule)}
+}
+
+func (s *MemStorage) Create(_ context.Context, rule *reminder.Rule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+	s.rules[rule.ID] = rule
+
+	return nil
+}
+
+func (s *MemStorage) Get(_ context.Context, id string) (*reminder.Rule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rule, ok := s.rules[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return rule, nil
+}
+
+func (s *MemStorage) Update(_ context.Context, rule *reminder.Rule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.rules[rule.ID]; !ok {
+		return ErrNotFound
+	}
+
+	rule.UpdatedAt = time.Now()
+	s.rules[rule.ID] = rule
+
+	return nil
+}
+
+func (s *MemStorage) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.rules[id]; !ok {
+		return ErrNotFound
+	}
+
+	delete(s.rules, id)
+
+	return nil
+}
+
+func (s *MemStorage) List(_ context.Context, userID string) ([]*reminder.Rule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]*reminder.Rule, 0)
+	for _, rule := range s.rules {
+		if rule.UserID == userID {
+			out = append(out, rule)
+		}
+	}
+
+	return out, nil
+}
+
+func (s *MemStorage) ListAll(_ context.Context) ([]*reminder.Rule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]*reminder.Rule, 0, len(s.rules))
+	for _, rule := range s.rules {
+		out = append(out, rule)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,104 @@
+// Package searchindexrepo stores the blind-indexed tokens search uses to
+// match encrypted text secrets, and an in-memory reference implementation.
+package searchindexrepo
+
+import (
+	"context"
+	"sync"
+)
+
+// Storage is the persistence contract for a user's blind search index.
+type Storage interface {
+	// Index replaces secretID's indexed tokens with tokens. Called with an
+	// empty tokens slice to remove a secret from the index.
+	Index(ctx context.Context, userID, secretID string, tokens []string) error
+	// Search returns the IDs of userID's secrets indexed under every token
+	// in tokens (an AND match across tokens).
+	Search(ctx context.Context, userID string, tokens []string) ([]string, error)
+	// Delete removes secretID from the index entirely.
+	Delete(ctx context.Context, userID, secretID string) error
+}
+
+// MemStorage is an in-memory Storage implementation safe for concurrent use.
+type MemStorage struct {
+	mu sync.Mutex
+	// entries maps userID -> token -> set of secretIDs indexed under it.
+	entries map[string]map[string]map[string]bool
+}
+
+// NewMemStorage returns an empty in-memory search index.
+func NewMemStorage() *MemStorage {
+	return &MemStorage{entries: make(map[string]map[string]map[string]bool)}
+}
+
+func (s *MemStorage) Index(_ context.Context, userID, secretID string, tokens []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.deleteLocked(userID, secretID); err != nil {
+		return err
+	}
+
+	byToken, ok := s.entries[userID]
+	if !ok {
+		byToken = make(map[string]map[string]bool)
+		s.entries[userID] = byToken
+	}
+
+	for _, token := range tokens {
+		if byToken[token] == nil {
+			byToken[token] = make(map[string]bool)
+		}
+
+		byToken[token][secretID] = true
+	}
+
+	return nil
+}
+
+func (s *MemStorage) Search(_ context.Context, userID string, tokens []string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	byToken, ok := s.entries[userID]
+	if !ok || len(tokens) == 0 {
+		return nil, nil
+	}
+
+	matches := byToken[tokens[0]]
+
+	result := make(map[string]bool, len(matches))
+	for id := range matches {
+		result[id] = true
+	}
+
+	for _, token := range tokens[1:] {
+		for id := range result {
+			if !byToken[token][id] {
+				delete(result, id)
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(result))
+	for id := range result {
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func (s *MemStorage) Delete(_ context.Context, userID, secretID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.deleteLocked(userID, secretID)
+}
+
+func (s *MemStorage) deleteLocked(userID, secretID string) error {
+	for _, secretIDs := range s.entries[userID] {
+		delete(secretIDs, secretID)
+	}
+
+	return nil
+}
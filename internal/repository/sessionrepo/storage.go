@@ -0,0 +1,49 @@
+// Package sessionrepo tracks the device fingerprints a user has previously
+// logged in from, so new-device logins can be detected.
+package sessionrepo
+
+import (
+	"context"
+	"sync"
+)
+
+// Storage records known device fingerprints per user.
+type Storage interface {
+	// IsKnown reports whether fingerprint has been seen before for userID.
+	IsKnown(ctx context.Context, userID, fingerprint string) (bool, error)
+	// Remember records fingerprint as seen for userID.
+	Remember(ctx context.Context, userID, fingerprint string) error
+}
+
+// MemStorage is an in-memory Storage implementation safe for concurrent use.
+type MemStorage struct {
+	mu   sync.RWMutex
+	seen map[string]map[string]struct{}
+}
+
+// NewMemStorage returns an empty in-memory session storage.
+func NewMemStorage() *MemStorage {
+	return &MemStorage{seen: make(map[string]map[string]struct{})}
+}
+
+func (s *MemStorage) IsKnown(_ context.Context, userID, fingerprint string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.seen[userID][fingerprint]
+
+	return ok, nil
+}
+
+func (s *MemStorage) Remember(_ context.Context, userID, fingerprint string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.seen[userID] == nil {
+		s.seen[userID] = make(map[string]struct{})
+	}
+
+	s.seen[userID][fingerprint] = struct{}{}
+
+	return nil
+}
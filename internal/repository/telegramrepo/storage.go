@@ -0,0 +1,111 @@
+// Package telegramrepo stores the link between a gophkeeper account and a
+// Telegram chat, plus the short-lived codes used to establish that link.
+package telegramrepo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCodeNotFound is returned when a link code doesn't exist or has expired.
+var ErrCodeNotFound = errors.New("telegramrepo: link code not found or expired")
+
+// ErrChatNotLinked is returned when a chat has no linked account.
+var ErrChatNotLinked = errors.New("telegramrepo: chat not linked")
+
+type linkCode struct {
+	userID    string
+	expiresAt time.Time
+}
+
+// Storage manages link codes and user<->chat bindings.
+type Storage interface {
+	// CreateCode stores a one-time code for userID, valid until expiresAt.
+	CreateCode(ctx context.Context, code, userID string, expiresAt time.Time) error
+	// ConsumeCode returns and invalidates the userID for code, failing if
+	// the code is unknown or expired.
+	ConsumeCode(ctx context.Context, code string) (userID string, err error)
+	// BindChat associates chatID with userID, replacing any prior binding.
+	BindChat(ctx context.Context, chatID int64, userID string) error
+	// UserForChat returns the userID bound to chatID.
+	UserForChat(ctx context.Context, chatID int64) (userID string, err error)
+	// ChatForUser returns the chatID bound to userID, the reverse of
+	// UserForChat, for notifysvc to address outbound messages.
+	ChatForUser(ctx context.Context, userID string) (chatID int64, err error)
+}
+
+// MemStorage is an in-memory Storage, suitable for a single server instance.
+type MemStorage struct {
+	mu    sync.RWMutex
+	codes map[string]linkCode
+	chats map[int64]string
+}
+
+// NewMemStorage returns an empty in-memory Storage.
+func NewMemStorage() *MemStorage {
+	return &MemStorage{
+		codes: make(map[string]linkCode),
+		chats: make(map[int64]string),
+	}
+}
+
+func (s *MemStorage) CreateCode(_ context.Context, code, userID string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.codes[code] = linkCode{userID: userID, expiresAt: expiresAt}
+
+	return nil
+}
+
+func (s *MemStorage) ConsumeCode(_ context.Context, code string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lc, ok := s.codes[code]
+	if !ok || time.Now().After(lc.expiresAt) {
+		delete(s.codes, code)
+
+		return "", ErrCodeNotFound
+	}
+
+	delete(s.codes, code)
+
+	return lc.userID, nil
+}
+
+func (s *MemStorage) BindChat(_ context.Context, chatID int64, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.chats[chatID] = userID
+
+	return nil
+}
+
+func (s *MemStorage) UserForChat(_ context.Context, chatID int64) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	userID, ok := s.chats[chatID]
+	if !ok {
+		return "", ErrChatNotLinked
+	}
+
+	return userID, nil
+}
+
+func (s *MemStorage) ChatForUser(_ context.Context, userID string) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for chatID, uid := range s.chats {
+		if uid == userID {
+			return chatID, nil
+		}
+	}
+
+	return 0, ErrChatNotLinked
+}
@@ -0,0 +1,211 @@
+// Package textrepo defines the storage interface for text secrets' metadata
+// and an in-memory reference implementation. The encrypted payload itself
+// lives in object storage, addressed by Content.ObjectKey.
+package textrepo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/text"
+)
+
+// ErrNotFound is returned when a secret lookup matches no record.
+var ErrNotFound = errors.New("text secret not found")
+
+// Storage is the persistence contract for text secret metadata.
+type Storage interface {
+	Create(ctx context.Context, s *text.Secret) error
+	Get(ctx context.Context, id string) (*text.Secret, error)
+	Update(ctx context.Context, s *text.Secret) error
+	// Delete permanently removes the secret with id. It is only ever
+	// called for a secret already trashed via Trash; use Trash for a
+	// user-facing delete.
+	Delete(ctx context.Context, id string) error
+	// List returns userID's secrets that have not been trashed via Trash.
+	List(ctx context.Context, userID string) ([]*text.Secret, error)
+	// GetSecrets returns userID's secrets matching any of names, in a
+	// single lookup instead of N sequential Gets.
+	GetSecrets(ctx context.Context, userID string, names []string) ([]*text.Secret, error)
+	// ListAll returns every secret across all users, including trashed
+	// ones, for operator tooling (fsck, integrity audits) that has no
+	// per-request user scope.
+	ListAll(ctx context.Context) ([]*text.Secret, error)
+	// Trash marks the secret with id deleted as of now, hiding it from
+	// List without touching its content, so it can still be restored or
+	// later purged by retentionsvc.
+	Trash(ctx context.Context, id string, now time.Time) error
+	// Restore clears DeletedAt on the secret with id, returning it to
+	// List.
+	Restore(ctx context.Context, id string) error
+	// ListTrashed returns userID's trashed secrets.
+	ListTrashed(ctx context.Context, userID string) ([]*text.Secret, error)
+	// ListAllTrashed returns every trashed secret across all users, for
+	// retentionsvc's background purge.
+	ListAllTrashed(ctx context.Context) ([]*text.Secret, error)
+}
+
+// MemStorage is an in-memory Storage implementation safe for concurrent use.
+type MemStorage struct {
+	mu      sync.RWMutex
+	secrets map[string]*text.Secret
+}
+
+// NewMemStorage returns an empty in-memory text secret storage.
+func NewMemStorage() *MemStorage {
+	return &MemStorage{secrets: make(map[string]*text.Secret)}
+}
+
+func (s *MemStorage) Create(_ context.Context, sec *text.Secret) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	sec.CreatedAt = now
+	sec.UpdatedAt = now
+	s.secrets[sec.ID] = sec
+
+	return nil
+}
+
+func (s *MemStorage) Get(_ context.Context, id string) (*text.Secret, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sec, ok := s.secrets[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return sec, nil
+}
+
+func (s *MemStorage) Update(_ context.Context, sec *text.Secret) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.secrets[sec.ID]; !ok {
+		return ErrNotFound
+	}
+
+	sec.UpdatedAt = time.Now()
+	s.secrets[sec.ID] = sec
+
+	return nil
+}
+
+func (s *MemStorage) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.secrets[id]; !ok {
+		return ErrNotFound
+	}
+
+	delete(s.secrets, id)
+
+	return nil
+}
+
+func (s *MemStorage) List(_ context.Context, userID string) ([]*text.Secret, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]*text.Secret, 0)
+	for _, sec := range s.secrets {
+		if sec.UserID == userID && sec.DeletedAt == nil {
+			out = append(out, sec)
+		}
+	}
+
+	return out, nil
+}
+
+func (s *MemStorage) ListAll(_ context.Context) ([]*text.Secret, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]*text.Secret, 0, len(s.secrets))
+	for _, sec := range s.secrets {
+		out = append(out, sec)
+	}
+
+	return out, nil
+}
+
+func (s *MemStorage) GetSecrets(_ context.Context, userID string, names []string) ([]*text.Secret, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	out := make([]*text.Secret, 0, len(names))
+	for _, sec := range s.secrets {
+		if sec.UserID == userID && sec.DeletedAt == nil && wanted[sec.Name] {
+			out = append(out, sec)
+		}
+	}
+
+	return out, nil
+}
+
+func (s *MemStorage) Trash(_ context.Context, id string, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sec, ok := s.secrets[id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	sec.DeletedAt = &now
+
+	return nil
+}
+
+func (s *MemStorage) Restore(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sec, ok := s.secrets[id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	sec.DeletedAt = nil
+
+	return nil
+}
+
+func (s *MemStorage) ListTrashed(_ context.Context, userID string) ([]*text.Secret, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]*text.Secret, 0)
+	for _, sec := range s.secrets {
+		if sec.UserID == userID && sec.DeletedAt != nil {
+			out = append(out, sec)
+		}
+	}
+
+	return out, nil
+}
+
+func (s *MemStorage) ListAllTrashed(_ context.Context) ([]*text.Secret, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]*text.Secret, 0)
+	for _, sec := range s.secrets {
+		if sec.DeletedAt != nil {
+			out = append(out, sec)
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,232 @@
+// Package userrepo defines the storage interface for user accounts and an
+// in-memory reference implementation used in tests.
+package userrepo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/user"
+)
+
+// ErrUserNotFound is returned when a user lookup matches no record.
+var ErrUserNotFound = errors.New("user not found")
+
+// ErrLoginAlreadyExists is returned when a create/update would violate the
+// uniqueness of a user's login.
+var ErrLoginAlreadyExists = errors.New("login already exists")
+
+// Storage is the persistence contract for user accounts.
+type Storage interface {
+	CreateUser(ctx context.Context, u *user.User) error
+	GetUser(ctx context.Context, id string) (*user.User, error)
+	GetUserByLogin(ctx context.Context, login string) (*user.User, error)
+	// UpdateUser persists changes to an existing user's mutable fields
+	// (login and/or hashed password). It returns ErrLoginAlreadyExists if
+	// the new login collides with a different user.
+	UpdateUser(ctx context.Context, u *user.User) error
+	// UpdateLastLogin stamps the user's last_login_at with the given time.
+	UpdateLastLogin(ctx context.Context, id string, at time.Time) error
+	// SetNotifyOnNewLogin updates the user's new-device login notification
+	// preference.
+	SetNotifyOnNewLogin(ctx context.Context, id string, enabled bool) error
+	// SetDisabled marks the account disabled or re-enables it, without
+	// deleting its data.
+	SetDisabled(ctx context.Context, id string, disabled bool) error
+	// BumpTokenVersion increments the user's token version, invalidating
+	// every JWT issued before the call.
+	BumpTokenVersion(ctx context.Context, id string) error
+	// SetSearchIndexEnabled opts the account in or out of server-side
+	// search indexing of its text secrets.
+	SetSearchIndexEnabled(ctx context.Context, id string, enabled bool) error
+	// SetTrashRetention overrides how long id's trashed secrets are kept
+	// before permanent purge. Zero resets the account to the instance-wide
+	// default.
+	SetTrashRetention(ctx context.Context, id string, retention time.Duration) error
+	// SetNotifyChannels replaces the account's notification channel
+	// preference. An empty slice resets it to the ["email"] default.
+	SetNotifyChannels(ctx context.Context, id string, channels []string) error
+}
+
+// MemStorage is an in-memory Storage implementation safe for concurrent use.
+type MemStorage struct {
+	mu    sync.RWMutex
+	users map[string]*user.User
+}
+
+// NewMemStorage returns an empty in-memory user storage.
+func NewMemStorage() *MemStorage {
+	return &MemStorage{
+		users: make(map[string]*user.User),
+	}
+}
+
+func (s *MemStorage) CreateUser(_ context.Context, u *user.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.users {
+		if existing.Login == u.Login {
+			return ErrLoginAlreadyExists
+		}
+	}
+
+	now := time.Now()
+	u.CreatedAt = now
+	u.UpdatedAt = now
+	s.users[u.ID] = u
+
+	return nil
+}
+
+func (s *MemStorage) GetUser(_ context.Context, id string) (*user.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	u, ok := s.users[id]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+
+	return u, nil
+}
+
+func (s *MemStorage) GetUserByLogin(_ context.Context, login string) (*user.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, u := range s.users {
+		if u.Login == login {
+			return u, nil
+		}
+	}
+
+	return nil, ErrUserNotFound
+}
+
+func (s *MemStorage) UpdateUser(_ context.Context, u *user.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.users[u.ID]
+	if !ok {
+		return ErrUserNotFound
+	}
+
+	for id, other := range s.users {
+		if id != u.ID && other.Login == u.Login {
+			return ErrLoginAlreadyExists
+		}
+	}
+
+	existing.Login = u.Login
+	existing.HashedPassword = u.HashedPassword
+	existing.UpdatedAt = time.Now()
+
+	return nil
+}
+
+func (s *MemStorage) UpdateLastLogin(_ context.Context, id string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[id]
+	if !ok {
+		return ErrUserNotFound
+	}
+
+	u.LastLoginAt = &at
+
+	return nil
+}
+
+func (s *MemStorage) SetNotifyOnNewLogin(_ context.Context, id string, enabled bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[id]
+	if !ok {
+		return ErrUserNotFound
+	}
+
+	u.NotifyOnNewLogin = enabled
+
+	return nil
+}
+
+func (s *MemStorage) SetDisabled(_ context.Context, id string, disabled bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[id]
+	if !ok {
+		return ErrUserNotFound
+	}
+
+	u.Disabled = disabled
+	u.UpdatedAt = time.Now()
+
+	return nil
+}
+
+func (s *MemStorage) BumpTokenVersion(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[id]
+	if !ok {
+		return ErrUserNotFound
+	}
+
+	u.TokenVersion++
+	u.UpdatedAt = time.Now()
+
+	return nil
+}
+
+func (s *MemStorage) SetSearchIndexEnabled(_ context.Context, id string, enabled bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[id]
+	if !ok {
+		return ErrUserNotFound
+	}
+
+	u.SearchIndexEnabled = enabled
+	u.UpdatedAt = time.Now()
+
+	return nil
+}
+
+func (s *MemStorage) SetTrashRetention(_ context.Context, id string, retention time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[id]
+	if !ok {
+		return ErrUserNotFound
+	}
+
+	u.TrashRetention = retention
+	u.UpdatedAt = time.Now()
+
+	return nil
+}
+
+func (s *MemStorage) SetNotifyChannels(_ context.Context, id string, channels []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[id]
+	if !ok {
+		return ErrUserNotFound
+	}
+
+	u.NotifyChannels = channels
+	u.UpdatedAt = time.Now()
+
+	return nil
+}
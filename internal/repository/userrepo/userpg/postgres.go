@@ -0,0 +1,226 @@
+// Package userpg is the Postgres-backed implementation of userrepo.Storage.
+package userpg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/user"
+	"github.com/andymarkow/gophkeeper/internal/repository/userrepo"
+)
+
+const uniqueViolationCode = "23505"
+
+// Storage is a Postgres-backed userrepo.Storage implementation.
+type Storage struct {
+	pool *pgxpool.Pool
+}
+
+// NewStorage returns a Storage backed by the given connection pool.
+func NewStorage(pool *pgxpool.Pool) *Storage {
+	return &Storage{pool: pool}
+}
+
+func (s *Storage) CreateUser(ctx context.Context, u *user.User) error {
+	const query = `
+		INSERT INTO users (id, login, hashed_password, tenant_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at, updated_at`
+
+	err := s.pool.QueryRow(ctx, query, u.ID, u.Login, u.HashedPassword, u.TenantID).
+		Scan(&u.CreatedAt, &u.UpdatedAt)
+	if isUniqueViolation(err) {
+		return userrepo.ErrLoginAlreadyExists
+	}
+	if err != nil {
+		return fmt.Errorf("insert user: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Storage) GetUser(ctx context.Context, id string) (*user.User, error) {
+	const query = `
+		SELECT id, login, hashed_password, created_at, updated_at, last_login_at, email, notify_on_new_login, disabled, token_version, search_index_enabled, tenant_id, trash_retention_seconds, notify_channels
+		FROM users
+		WHERE id = $1`
+
+	return s.scanUser(s.pool.QueryRow(ctx, query, id))
+}
+
+func (s *Storage) GetUserByLogin(ctx context.Context, login string) (*user.User, error) {
+	const query = `
+		SELECT id, login, hashed_password, created_at, updated_at, last_login_at, email, notify_on_new_login, disabled, token_version, search_index_enabled, tenant_id, trash_retention_seconds, notify_channels
+		FROM users
+		WHERE login = $1`
+
+	return s.scanUser(s.pool.QueryRow(ctx, query, login))
+}
+
+// UpdateLastLogin stamps the user's last_login_at, called after successful
+// authentication.
+func (s *Storage) UpdateLastLogin(ctx context.Context, id string, at time.Time) error {
+	const query = `UPDATE users SET last_login_at = $2 WHERE id = $1`
+
+	tag, err := s.pool.Exec(ctx, query, id, at)
+	if err != nil {
+		return fmt.Errorf("update last login: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return userrepo.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// SetNotifyOnNewLogin updates the user's new-device login notification
+// preference.
+func (s *Storage) SetNotifyOnNewLogin(ctx context.Context, id string, enabled bool) error {
+	const query = `UPDATE users SET notify_on_new_login = $2 WHERE id = $1`
+
+	tag, err := s.pool.Exec(ctx, query, id, enabled)
+	if err != nil {
+		return fmt.Errorf("set notify on new login: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return userrepo.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// UpdateUser persists the user's login and hashed password, supporting both
+// a profile login change and a password change in the same call. Callers
+// that only want to change one field should read the current record first
+// and copy the untouched field across.
+func (s *Storage) UpdateUser(ctx context.Context, u *user.User) error {
+	const query = `
+		UPDATE users
+		SET login = $2, hashed_password = $3, updated_at = now()
+		WHERE id = $1
+		RETURNING updated_at`
+
+	err := s.pool.QueryRow(ctx, query, u.ID, u.Login, u.HashedPassword).Scan(&u.UpdatedAt)
+	if isUniqueViolation(err) {
+		return userrepo.ErrLoginAlreadyExists
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return userrepo.ErrUserNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("update user: %w", err)
+	}
+
+	return nil
+}
+
+// SetDisabled marks the account disabled (deprovisioned) or re-enables it,
+// without deleting its data.
+func (s *Storage) SetDisabled(ctx context.Context, id string, disabled bool) error {
+	const query = `UPDATE users SET disabled = $2, updated_at = now() WHERE id = $1`
+
+	tag, err := s.pool.Exec(ctx, query, id, disabled)
+	if err != nil {
+		return fmt.Errorf("set disabled: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return userrepo.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// BumpTokenVersion increments the user's token version, invalidating every
+// JWT issued before the call.
+func (s *Storage) BumpTokenVersion(ctx context.Context, id string) error {
+	const query = `UPDATE users SET token_version = token_version + 1, updated_at = now() WHERE id = $1`
+
+	tag, err := s.pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("bump token version: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return userrepo.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// SetSearchIndexEnabled opts the account in or out of server-side search
+// indexing of its text secrets.
+func (s *Storage) SetSearchIndexEnabled(ctx context.Context, id string, enabled bool) error {
+	const query = `UPDATE users SET search_index_enabled = $2, updated_at = now() WHERE id = $1`
+
+	tag, err := s.pool.Exec(ctx, query, id, enabled)
+	if err != nil {
+		return fmt.Errorf("set search index enabled: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return userrepo.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// SetTrashRetention overrides how long id's trashed secrets are kept before
+// permanent purge. Zero resets the account to the instance-wide default.
+func (s *Storage) SetTrashRetention(ctx context.Context, id string, retention time.Duration) error {
+	const query = `UPDATE users SET trash_retention_seconds = $2, updated_at = now() WHERE id = $1`
+
+	tag, err := s.pool.Exec(ctx, query, id, int64(retention/time.Second))
+	if err != nil {
+		return fmt.Errorf("set trash retention: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return userrepo.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// SetNotifyChannels replaces id's notification channel preference.
+func (s *Storage) SetNotifyChannels(ctx context.Context, id string, channels []string) error {
+	const query = `UPDATE users SET notify_channels = $2, updated_at = now() WHERE id = $1`
+
+	tag, err := s.pool.Exec(ctx, query, id, channels)
+	if err != nil {
+		return fmt.Errorf("set notify channels: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return userrepo.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (s *Storage) scanUser(row pgx.Row) (*user.User, error) {
+	u := &user.User{}
+
+	var trashRetentionSeconds int64
+
+	err := row.Scan(&u.ID, &u.Login, &u.HashedPassword, &u.CreatedAt, &u.UpdatedAt, &u.LastLoginAt,
+		&u.Email, &u.NotifyOnNewLogin, &u.Disabled, &u.TokenVersion, &u.SearchIndexEnabled, &u.TenantID,
+		&trashRetentionSeconds, &u.NotifyChannels)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, userrepo.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan user: %w", err)
+	}
+
+	u.TrashRetention = time.Duration(trashRetentionSeconds) * time.Second
+
+	return u, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode
+}
@@ -0,0 +1,92 @@
+// Package reqrecorder implements a fixed-size ring buffer of sanitized
+// request/response pairs, sampled from live traffic, so an operator can
+// reproduce a client-reported issue from the admin port instead of asking
+// the client for a packet capture. It must never be enabled in production:
+// even sanitized, a recorded body is a copy of real user data sitting in
+// process memory.
+package reqrecorder
+
+import (
+	"sync"
+	"time"
+)
+
+// sensitiveFields are JSON object keys whose values RedactBody replaces
+// regardless of where they appear in the body.
+var sensitiveFields = map[string]bool{
+	"password":    true,
+	"token":       true,
+	"secret":      true,
+	"payload":     true,
+	"data":        true,
+	"login":       true,
+	"card_number": true,
+	"cvv":         true,
+}
+
+// redacted is substituted for any sensitiveFields value.
+const redacted = "[REDACTED]"
+
+// Entry is one recorded request/response pair.
+type Entry struct {
+	Method       string
+	Path         string
+	Query        string
+	StatusCode   int
+	RequestBody  []byte
+	ResponseBody []byte
+	Duration     time.Duration
+	RecordedAt   time.Time
+}
+
+// Recorder is a fixed-capacity ring buffer of Entries, safe for concurrent
+// use. Once full, each Add overwrites the oldest entry.
+type Recorder struct {
+	mu       sync.Mutex
+	buf      []Entry
+	writePos int
+	full     bool
+}
+
+// New returns a Recorder holding at most capacity Entries. A capacity of 0
+// makes Add a no-op, so recording can be disabled by configuring a zero
+// buffer size rather than special-casing a nil Recorder at every call site.
+func New(capacity int) *Recorder {
+	return &Recorder{buf: make([]Entry, capacity)}
+}
+
+// Add records e, evicting the oldest entry once the buffer is full.
+func (r *Recorder) Add(e Entry) {
+	if len(r.buf) == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.buf[r.writePos] = e
+	r.writePos = (r.writePos + 1) % len(r.buf)
+
+	if r.writePos == 0 {
+		r.full = true
+	}
+}
+
+// Entries returns the recorded entries, oldest first.
+func (r *Recorder) Entries() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		out := make([]Entry, r.writePos)
+		copy(out, r.buf[:r.writePos])
+
+		return out
+	}
+
+	out := make([]Entry, len(r.buf))
+	n := copy(out, r.buf[r.writePos:])
+	copy(out[n:], r.buf[:r.writePos])
+
+	return out
+}
@@ -0,0 +1,52 @@
+package reqrecorder
+
+import "encoding/json"
+
+// RedactBody returns body with every JSON object value keyed by a
+// sensitiveFields name replaced by redacted, regardless of nesting depth.
+// A body that isn't valid JSON is replaced wholesale, since there's no
+// structure to redact selectively and API request/response bodies are JSON
+// in this codebase.
+func RedactBody(body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return []byte(`"[non-json body omitted]"`)
+	}
+
+	out, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return []byte(`"[unrepresentable body omitted]"`)
+	}
+
+	return out
+}
+
+func redactValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(val))
+
+		for k, vv := range val {
+			if sensitiveFields[k] {
+				out[k] = redacted
+			} else {
+				out[k] = redactValue(vv)
+			}
+		}
+
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, vv := range val {
+			out[i] = redactValue(vv)
+		}
+
+		return out
+	default:
+		return val
+	}
+}
@@ -0,0 +1,54 @@
+// Package searchindex implements blind indexing: deterministic, keyed
+// tokens that let the server match search queries against encrypted text
+// secrets without ever seeing their plaintext.
+package searchindex
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+var wordPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// Tokenize splits text into its lowercased word tokens, deduplicated, in the
+// form both indexing and search queries are blinded from.
+func Tokenize(text string) []string {
+	seen := make(map[string]bool)
+
+	var tokens []string
+
+	for _, word := range wordPattern.FindAllString(strings.ToLower(text), -1) {
+		if !seen[word] {
+			seen[word] = true
+
+			tokens = append(tokens, word)
+		}
+	}
+
+	return tokens
+}
+
+// BlindToken returns the deterministic, keyed token for term: an HMAC-SHA256
+// of term under key, hex-encoded. Because it's keyed, a leaked index can't
+// be dictionary-attacked without also recovering the key; because it's
+// deterministic, equal terms always index to the same token, making exact
+// word search possible without decryption.
+func BlindToken(key []byte, term string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(term))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// BlindTokens applies BlindToken to every entry in terms.
+func BlindTokens(key []byte, terms []string) []string {
+	tokens := make([]string, len(terms))
+	for i, term := range terms {
+		tokens[i] = BlindToken(key, term)
+	}
+
+	return tokens
+}
@@ -0,0 +1,208 @@
+// Package apitokensvc implements API token secret CRUD, centrally enforcing
+// ownership and the per-secret ACL.
+package apitokensvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/andymarkow/gophkeeper/internal/cryptutils"
+	"github.com/andymarkow/gophkeeper/internal/domain/acl"
+	"github.com/andymarkow/gophkeeper/internal/domain/apitoken"
+	"github.com/andymarkow/gophkeeper/internal/repository/apitokenrepo"
+)
+
+// ErrAccessDenied is returned when the caller is neither the secret's owner
+// nor granted access via its ACL.
+var ErrAccessDenied = errors.New("access denied")
+
+// ErrSecretLimitExceeded is returned by Create when userID already owns
+// maxSecrets API token secrets.
+var ErrSecretLimitExceeded = errors.New("api token secret limit exceeded for this account")
+
+// Service implements API token secret operations.
+type Service struct {
+	storage    apitokenrepo.Storage
+	keyring    *cryptutils.Keyring
+	maxSecrets int
+}
+
+// NewService returns a Service backed by storage, decrypting tokens for
+// masking with keyring. maxSecrets caps how many API token secrets a single
+// user may own; 0 disables the cap.
+func NewService(storage apitokenrepo.Storage, keyring *cryptutils.Keyring, maxSecrets int) *Service {
+	return &Service{storage: storage, keyring: keyring, maxSecrets: maxSecrets}
+}
+
+// EncryptToken encrypts token under the keyring's primary key, returning the
+// ciphertext and key ID to store on a Secret.
+func (s *Service) EncryptToken(token string) (ciphertext []byte, keyID string, err error) {
+	ciphertext, err = cryptutils.Encrypt([]byte(token), s.keyring.PrimaryKey())
+	if err != nil {
+		return nil, "", fmt.Errorf("encrypt token: %w", err)
+	}
+
+	return ciphertext, s.keyring.PrimaryKeyID(), nil
+}
+
+// DecryptToken decrypts sec's Token in full.
+func (s *Service) DecryptToken(sec *apitoken.Secret) (string, error) {
+	key, err := s.keyring.Key(sec.KeyID)
+	if err != nil {
+		return "", fmt.Errorf("select decryption key: %w", err)
+	}
+
+	plaintext, err := cryptutils.Decrypt(sec.Token, key)
+	if err != nil {
+		return "", fmt.Errorf("decrypt token: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+// Create stores a new secret owned by userID. Fails with
+// ErrSecretLimitExceeded if userID already owns the configured maximum.
+func (s *Service) Create(ctx context.Context, userID string, sec *apitoken.Secret) error {
+	if s.maxSecrets > 0 {
+		existing, err := s.storage.List(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("list api token secrets: %w", err)
+		}
+
+		if len(existing) >= s.maxSecrets {
+			return ErrSecretLimitExceeded
+		}
+	}
+
+	sec.UserID = userID
+
+	if err := s.storage.Create(ctx, sec); err != nil {
+		return fmt.Errorf("create api token secret: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns the secret with id if userID is its owner or has been granted
+// read access via its ACL.
+func (s *Service) Get(ctx context.Context, userID, id string) (*apitoken.Secret, error) {
+	sec, err := s.storage.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get api token secret: %w", err)
+	}
+
+	if !canAccess(sec, userID, acl.PermissionRead) {
+		return nil, ErrAccessDenied
+	}
+
+	return sec, nil
+}
+
+// Update persists changes to sec if userID is its owner or has been granted
+// write access via its ACL.
+func (s *Service) Update(ctx context.Context, userID string, sec *apitoken.Secret) error {
+	existing, err := s.storage.Get(ctx, sec.ID)
+	if err != nil {
+		return fmt.Errorf("get api token secret: %w", err)
+	}
+
+	if !canAccess(existing, userID, acl.PermissionWrite) {
+		return ErrAccessDenied
+	}
+
+	sec.UserID = existing.UserID
+
+	if err := s.storage.Update(ctx, sec); err != nil {
+		return fmt.Errorf("update api token secret: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the secret with id if userID is its owner. ACL grants do
+// not extend to deletion, only the owner can delete.
+func (s *Service) Delete(ctx context.Context, userID, id string) error {
+	existing, err := s.storage.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get api token secret: %w", err)
+	}
+
+	if existing.UserID != userID {
+		return ErrAccessDenied
+	}
+
+	if err := s.storage.Delete(ctx, id); err != nil {
+		return fmt.Errorf("delete api token secret: %w", err)
+	}
+
+	return nil
+}
+
+// List returns every secret owned by userID. It does not include secrets
+// merely shared with userID via ACL.
+func (s *Service) List(ctx context.Context, userID string) ([]*apitoken.Secret, error) {
+	secrets, err := s.storage.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list api token secrets: %w", err)
+	}
+
+	return secrets, nil
+}
+
+// Masked is an API token secret with Token decrypted and masked, returned by
+// ListMasked so clients can identify a token (e.g. by its last few
+// characters) without ever receiving it in full.
+type Masked struct {
+	*apitoken.Secret
+	Token string
+}
+
+// ListMasked returns every secret owned by userID with Token decrypted and
+// masked via MaskToken.
+func (s *Service) ListMasked(ctx context.Context, userID string) ([]*Masked, error) {
+	secrets, err := s.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*Masked, 0, len(secrets))
+
+	for _, sec := range secrets {
+		key, err := s.keyring.Key(sec.KeyID)
+		if err != nil {
+			return nil, fmt.Errorf("select decryption key: %w", err)
+		}
+
+		token, err := cryptutils.Decrypt(sec.Token, key)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt token: %w", err)
+		}
+
+		out = append(out, &Masked{Secret: sec, Token: MaskToken(string(token))})
+	}
+
+	return out, nil
+}
+
+// MaskToken replaces all but the last 4 characters of token with "*", so a
+// client can recognize which token is which without it being exposed in
+// full. Tokens of 4 characters or fewer are masked entirely.
+func MaskToken(token string) string {
+	const visible = 4
+
+	if len(token) <= visible {
+		return strings.Repeat("*", len(token))
+	}
+
+	return strings.Repeat("*", len(token)-visible) + token[len(token)-visible:]
+}
+
+func canAccess(sec *apitoken.Secret, userID string, perm acl.Permission) bool {
+	if sec.UserID == userID {
+		return true
+	}
+
+	return sec.ACL.Allows(acl.UserPrincipal(userID), perm)
+}
@@ -0,0 +1,357 @@
+// Package authsvc issues and verifies the JWTs used to authenticate API
+// requests.
+package authsvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/user"
+	"github.com/andymarkow/gophkeeper/internal/passwordhash"
+	"github.com/andymarkow/gophkeeper/internal/repository/userrepo"
+)
+
+// ErrInvalidToken is returned for tokens that fail signature, expiry or
+// claims validation.
+var ErrInvalidToken = errors.New("invalid token")
+
+// ErrInvalidCredentials is returned when a login/password pair does not
+// match a known user.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// claims is the JWT payload gophkeeper issues.
+type claims struct {
+	jwt.RegisteredClaims
+	UserID string `json:"uid"`
+	// Ver is the user's TokenVersion at issuance time. VerifyToken rejects
+	// the token once the stored version has moved past it.
+	Ver int `json:"ver"`
+	// TenantID is the issuing user's organization, in multi-tenancy
+	// deployments. Empty for single-tenant accounts.
+	TenantID string `json:"tid,omitempty"`
+	// SessionIssuedAt is the session's original IssuedAt, from the very
+	// first IssueToken call. It is carried forward unchanged by
+	// RefreshSlidingToken so maxLifetime is measured from login, not from
+	// the most recent refresh. Absent (zero) on tokens issued before
+	// sliding expiration existed, in which case RefreshSlidingToken falls
+	// back to RegisteredClaims.IssuedAt.
+	SessionIssuedAt *jwt.NumericDate `json:"siat,omitempty"`
+}
+
+// Service issues and verifies user session tokens.
+type Service struct {
+	users  userrepo.Storage
+	secret []byte
+	ttl    time.Duration
+	// maxLifetime bounds how long RefreshSlidingToken will keep extending
+	// a session past its original login, regardless of how active it
+	// stays. 0 disables sliding expiration entirely: RefreshSlidingToken
+	// always returns ErrSlidingDisabled.
+	maxLifetime time.Duration
+	hasher      *passwordhash.Hasher
+}
+
+// NewService returns a Service backed by users, signing tokens with secret
+// and expiring them after ttl. maxLifetime enables sliding expiration (see
+// RefreshSlidingToken) when greater than zero; pass 0 to disable it.
+// bcryptCost configures the password hash cost Authenticate rehashes stale
+// hashes to; pass 0 for passwordhash.DefaultCost.
+func NewService(users userrepo.Storage, secret []byte, ttl, maxLifetime time.Duration, bcryptCost int) *Service {
+	return &Service{
+		users: users, secret: secret, ttl: ttl, maxLifetime: maxLifetime,
+		hasher: passwordhash.NewHasher(bcryptCost),
+	}
+}
+
+// Authenticate verifies login/password against the user store and returns
+// the matching user's ID.
+func (s *Service) Authenticate(ctx context.Context, login, password string) (string, error) {
+	u, err := s.users.GetUserByLogin(ctx, login)
+	if err != nil {
+		if errors.Is(err, userrepo.ErrUserNotFound) {
+			return "", ErrInvalidCredentials
+		}
+
+		return "", fmt.Errorf("get user by login: %w", err)
+	}
+
+	if err := s.hasher.Compare(u.HashedPassword, password); err != nil {
+		return "", ErrInvalidCredentials
+	}
+
+	if u.Disabled {
+		return "", ErrInvalidCredentials
+	}
+
+	s.rehashIfNeeded(ctx, u, password)
+
+	return u.ID, nil
+}
+
+// rehashIfNeeded re-hashes u's password at the service's configured bcrypt
+// cost and persists it when the stored hash was minted at a different cost
+// (typically a lower one from before an operator raised it). Hashing and
+// persistence failures are swallowed: login has already succeeded, and a
+// failed opportunistic rehash just means the account keeps its current
+// hash until the next successful login tries again.
+func (s *Service) rehashIfNeeded(ctx context.Context, u *user.User, password string) {
+	if !s.hasher.NeedsRehash(u.HashedPassword) {
+		return
+	}
+
+	hashed, err := s.hasher.Hash(password)
+	if err != nil {
+		return
+	}
+
+	u.HashedPassword = hashed
+
+	_ = s.users.UpdateUser(ctx, u)
+}
+
+// VerifyPassword re-checks password against userID's stored hash, for
+// endpoints that require a fresh confirmation of identity (CSV export,
+// destructive operations) beyond holding a valid session token.
+func (s *Service) VerifyPassword(ctx context.Context, userID, password string) error {
+	u, err := s.users.GetUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get user: %w", err)
+	}
+
+	if err := s.hasher.Compare(u.HashedPassword, password); err != nil {
+		return ErrInvalidCredentials
+	}
+
+	return nil
+}
+
+// elevationTTL is how long an elevation token stays valid. Short-lived by
+// design: it only needs to cover the handful of sensitive reads right after
+// a fresh re-authentication, not a whole session.
+const elevationTTL = 5 * time.Minute
+
+// elevationSubject marks a token as an elevation grant rather than a
+// session token, so VerifyElevation never accepts a regular session JWT and
+// VerifyToken never accepts an elevation token.
+const elevationSubject = "elevation"
+
+type elevationClaims struct {
+	jwt.RegisteredClaims
+	UserID string `json:"uid"`
+}
+
+// IssueElevationToken returns a short-lived token asserting that userID has
+// just re-authenticated, for step-up access to secrets marked high
+// security. Callers obtain one via VerifyPassword (or a fresh 2FA check)
+// immediately before issuing it.
+func (s *Service) IssueElevationToken(userID string) (string, error) {
+	now := time.Now()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, elevationClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   elevationSubject,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(elevationTTL)),
+		},
+		UserID: userID,
+	})
+
+	signed, err := token.SignedString(s.secret)
+	if err != nil {
+		return "", fmt.Errorf("sign elevation token: %w", err)
+	}
+
+	return signed, nil
+}
+
+// VerifyElevation validates that tokenString is a currently valid elevation
+// token issued for userID.
+func (s *Service) VerifyElevation(tokenString, userID string) error {
+	var c elevationClaims
+
+	_, err := jwt.ParseWithClaims(tokenString, &c, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("%w: unexpected signing method %v", ErrInvalidToken, t.Method)
+		}
+
+		return s.secret, nil
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	if c.Subject != elevationSubject || c.UserID != userID {
+		return ErrInvalidToken
+	}
+
+	return nil
+}
+
+// IssueToken returns a signed JWT asserting userID, valid for the service's
+// configured TTL. The user's current TokenVersion is embedded so a later
+// password change or logout-all can invalidate it.
+func (s *Service) IssueToken(ctx context.Context, userID string) (string, error) {
+	u, err := s.users.GetUser(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("get user: %w", err)
+	}
+
+	now := time.Now()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.ttl)),
+		},
+		UserID:          userID,
+		Ver:             u.TokenVersion,
+		TenantID:        u.TenantID,
+		SessionIssuedAt: jwt.NewNumericDate(now),
+	})
+
+	signed, err := token.SignedString(s.secret)
+	if err != nil {
+		return "", fmt.Errorf("sign token: %w", err)
+	}
+
+	return signed, nil
+}
+
+// ErrSlidingDisabled is returned by RefreshSlidingToken when the service
+// was constructed with maxLifetime 0.
+var ErrSlidingDisabled = errors.New("sliding expiration is disabled")
+
+// ErrSessionExpired is returned by RefreshSlidingToken when the session has
+// already reached its maxLifetime cap and can no longer be extended; the
+// caller must re-authenticate.
+var ErrSessionExpired = errors.New("session has reached its maximum lifetime")
+
+// RefreshSlidingToken validates tokenString exactly as VerifyToken does,
+// then, if the session is still within maxLifetime of its original login,
+// returns a freshly-signed token with a renewed TTL window (capped so it
+// never extends past that original maxLifetime deadline). This lets an
+// actively-used session stay alive indefinitely up to maxLifetime without
+// lengthening how long a token that's merely sitting unused stays valid:
+// an idle token still expires at its own IssuedAt+ttl, same as without
+// sliding expiration.
+func (s *Service) RefreshSlidingToken(ctx context.Context, tokenString string) (string, error) {
+	if s.maxLifetime <= 0 {
+		return "", ErrSlidingDisabled
+	}
+
+	c, err := s.parseClaims(tokenString)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := s.verifyClaims(ctx, c)
+	if err != nil {
+		return "", err
+	}
+
+	sessionStart := c.IssuedAt.Time
+	if c.SessionIssuedAt != nil {
+		sessionStart = c.SessionIssuedAt.Time
+	}
+
+	now := time.Now()
+
+	deadline := sessionStart.Add(s.maxLifetime)
+	if !now.Before(deadline) {
+		return "", ErrSessionExpired
+	}
+
+	newExpiry := now.Add(s.ttl)
+	if newExpiry.After(deadline) {
+		newExpiry = deadline
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(newExpiry),
+		},
+		UserID:          u.ID,
+		Ver:             u.TokenVersion,
+		TenantID:        u.TenantID,
+		SessionIssuedAt: jwt.NewNumericDate(sessionStart),
+	})
+
+	signed, err := token.SignedString(s.secret)
+	if err != nil {
+		return "", fmt.Errorf("sign refreshed token: %w", err)
+	}
+
+	return signed, nil
+}
+
+// VerifyToken validates tokenString and returns the user ID and tenant ID it
+// asserts. It rejects tokens issued before the user's stored TokenVersion
+// was last bumped, so a password change or logout-all revokes them
+// immediately. It also rejects tokens whose tenant claim no longer matches
+// the user's current tenant, so a user moved between tenants can't keep
+// using a token minted under the old one.
+func (s *Service) VerifyToken(ctx context.Context, tokenString string) (userID, tenantID string, err error) {
+	c, err := s.parseClaims(tokenString)
+	if err != nil {
+		return "", "", err
+	}
+
+	if _, err := s.verifyClaims(ctx, c); err != nil {
+		return "", "", err
+	}
+
+	return c.UserID, c.TenantID, nil
+}
+
+// parseClaims verifies tokenString's signature and expiry and decodes its
+// claims, without yet checking them against the user store.
+func (s *Service) parseClaims(tokenString string) (*claims, error) {
+	var c claims
+
+	_, err := jwt.ParseWithClaims(tokenString, &c, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("%w: unexpected signing method %v", ErrInvalidToken, t.Method)
+		}
+
+		return s.secret, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	if c.UserID == "" || c.Subject == elevationSubject {
+		return nil, ErrInvalidToken
+	}
+
+	return &c, nil
+}
+
+// verifyClaims checks c against the current user record: TokenVersion
+// rejects tokens issued before a password change or logout-all, and
+// TenantID rejects tokens minted under a tenant the user has since moved
+// away from. Returns the current user record on success.
+func (s *Service) verifyClaims(ctx context.Context, c *claims) (*user.User, error) {
+	u, err := s.users.GetUser(ctx, c.UserID)
+	if err != nil {
+		if errors.Is(err, userrepo.ErrUserNotFound) {
+			return nil, ErrInvalidToken
+		}
+
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+
+	if u.TokenVersion != c.Ver {
+		return nil, ErrInvalidToken
+	}
+
+	if u.TenantID != c.TenantID {
+		return nil, ErrInvalidToken
+	}
+
+	return u, nil
+}
@@ -0,0 +1,88 @@
+package authsvc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/user"
+	"github.com/andymarkow/gophkeeper/internal/repository/userrepo"
+	"github.com/andymarkow/gophkeeper/internal/service/authsvc"
+)
+
+func TestVerifyToken_RejectsChangedTenant(t *testing.T) {
+	storage := userrepo.NewMemStorage()
+
+	u := &user.User{ID: "user-1", Login: "alice", TenantID: "tenant-a"}
+	if err := storage.CreateUser(context.Background(), u); err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+
+	svc := authsvc.NewService(storage, []byte("test-secret"), time.Hour, 0, 0)
+
+	token, err := svc.IssueToken(context.Background(), u.ID)
+	if err != nil {
+		t.Fatalf("IssueToken() error = %v", err)
+	}
+
+	if _, _, err := svc.VerifyToken(context.Background(), token); err != nil {
+		t.Fatalf("VerifyToken() error = %v, want nil", err)
+	}
+
+	// Simulate the account moving to a different tenant after the token was
+	// issued.
+	u.TenantID = "tenant-b"
+
+	if _, _, err := svc.VerifyToken(context.Background(), token); !errors.Is(err, authsvc.ErrInvalidToken) {
+		t.Fatalf("VerifyToken() error = %v, want %v", err, authsvc.ErrInvalidToken)
+	}
+}
+
+func TestVerifyElevation_AcceptsOwnToken(t *testing.T) {
+	svc := authsvc.NewService(userrepo.NewMemStorage(), []byte("test-secret"), time.Hour, 0, 0)
+
+	token, err := svc.IssueElevationToken("user-1")
+	if err != nil {
+		t.Fatalf("IssueElevationToken() error = %v", err)
+	}
+
+	if err := svc.VerifyElevation(token, "user-1"); err != nil {
+		t.Fatalf("VerifyElevation() error = %v, want nil", err)
+	}
+}
+
+func TestVerifyElevation_RejectsCrossUserToken(t *testing.T) {
+	svc := authsvc.NewService(userrepo.NewMemStorage(), []byte("test-secret"), time.Hour, 0, 0)
+
+	token, err := svc.IssueElevationToken("user-1")
+	if err != nil {
+		t.Fatalf("IssueElevationToken() error = %v", err)
+	}
+
+	if err := svc.VerifyElevation(token, "user-2"); !errors.Is(err, authsvc.ErrInvalidToken) {
+		t.Fatalf("VerifyElevation() error = %v, want %v", err, authsvc.ErrInvalidToken)
+	}
+}
+
+func TestVerifyElevation_RejectsSessionToken(t *testing.T) {
+	storage := userrepo.NewMemStorage()
+
+	u := &user.User{ID: "user-1", Login: "alice"}
+	if err := storage.CreateUser(context.Background(), u); err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+
+	svc := authsvc.NewService(storage, []byte("test-secret"), time.Hour, 0, 0)
+
+	sessionToken, err := svc.IssueToken(context.Background(), u.ID)
+	if err != nil {
+		t.Fatalf("IssueToken() error = %v", err)
+	}
+
+	// A regular session token must never pass as an elevation grant, even
+	// though both are signed with the same secret.
+	if err := svc.VerifyElevation(sessionToken, u.ID); !errors.Is(err, authsvc.ErrInvalidToken) {
+		t.Fatalf("VerifyElevation() error = %v, want %v", err, authsvc.ErrInvalidToken)
+	}
+}
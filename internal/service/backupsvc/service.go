@@ -0,0 +1,260 @@
+// Package backupsvc periodically snapshots file/text secret metadata and
+// the objects they reference into a single encrypted archive, so an
+// instance can be restored after catastrophic data loss without relying on
+// the underlying Postgres/object-store providers' own backup tooling.
+package backupsvc
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/cryptutils"
+	"github.com/andymarkow/gophkeeper/internal/domain/contentinfo"
+	"github.com/andymarkow/gophkeeper/internal/domain/file"
+	"github.com/andymarkow/gophkeeper/internal/domain/text"
+	"github.com/andymarkow/gophkeeper/internal/objrepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/filerepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/textrepo"
+)
+
+// backupPrefix namespaces backup archives in the destination bucket from
+// anything else stored there.
+const backupPrefix = "backups/"
+
+const manifestName = "manifest.json"
+
+// manifest is the JSON index archived alongside the objects it references,
+// letting Restore recreate every row without guessing at object layout.
+type manifest struct {
+	CreatedAt time.Time      `json:"created_at"`
+	Files     []*file.Secret `json:"files"`
+	Texts     []*text.Secret `json:"texts"`
+}
+
+// Service snapshots secret metadata and content into gzip+encrypted tar
+// archives, and restores one back.
+type Service struct {
+	files   filerepo.Storage
+	texts   textrepo.Storage
+	objects objrepo.Storage // primary content bucket
+	backups objrepo.Storage // backup destination bucket/prefix
+	keyring *cryptutils.Keyring
+}
+
+// NewService returns a Service that backs up files/texts and objects into
+// backups, a bucket that should be distinct from objects so a single bucket
+// compromise or deletion can't take out both the data and its backup.
+func NewService(files filerepo.Storage, texts textrepo.Storage, objects, backups objrepo.Storage,
+	keyring *cryptutils.Keyring,
+) *Service {
+	return &Service{files: files, texts: texts, objects: objects, backups: backups, keyring: keyring}
+}
+
+// Run snapshots every file/text secret's metadata and referenced object
+// into one gzip+encrypted tar archive, uploads it to the backup bucket, and
+// returns the archive's key.
+func (s *Service) Run(ctx context.Context) (string, error) {
+	now := time.Now()
+	key := backupPrefix + now.UTC().Format("20060102T150405Z") + ".tar.gz.enc"
+
+	files, err := s.files.ListAll(ctx)
+	if err != nil {
+		return "", fmt.Errorf("list file secrets: %w", err)
+	}
+
+	texts, err := s.texts.ListAll(ctx)
+	if err != nil {
+		return "", fmt.Errorf("list text secrets: %w", err)
+	}
+
+	pr, pw := io.Pipe()
+
+	go func() {
+		pw.CloseWithError(s.writeTar(ctx, pw, manifest{CreatedAt: now, Files: files, Texts: texts}))
+	}()
+
+	epr, epw := io.Pipe()
+
+	go func() {
+		epw.CloseWithError(cryptutils.EncryptStream(epw, pr, s.keyring.PrimaryKey(), contentinfo.CompressionGzip))
+	}()
+
+	// The encrypted archive's size isn't known up front; Put streams it.
+	if err := s.backups.Put(ctx, key, epr, -1, objrepo.Tags{SecretType: "backup"}); err != nil {
+		return "", fmt.Errorf("upload backup archive: %w", err)
+	}
+
+	return key, nil
+}
+
+// writeTar writes m as manifest.json, followed by every object file/text
+// secrets in m reference, as an uncompressed tar stream to w.
+func (s *Service) writeTar(ctx context.Context, w io.Writer, m manifest) error {
+	tw := tar.NewWriter(w)
+
+	manifestJSON, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("marshal manifest: %w", err)
+	}
+
+	if err := tw.WriteHeader(&tar.Header{Name: manifestName, Size: int64(len(manifestJSON)), Mode: 0o600}); err != nil {
+		return fmt.Errorf("write manifest header: %w", err)
+	}
+
+	if _, err := tw.Write(manifestJSON); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+
+	keys := make(map[string]bool)
+	for _, f := range m.Files {
+		keys[f.Content.ObjectKey] = true
+
+		if f.Thumbnail.ObjectKey != "" {
+			keys[f.Thumbnail.ObjectKey] = true
+		}
+	}
+
+	for _, t := range m.Texts {
+		keys[t.Content.ObjectKey] = true
+	}
+
+	for key := range keys {
+		if err := s.writeObjectEntry(ctx, tw, key); err != nil {
+			return fmt.Errorf("archive object %q: %w", key, err)
+		}
+	}
+
+	return tw.Close()
+}
+
+func (s *Service) writeObjectEntry(ctx context.Context, tw *tar.Writer, key string) error {
+	obj, err := s.objects.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("get object: %w", err)
+	}
+	defer obj.Close()
+
+	data, err := io.ReadAll(obj)
+	if err != nil {
+		return fmt.Errorf("read object: %w", err)
+	}
+
+	if err := tw.WriteHeader(&tar.Header{Name: "objects/" + key, Size: int64(len(data)), Mode: 0o600}); err != nil {
+		return fmt.Errorf("write object header: %w", err)
+	}
+
+	_, err = tw.Write(data)
+
+	return err
+}
+
+// Restore decrypts and unpacks the backup archive at key, recreating every
+// file/text row it contains and copying its objects back into the primary
+// content bucket. Rows are recreated with Create, so their stored
+// CreatedAt/UpdatedAt reflect the restore time rather than the original
+// values in the archive.
+func (s *Service) Restore(ctx context.Context, key string) error {
+	obj, err := s.backups.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("get backup archive: %w", err)
+	}
+	defer obj.Close()
+
+	pr, pw := io.Pipe()
+
+	go func() {
+		pw.CloseWithError(cryptutils.DecryptStream(pw, obj, s.keyring.PrimaryKey(), contentinfo.CompressionGzip))
+	}()
+
+	tr := tar.NewReader(pr)
+
+	var m manifest
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("read tar entry: %w", err)
+		}
+
+		switch {
+		case hdr.Name == manifestName:
+			if err := json.NewDecoder(tr).Decode(&m); err != nil {
+				return fmt.Errorf("decode manifest: %w", err)
+			}
+		case strings.HasPrefix(hdr.Name, "objects/"):
+			objKey := strings.TrimPrefix(hdr.Name, "objects/")
+			if err := s.objects.Put(ctx, objKey, tr, hdr.Size, objrepo.Tags{}); err != nil {
+				return fmt.Errorf("restore object %q: %w", objKey, err)
+			}
+		}
+	}
+
+	for _, f := range m.Files {
+		if err := s.files.Create(ctx, f); err != nil {
+			return fmt.Errorf("recreate file secret %q: %w", f.ID, err)
+		}
+	}
+
+	for _, t := range m.Texts {
+		if err := s.texts.Create(ctx, t); err != nil {
+			return fmt.Errorf("recreate text secret %q: %w", t.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// Prune deletes every backup archive older than retention, keeping at least
+// the single most recent one regardless of age.
+func (s *Service) Prune(ctx context.Context, retention time.Duration) (int, error) {
+	objects, err := s.backups.List(ctx, backupPrefix)
+	if err != nil {
+		return 0, fmt.Errorf("list backups: %w", err)
+	}
+
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+
+	if len(objects) == 0 {
+		return 0, nil
+	}
+
+	// Keys sort lexically by their embedded timestamp, so the newest
+	// backup is always last; never prune it.
+	objects = objects[:len(objects)-1]
+
+	cutoff := time.Now().Add(-retention)
+
+	deleted := 0
+
+	for _, o := range objects {
+		ts, err := time.Parse("20060102T150405Z", backupTimestamp(o.Key))
+		if err != nil {
+			continue
+		}
+
+		if ts.Before(cutoff) {
+			if err := s.backups.Delete(ctx, o.Key); err != nil {
+				return deleted, fmt.Errorf("delete backup %q: %w", o.Key, err)
+			}
+
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+func backupTimestamp(key string) string {
+	name := strings.TrimPrefix(key, backupPrefix)
+
+	return strings.TrimSuffix(name, ".tar.gz.enc")
+}
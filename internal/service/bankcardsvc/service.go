@@ -0,0 +1,178 @@
+// Package bankcardsvc implements the bank card secret operations that need
+// server-side logic beyond plain storage: ownership enforcement and
+// mask/reveal decryption. Bank cards otherwise have no CRUD service layer
+// yet (see internal/domain/bankcard); this package only covers the
+// mask/reveal split, not full create/update/delete.
+package bankcardsvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/andymarkow/gophkeeper/internal/cryptutils"
+	"github.com/andymarkow/gophkeeper/internal/domain/acl"
+	"github.com/andymarkow/gophkeeper/internal/domain/bankcard"
+	"github.com/andymarkow/gophkeeper/internal/domain/metadata"
+	"github.com/andymarkow/gophkeeper/internal/repository/bankcardrepo"
+)
+
+// ErrAccessDenied is returned when the caller is neither the secret's owner
+// nor granted access via its ACL.
+var ErrAccessDenied = errors.New("access denied")
+
+// Service implements bank card secret mask/reveal.
+type Service struct {
+	storage bankcardrepo.Storage
+	keyring *cryptutils.Keyring
+}
+
+// NewService returns a Service backed by storage, decrypting with keyring.
+func NewService(storage bankcardrepo.Storage, keyring *cryptutils.Keyring) *Service {
+	return &Service{storage: storage, keyring: keyring}
+}
+
+// Get returns the secret with id if userID is its owner or has been granted
+// read access via its ACL.
+func (s *Service) Get(ctx context.Context, userID, id string) (*bankcard.Secret, error) {
+	sec, err := s.storage.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get bank card secret: %w", err)
+	}
+
+	if !canAccess(sec, userID, acl.PermissionRead) {
+		return nil, ErrAccessDenied
+	}
+
+	return sec, nil
+}
+
+// Decrypted is a bank card secret with every encrypted field decrypted.
+type Decrypted struct {
+	*bankcard.Secret
+	Number     string
+	Holder     string
+	ExpiryDate string
+	CVV        string
+}
+
+// Masked is a bank card secret with every encrypted field decrypted and
+// Number and CVV masked, returned by MaskedGet so a single-secret GET never puts plaintext
+// card data in a response, log line or cache; callers needing the real
+// values call RevealGet instead.
+type Masked struct {
+	*bankcard.Secret
+	Number     string
+	Holder     string
+	ExpiryDate string
+	CVV        string
+}
+
+// MaskedGet returns the secret with id, with Number and CVV masked and
+// Holder/ExpiryDate left as-is, if userID is its owner or has been granted
+// read access via its ACL.
+func (s *Service) MaskedGet(ctx context.Context, userID, id string) (*Masked, error) {
+	sec, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := s.decryptFields(sec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Masked{
+		Secret: sec, Number: maskValue(d.Number), Holder: d.Holder,
+		ExpiryDate: d.ExpiryDate, CVV: strings.Repeat("*", len(d.CVV)),
+	}, nil
+}
+
+// RevealGet returns the secret with id with every field decrypted in full,
+// if userID is its owner or has been granted read access via its ACL.
+// Unlike MaskedGet, callers are expected to rate limit and audit log every
+// call to this method, since its result is the plaintext card itself.
+func (s *Service) RevealGet(ctx context.Context, userID, id string) (*Decrypted, error) {
+	sec, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.decryptFields(sec)
+}
+
+func (s *Service) decryptFields(sec *bankcard.Secret) (*Decrypted, error) {
+	key, err := s.keyring.Key(sec.KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("select decryption key: %w", err)
+	}
+
+	number, err := cryptutils.Decrypt(sec.Number, key)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt number: %w", err)
+	}
+
+	holder, err := cryptutils.Decrypt(sec.Holder, key)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt holder: %w", err)
+	}
+
+	expiry, err := cryptutils.Decrypt(sec.ExpiryDate, key)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt expiry date: %w", err)
+	}
+
+	cvv, err := cryptutils.Decrypt(sec.CVV, key)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt cvv: %w", err)
+	}
+
+	return &Decrypted{
+		Secret: sec, Number: string(number), Holder: string(holder),
+		ExpiryDate: string(expiry), CVV: string(cvv),
+	}, nil
+}
+
+// maskValue replaces all but the last 4 characters of v with "*", so a
+// client can recognize a value without it being exposed in full. Values of
+// 4 characters or fewer are masked entirely.
+func maskValue(v string) string {
+	const visible = 4
+
+	if len(v) <= visible {
+		return strings.Repeat("*", len(v))
+	}
+
+	return strings.Repeat("*", len(v)-visible) + v[len(v)-visible:]
+}
+
+// SetHighSecurity marks or clears the secret's step-up gate (see
+// metadata.MarkHighSecurity), if userID is its owner or has been granted
+// write access via its ACL.
+func (s *Service) SetHighSecurity(ctx context.Context, userID, id string, enabled bool) error {
+	sec, err := s.storage.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get bank card secret: %w", err)
+	}
+
+	if !canAccess(sec, userID, acl.PermissionWrite) {
+		return ErrAccessDenied
+	}
+
+	metadata.MarkHighSecurity(&sec.Metadata, enabled)
+
+	if err := s.storage.Update(ctx, sec); err != nil {
+		return fmt.Errorf("update bank card secret: %w", err)
+	}
+
+	return nil
+}
+
+func canAccess(sec *bankcard.Secret, userID string, perm acl.Permission) bool {
+	if sec.UserID == userID {
+		return true
+	}
+
+	return sec.ACL.Allows(acl.UserPrincipal(userID), perm)
+}
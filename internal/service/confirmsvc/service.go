@@ -0,0 +1,81 @@
+// Package confirmsvc implements the prepare/confirm flow destructive admin
+// operations use to require a fresh, single-use ticket before running, so
+// an accidental or replayed request can't repeat them.
+package confirmsvc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/confirmation"
+	"github.com/andymarkow/gophkeeper/internal/repository/confirmationrepo"
+)
+
+// defaultTTL is how long a prepared ticket stays valid if the Service was
+// constructed with ttl <= 0.
+const defaultTTL = 5 * time.Minute
+
+// Service issues and consumes one-time confirmation tickets.
+type Service struct {
+	tickets confirmationrepo.Storage
+	ttl     time.Duration
+}
+
+// NewService returns a Service backed by tickets, issuing tickets valid for
+// ttl (defaultTTL if ttl <= 0).
+func NewService(tickets confirmationrepo.Storage, ttl time.Duration) *Service {
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+
+	return &Service{tickets: tickets, ttl: ttl}
+}
+
+// Prepare issues a one-time token for action against target, to be passed
+// back to Confirm by the guarded operation. actorID identifies who
+// requested it, for audit purposes.
+func (s *Service) Prepare(ctx context.Context, actorID, action, target string) (string, error) {
+	token, err := randomToken()
+	if err != nil {
+		return "", fmt.Errorf("generate confirmation token: %w", err)
+	}
+
+	ticket := &confirmation.Ticket{
+		ID:        token,
+		Action:    action,
+		Target:    target,
+		IssuedBy:  actorID,
+		ExpiresAt: time.Now().Add(s.ttl),
+	}
+
+	if err := s.tickets.Create(ctx, ticket); err != nil {
+		return "", fmt.Errorf("create confirmation ticket: %w", err)
+	}
+
+	return token, nil
+}
+
+// Confirm consumes token for action against target. It returns
+// confirmationrepo.ErrInvalidTicket if token doesn't exist, doesn't match
+// action/target, has expired, or was already used — the four cases are
+// deliberately indistinguishable to a caller.
+func (s *Service) Confirm(ctx context.Context, token, action, target string) error {
+	if err := s.tickets.Consume(ctx, token, action, target, time.Now()); err != nil {
+		return fmt.Errorf("consume confirmation ticket: %w", err)
+	}
+
+	return nil
+}
+
+// randomToken returns a random 24-byte hex-encoded one-time token.
+func randomToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate id: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
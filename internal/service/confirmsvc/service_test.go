@@ -0,0 +1,59 @@
+package confirmsvc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/repository/confirmationrepo"
+	"github.com/andymarkow/gophkeeper/internal/service/confirmsvc"
+)
+
+func TestConfirm_RejectsReusedTicket(t *testing.T) {
+	svc := confirmsvc.NewService(confirmationrepo.NewMemStorage(), time.Minute)
+
+	token, err := svc.Prepare(context.Background(), "admin-1", "delete-account", "user-42")
+	if err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+
+	if err := svc.Confirm(context.Background(), token, "delete-account", "user-42"); err != nil {
+		t.Fatalf("Confirm() error = %v, want nil", err)
+	}
+
+	err = svc.Confirm(context.Background(), token, "delete-account", "user-42")
+	if !errors.Is(err, confirmationrepo.ErrInvalidTicket) {
+		t.Fatalf("Confirm() error = %v, want %v", err, confirmationrepo.ErrInvalidTicket)
+	}
+}
+
+func TestConfirm_RejectsExpiredTicket(t *testing.T) {
+	svc := confirmsvc.NewService(confirmationrepo.NewMemStorage(), time.Millisecond)
+
+	token, err := svc.Prepare(context.Background(), "admin-1", "delete-account", "user-42")
+	if err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	err = svc.Confirm(context.Background(), token, "delete-account", "user-42")
+	if !errors.Is(err, confirmationrepo.ErrInvalidTicket) {
+		t.Fatalf("Confirm() error = %v, want %v", err, confirmationrepo.ErrInvalidTicket)
+	}
+}
+
+func TestConfirm_RejectsMismatchedTarget(t *testing.T) {
+	svc := confirmsvc.NewService(confirmationrepo.NewMemStorage(), time.Minute)
+
+	token, err := svc.Prepare(context.Background(), "admin-1", "delete-account", "user-42")
+	if err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+
+	err = svc.Confirm(context.Background(), token, "delete-account", "user-99")
+	if !errors.Is(err, confirmationrepo.ErrInvalidTicket) {
+		t.Fatalf("Confirm() error = %v, want %v", err, confirmationrepo.ErrInvalidTicket)
+	}
+}
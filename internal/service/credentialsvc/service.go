@@ -0,0 +1,382 @@
+// Package credentialsvc implements credential secret CRUD, centrally
+// enforcing ownership and the per-secret ACL.
+package credentialsvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/andymarkow/gophkeeper/internal/cryptutils"
+	"github.com/andymarkow/gophkeeper/internal/domain/acl"
+	"github.com/andymarkow/gophkeeper/internal/domain/credential"
+	"github.com/andymarkow/gophkeeper/internal/domain/metadata"
+	"github.com/andymarkow/gophkeeper/internal/repository/credrepo"
+)
+
+// ErrAccessDenied is returned when the caller is neither the secret's owner
+// nor granted access via its ACL.
+var ErrAccessDenied = errors.New("access denied")
+
+// ErrPageSizeExceeded is returned by ListWithData when the number of
+// secrets owned by a user exceeds the caller-supplied limit, to force
+// clients rendering a full vault to page instead of decrypting everything
+// in one unbounded response.
+var ErrPageSizeExceeded = errors.New("page size exceeded")
+
+// ErrBulkTooLarge is returned by BulkCreate when more than BulkMaxItems
+// secrets are submitted in one call.
+var ErrBulkTooLarge = errors.New("bulk request exceeds the maximum batch size")
+
+// ErrSecretLimitExceeded is returned by Create and BulkCreate when the new
+// secrets would take userID past maxSecrets credential secrets.
+var ErrSecretLimitExceeded = errors.New("credential secret limit exceeded for this account")
+
+// BulkMaxItems is the largest batch BulkCreate accepts in one call.
+const BulkMaxItems = 500
+
+// BulkResult reports the outcome of one secret within a BulkCreate call, at
+// its position in the submitted slice.
+type BulkResult struct {
+	Index int
+	ID    string
+	Error string
+}
+
+// Service implements credential secret operations.
+type Service struct {
+	storage    credrepo.Storage
+	keyring    *cryptutils.Keyring
+	maxSecrets int
+}
+
+// NewService returns a Service backed by storage, decrypting
+// ListWithData's results with keyring. maxSecrets caps how many credential
+// secrets a single user may own; 0 disables the cap.
+func NewService(storage credrepo.Storage, keyring *cryptutils.Keyring, maxSecrets int) *Service {
+	return &Service{storage: storage, keyring: keyring, maxSecrets: maxSecrets}
+}
+
+// EncryptFields encrypts login and password under the keyring's primary
+// key, returning ciphertexts, the key ID they were encrypted under, and a
+// blind index of login, ready to assign to a Secret's Login, Password,
+// KeyID and LoginIndex fields.
+func (s *Service) EncryptFields(login, password string) (loginCT, passwordCT []byte, keyID, loginIndex string, err error) {
+	loginCT, err = cryptutils.Encrypt([]byte(login), s.keyring.PrimaryKey())
+	if err != nil {
+		return nil, nil, "", "", fmt.Errorf("encrypt login: %w", err)
+	}
+
+	passwordCT, err = cryptutils.Encrypt([]byte(password), s.keyring.PrimaryKey())
+	if err != nil {
+		return nil, nil, "", "", fmt.Errorf("encrypt password: %w", err)
+	}
+
+	loginIndex = cryptutils.BlindIndex(cryptutils.NormalizeLogin(login), s.keyring.PrimaryKey())
+
+	return loginCT, passwordCT, s.keyring.PrimaryKeyID(), loginIndex, nil
+}
+
+// FindByLogin returns userID's secret whose login matches login exactly
+// (after normalization), via LoginIndex, without decrypting userID's other
+// secrets to compare. It returns credrepo.ErrNotFound if none matches.
+func (s *Service) FindByLogin(ctx context.Context, userID, login string) (*credential.Secret, error) {
+	index := cryptutils.BlindIndex(cryptutils.NormalizeLogin(login), s.keyring.PrimaryKey())
+
+	sec, err := s.storage.GetByLoginIndex(ctx, userID, index)
+	if err != nil {
+		return nil, fmt.Errorf("get credential secret by login index: %w", err)
+	}
+
+	return sec, nil
+}
+
+// Create stores a new secret owned by userID, failing with
+// ErrSecretLimitExceeded if userID already owns the configured maximum.
+func (s *Service) Create(ctx context.Context, userID string, sec *credential.Secret) error {
+	if err := s.checkLimit(ctx, userID, 1); err != nil {
+		return err
+	}
+
+	sec.UserID = userID
+
+	if err := s.storage.Create(ctx, sec); err != nil {
+		return fmt.Errorf("create credential secret: %w", err)
+	}
+
+	return nil
+}
+
+// checkLimit returns ErrSecretLimitExceeded if userID's existing credential
+// count plus additional would exceed s.maxSecrets. A maxSecrets of 0
+// disables the check.
+func (s *Service) checkLimit(ctx context.Context, userID string, additional int) error {
+	if s.maxSecrets <= 0 {
+		return nil
+	}
+
+	existing, err := s.storage.List(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("list credential secrets: %w", err)
+	}
+
+	if len(existing)+additional > s.maxSecrets {
+		return ErrSecretLimitExceeded
+	}
+
+	return nil
+}
+
+// BulkCreate stores secrets as a single batch via the repository's
+// CreateMany, instead of one round trip per secret, for fast vault imports.
+// Each secret's own result is reported at its original index so the caller
+// can tell which of a large import succeeded; a secret with an empty Name
+// is rejected individually without failing the rest of the batch.
+func (s *Service) BulkCreate(ctx context.Context, userID string, secrets []*credential.Secret) ([]BulkResult, error) {
+	if len(secrets) > BulkMaxItems {
+		return nil, ErrBulkTooLarge
+	}
+
+	if err := s.checkLimit(ctx, userID, len(secrets)); err != nil {
+		return nil, err
+	}
+
+	results := make([]BulkResult, len(secrets))
+	valid := make([]*credential.Secret, 0, len(secrets))
+	validIdx := make([]int, 0, len(secrets))
+
+	for i, sec := range secrets {
+		if sec.Name == "" {
+			results[i] = BulkResult{Index: i, Error: "name is required"}
+
+			continue
+		}
+
+		sec.UserID = userID
+		valid = append(valid, sec)
+		validIdx = append(validIdx, i)
+	}
+
+	if len(valid) > 0 {
+		if err := s.storage.CreateMany(ctx, valid); err != nil {
+			return nil, fmt.Errorf("create credential secrets: %w", err)
+		}
+	}
+
+	for j, sec := range valid {
+		results[validIdx[j]] = BulkResult{Index: validIdx[j], ID: sec.ID}
+	}
+
+	return results, nil
+}
+
+// Get returns the secret with id if userID is its owner or has been granted
+// read access via its ACL.
+func (s *Service) Get(ctx context.Context, userID, id string) (*credential.Secret, error) {
+	sec, err := s.storage.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get credential secret: %w", err)
+	}
+
+	if !canAccess(sec, userID, acl.PermissionRead) {
+		return nil, ErrAccessDenied
+	}
+
+	return sec, nil
+}
+
+// Update persists changes to sec if userID is its owner or has been granted
+// write access via its ACL.
+func (s *Service) Update(ctx context.Context, userID string, sec *credential.Secret) error {
+	existing, err := s.storage.Get(ctx, sec.ID)
+	if err != nil {
+		return fmt.Errorf("get credential secret: %w", err)
+	}
+
+	if !canAccess(existing, userID, acl.PermissionWrite) {
+		return ErrAccessDenied
+	}
+
+	sec.UserID = existing.UserID
+
+	if err := s.storage.Update(ctx, sec); err != nil {
+		return fmt.Errorf("update credential secret: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the secret with id if userID is its owner. ACL grants do
+// not extend to deletion, only the owner can delete.
+func (s *Service) Delete(ctx context.Context, userID, id string) error {
+	existing, err := s.storage.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get credential secret: %w", err)
+	}
+
+	if existing.UserID != userID {
+		return ErrAccessDenied
+	}
+
+	if err := s.storage.Delete(ctx, id); err != nil {
+		return fmt.Errorf("delete credential secret: %w", err)
+	}
+
+	return nil
+}
+
+// SetHighSecurity marks or clears the secret's step-up gate (see
+// metadata.MarkHighSecurity), if userID is its owner or has been granted
+// write access via its ACL.
+func (s *Service) SetHighSecurity(ctx context.Context, userID, id string, enabled bool) error {
+	sec, err := s.storage.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get credential secret: %w", err)
+	}
+
+	if !canAccess(sec, userID, acl.PermissionWrite) {
+		return ErrAccessDenied
+	}
+
+	metadata.MarkHighSecurity(&sec.Metadata, enabled)
+
+	if err := s.storage.Update(ctx, sec); err != nil {
+		return fmt.Errorf("update credential secret: %w", err)
+	}
+
+	return nil
+}
+
+// List returns every secret owned by userID. It does not include secrets
+// merely shared with userID via ACL.
+func (s *Service) List(ctx context.Context, userID string) ([]*credential.Secret, error) {
+	secrets, err := s.storage.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list credential secrets: %w", err)
+	}
+
+	return secrets, nil
+}
+
+// Decrypted is a credential secret with Login and Password decrypted,
+// returned by ListWithData so clients rendering a full vault don't need one
+// GET per secret.
+type Decrypted struct {
+	*credential.Secret
+	Login    string
+	Password string
+}
+
+// ListWithData returns every secret owned by userID with Login and Password
+// decrypted, failing with ErrPageSizeExceeded if that would exceed
+// maxPageSize, so a large vault can't be decrypted in one unbounded
+// response.
+func (s *Service) ListWithData(ctx context.Context, userID string, maxPageSize int) ([]*Decrypted, error) {
+	secrets, err := s.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(secrets) > maxPageSize {
+		return nil, ErrPageSizeExceeded
+	}
+
+	out := make([]*Decrypted, 0, len(secrets))
+
+	for _, sec := range secrets {
+		login, password, err := s.decryptFields(sec)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, &Decrypted{Secret: sec, Login: login, Password: password})
+	}
+
+	return out, nil
+}
+
+// decryptFields decrypts sec's Login and Password under its KeyID.
+func (s *Service) decryptFields(sec *credential.Secret) (login, password string, err error) {
+	key, err := s.keyring.Key(sec.KeyID)
+	if err != nil {
+		return "", "", fmt.Errorf("select decryption key: %w", err)
+	}
+
+	loginPT, err := cryptutils.Decrypt(sec.Login, key)
+	if err != nil {
+		return "", "", fmt.Errorf("decrypt login: %w", err)
+	}
+
+	passwordPT, err := cryptutils.Decrypt(sec.Password, key)
+	if err != nil {
+		return "", "", fmt.Errorf("decrypt password: %w", err)
+	}
+
+	return string(loginPT), string(passwordPT), nil
+}
+
+// Masked is a credential secret with Login and Password decrypted and
+// masked, returned by MaskedGet so a single-secret GET never puts plaintext
+// in a response, log line or cache; callers needing the real values call
+// RevealGet instead.
+type Masked struct {
+	*credential.Secret
+	Login    string
+	Password string
+}
+
+// MaskedGet returns the secret with id, masking its Login and Password, if
+// userID is its owner or has been granted read access via its ACL.
+func (s *Service) MaskedGet(ctx context.Context, userID, id string) (*Masked, error) {
+	sec, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	login, password, err := s.decryptFields(sec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Masked{Secret: sec, Login: maskValue(login), Password: maskValue(password)}, nil
+}
+
+// RevealGet returns the secret with id with Login and Password decrypted in
+// full, if userID is its owner or has been granted read access via its ACL.
+// Unlike MaskedGet, callers are expected to rate limit and audit log every
+// call to this method, since its result is the plaintext credential itself.
+func (s *Service) RevealGet(ctx context.Context, userID, id string) (*Decrypted, error) {
+	sec, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	login, password, err := s.decryptFields(sec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Decrypted{Secret: sec, Login: login, Password: password}, nil
+}
+
+// maskValue replaces all but the last 4 characters of v with "*", so a
+// client can recognize a value without it being exposed in full. Values of
+// 4 characters or fewer are masked entirely.
+func maskValue(v string) string {
+	const visible = 4
+
+	if len(v) <= visible {
+		return strings.Repeat("*", len(v))
+	}
+
+	return strings.Repeat("*", len(v)-visible) + v[len(v)-visible:]
+}
+
+func canAccess(sec *credential.Secret, userID string, perm acl.Permission) bool {
+	if sec.UserID == userID {
+		return true
+	}
+
+	return sec.ACL.Allows(acl.UserPrincipal(userID), perm)
+}
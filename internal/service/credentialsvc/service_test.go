@@ -0,0 +1,95 @@
+package credentialsvc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/andymarkow/gophkeeper/internal/cryptutils"
+	"github.com/andymarkow/gophkeeper/internal/domain/credential"
+	"github.com/andymarkow/gophkeeper/internal/repository/credrepo"
+	"github.com/andymarkow/gophkeeper/internal/service/credentialsvc"
+)
+
+func newTestService(t *testing.T) *credentialsvc.Service {
+	t.Helper()
+
+	keyring, err := cryptutils.NewKeyring("k1", map[string][]byte{
+		"k1": []byte("01234567890123456789012345678901"),
+	})
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+
+	return credentialsvc.NewService(credrepo.NewMemStorage(), keyring, 0)
+}
+
+func TestFindByLogin_MatchesWithoutDecryptingOtherSecrets(t *testing.T) {
+	svc := newTestService(t)
+
+	loginCT, passwordCT, keyID, loginIndex, err := svc.EncryptFields("alice@example.com", "hunter2")
+	if err != nil {
+		t.Fatalf("EncryptFields() error = %v", err)
+	}
+
+	sec := &credential.Secret{
+		ID: "cred-1", Login: loginCT, Password: passwordCT, KeyID: keyID, LoginIndex: loginIndex,
+	}
+	if err := svc.Create(context.Background(), "user-1", sec); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	got, err := svc.FindByLogin(context.Background(), "user-1", "alice@example.com")
+	if err != nil {
+		t.Fatalf("FindByLogin() error = %v", err)
+	}
+
+	if got.ID != sec.ID {
+		t.Fatalf("FindByLogin() returned secret %q, want %q", got.ID, sec.ID)
+	}
+}
+
+func TestFindByLogin_ScopedToOwner(t *testing.T) {
+	svc := newTestService(t)
+
+	loginCT, passwordCT, keyID, loginIndex, err := svc.EncryptFields("alice@example.com", "hunter2")
+	if err != nil {
+		t.Fatalf("EncryptFields() error = %v", err)
+	}
+
+	sec := &credential.Secret{
+		ID: "cred-1", Login: loginCT, Password: passwordCT, KeyID: keyID, LoginIndex: loginIndex,
+	}
+	if err := svc.Create(context.Background(), "user-1", sec); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if _, err := svc.FindByLogin(context.Background(), "user-2", "alice@example.com"); !errors.Is(err, credrepo.ErrNotFound) {
+		t.Fatalf("FindByLogin() error = %v, want %v", err, credrepo.ErrNotFound)
+	}
+}
+
+func TestFindByLogin_NormalizesLoginBeforeIndexing(t *testing.T) {
+	svc := newTestService(t)
+
+	loginCT, passwordCT, keyID, loginIndex, err := svc.EncryptFields("Alice@Example.com", "hunter2")
+	if err != nil {
+		t.Fatalf("EncryptFields() error = %v", err)
+	}
+
+	sec := &credential.Secret{
+		ID: "cred-1", Login: loginCT, Password: passwordCT, KeyID: keyID, LoginIndex: loginIndex,
+	}
+	if err := svc.Create(context.Background(), "user-1", sec); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	got, err := svc.FindByLogin(context.Background(), "user-1", "alice@example.com")
+	if err != nil {
+		t.Fatalf("FindByLogin() error = %v", err)
+	}
+
+	if got.ID != sec.ID {
+		t.Fatalf("FindByLogin() returned secret %q, want %q", got.ID, sec.ID)
+	}
+}
@@ -0,0 +1,205 @@
+// Package expirysvc scans secrets with an expiration date — bank cards, API
+// tokens; certificate secrets will plug in the same way once that secret
+// type exists — and notifies the owner when one is about to expire.
+package expirysvc
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/cryptutils"
+	"github.com/andymarkow/gophkeeper/internal/domain/bankcard"
+	"github.com/andymarkow/gophkeeper/internal/repository/apitokenrepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/bankcardrepo"
+	"github.com/andymarkow/gophkeeper/internal/service/notifysvc"
+)
+
+// Service notifies users N days before a secret of theirs expires.
+type Service struct {
+	bankcards  bankcardrepo.Storage
+	apitokens  apitokenrepo.Storage
+	keyring    *cryptutils.Keyring
+	notify     *notifysvc.Service
+	warnBefore time.Duration
+}
+
+// NewService returns a Service that warns warnBefore ahead of expiration.
+func NewService(bankcards bankcardrepo.Storage, apitokens apitokenrepo.Storage, keyring *cryptutils.Keyring,
+	notify *notifysvc.Service, warnBefore time.Duration,
+) *Service {
+	return &Service{bankcards: bankcards, apitokens: apitokens, keyring: keyring, notify: notify, warnBefore: warnBefore}
+}
+
+// NotifyBankCardsExpiringSoon scans every bank card, decrypts its expiry
+// date, and notifies the owner once if the card expires within warnBefore
+// and they haven't already been warned about this expiry.
+func (s *Service) NotifyBankCardsExpiringSoon(ctx context.Context) (int, error) {
+	cards, err := s.bankcards.ListAll(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list bank cards: %w", err)
+	}
+
+	now := time.Now()
+	notified := 0
+
+	for _, card := range cards {
+		if card.ExpiryNotifiedAt != nil {
+			continue
+		}
+
+		expiresAt, err := s.decryptExpiry(card)
+		if err != nil {
+			continue
+		}
+
+		if expiresAt.Before(now) || expiresAt.Sub(now) > s.warnBefore {
+			continue
+		}
+
+		data := struct{ SecretType, Name, ExpiresAt string }{
+			SecretType: "bank card",
+			Name:       card.Name,
+			ExpiresAt:  expiresAt.Format("2006-01"),
+		}
+
+		if err := s.notify.Notify(ctx, card.UserID, notifysvc.KindExpiryWarning, data); err != nil {
+			return notified, fmt.Errorf("notify user %q: %w", card.UserID, err)
+		}
+
+		card.ExpiryNotifiedAt = &now
+
+		if err := s.bankcards.Update(ctx, card); err != nil {
+			return notified, fmt.Errorf("mark card %q notified: %w", card.ID, err)
+		}
+
+		notified++
+	}
+
+	return notified, nil
+}
+
+// NotifyAPITokensExpiringSoon scans every API token with a known expiry and
+// notifies the owner once if it expires within warnBefore and they haven't
+// already been warned about this expiry.
+func (s *Service) NotifyAPITokensExpiringSoon(ctx context.Context) (int, error) {
+	tokens, err := s.apitokens.ListAll(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list api tokens: %w", err)
+	}
+
+	now := time.Now()
+	notified := 0
+
+	for _, tok := range tokens {
+		if tok.ExpiryNotifiedAt != nil || tok.ExpiresAt == nil {
+			continue
+		}
+
+		if tok.ExpiresAt.Before(now) || tok.ExpiresAt.Sub(now) > s.warnBefore {
+			continue
+		}
+
+		data := struct{ SecretType, Name, ExpiresAt string }{
+			SecretType: "API token",
+			Name:       tok.Name,
+			ExpiresAt:  tok.ExpiresAt.Format("2006-01-02"),
+		}
+
+		if err := s.notify.Notify(ctx, tok.UserID, notifysvc.KindExpiryWarning, data); err != nil {
+			return notified, fmt.Errorf("notify user %q: %w", tok.UserID, err)
+		}
+
+		tok.ExpiryNotifiedAt = &now
+
+		if err := s.apitokens.Update(ctx, tok); err != nil {
+			return notified, fmt.Errorf("mark token %q notified: %w", tok.ID, err)
+		}
+
+		notified++
+	}
+
+	return notified, nil
+}
+
+// ListExpiringWithin returns userID's bank cards expiring within the given
+// window. No HTTP endpoint calls it: the bank card routes serve get, reveal
+// and export, and there is no bank card list endpoint to filter.
+func (s *Service) ListExpiringWithin(ctx context.Context, userID string, within time.Duration) ([]*bankcard.Secret, error) {
+	cards, err := s.bankcards.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list bank cards: %w", err)
+	}
+
+	now := time.Now()
+
+	out := make([]*bankcard.Secret, 0, len(cards))
+
+	for _, card := range cards {
+		expiresAt, err := s.decryptExpiry(card)
+		if err != nil {
+			continue
+		}
+
+		if !expiresAt.Before(now) && expiresAt.Sub(now) <= within {
+			out = append(out, card)
+		}
+	}
+
+	return out, nil
+}
+
+// decryptExpiry decrypts card's expiry date field and parses it, expecting
+// the MM/YY or MM/YYYY format used on physical cards. The card is
+// considered to expire at the end of that month.
+func (s *Service) decryptExpiry(card *bankcard.Secret) (time.Time, error) {
+	return DecryptBankCardExpiry(card, s.keyring)
+}
+
+// DecryptBankCardExpiry decrypts and parses card's expiry date field,
+// expecting the MM/YY or MM/YYYY format used on physical cards. The card is
+// considered to expire at the end of that month. Exported so other services
+// needing a card's expiry (e.g. remindersvc) share this logic instead of
+// duplicating the keyring lookup and parsing.
+func DecryptBankCardExpiry(card *bankcard.Secret, keyring *cryptutils.Keyring) (time.Time, error) {
+	key, err := keyring.Key(card.KeyID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("select decryption key: %w", err)
+	}
+
+	plaintext, err := cryptutils.Decrypt(card.ExpiryDate, key)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("decrypt expiry date: %w", err)
+	}
+
+	return parseCardExpiry(string(plaintext))
+}
+
+// parseCardExpiry parses MM/YY or MM/YYYY into the last instant of that
+// month, the convention used for bank card expiration.
+func parseCardExpiry(s string) (time.Time, error) {
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 {
+		return time.Time{}, fmt.Errorf("invalid expiry date %q", s)
+	}
+
+	month, err := strconv.Atoi(parts[0])
+	if err != nil || month < 1 || month > 12 {
+		return time.Time{}, fmt.Errorf("invalid expiry month %q", parts[0])
+	}
+
+	year, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid expiry year %q", parts[1])
+	}
+
+	if year < 100 {
+		year += 2000
+	}
+
+	// The first instant of the following month, minus one nanosecond, is
+	// the last instant of the expiry month.
+	return time.Date(year, time.Month(month)+1, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond), nil
+}
@@ -0,0 +1,63 @@
+package exportsvc
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// ListAll queries every registered source concurrently and returns their
+// Records combined into one slice, with Type stamped on each. All queries
+// share ctx, so a deadline or cancellation on ctx bounds the whole call
+// rather than each source individually, and the first source to fail
+// cancels the others. Order across types is unspecified; within a type it
+// matches the order the source returned.
+func (e *NDJSONExporter) ListAll(ctx context.Context, userID string, includeData bool) ([]Record, error) {
+	g, ctx := errgroup.WithContext(ctx)
+
+	type listing struct {
+		typeName string
+		records  []Record
+	}
+
+	listings := make([]listing, len(e.sources))
+
+	i := 0
+
+	for typeName, source := range e.sources {
+		idx, typeName, source := i, typeName, source
+		i++
+
+		g.Go(func() error {
+			records, err := source(ctx, userID, includeData)
+			if err != nil {
+				return fmt.Errorf("list %s secrets: %w", typeName, err)
+			}
+
+			for j := range records {
+				records[j].Type = typeName
+			}
+
+			listings[idx] = listing{typeName: typeName, records: records}
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	var total int
+	for _, l := range listings {
+		total += len(l.records)
+	}
+
+	out := make([]Record, 0, total)
+	for _, l := range listings {
+		out = append(out, l.records...)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,108 @@
+package exportsvc_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/service/exportsvc"
+)
+
+// sleepySource returns a SourceFunc that sleeps for delay before returning
+// n records, simulating a slow repository query.
+func sleepySource(typeName string, n int, delay time.Duration) exportsvc.SourceFunc {
+	return func(_ context.Context, _ string, _ bool) ([]exportsvc.Record, error) {
+		time.Sleep(delay)
+
+		records := make([]exportsvc.Record, n)
+		for i := range records {
+			records[i] = exportsvc.Record{ID: fmt.Sprintf("%s-%d", typeName, i)}
+		}
+
+		return records, nil
+	}
+}
+
+func TestNDJSONExporter_ListAll(t *testing.T) {
+	exporter := exportsvc.NewNDJSONExporter(map[string]exportsvc.SourceFunc{
+		"credential": sleepySource("credential", 2, 0),
+		"bankcard":   sleepySource("bankcard", 3, 0),
+		"generic":    sleepySource("generic", 1, 0),
+	})
+
+	records, err := exporter.ListAll(context.Background(), "user-1", false)
+	if err != nil {
+		t.Fatalf("ListAll() error = %v", err)
+	}
+
+	if got, want := len(records), 6; got != want {
+		t.Fatalf("len(records) = %d, want %d", got, want)
+	}
+
+	counts := map[string]int{}
+	for _, rec := range records {
+		counts[rec.Type]++
+	}
+
+	for typeName, want := range map[string]int{"credential": 2, "bankcard": 3, "generic": 1} {
+		if counts[typeName] != want {
+			t.Errorf("counts[%q] = %d, want %d", typeName, counts[typeName], want)
+		}
+	}
+}
+
+// TestNDJSONExporter_ListAll_Concurrent asserts sources run in parallel
+// rather than sequentially: three 50ms sources should finish in well under
+// their combined 150ms if ListAll queries them concurrently.
+func TestNDJSONExporter_ListAll_Concurrent(t *testing.T) {
+	delay := 50 * time.Millisecond
+
+	exporter := exportsvc.NewNDJSONExporter(map[string]exportsvc.SourceFunc{
+		"credential": sleepySource("credential", 1, delay),
+		"bankcard":   sleepySource("bankcard", 1, delay),
+		"generic":    sleepySource("generic", 1, delay),
+	})
+
+	start := time.Now()
+
+	if _, err := exporter.ListAll(context.Background(), "user-1", false); err != nil {
+		t.Fatalf("ListAll() error = %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed >= 3*delay {
+		t.Errorf("ListAll() took %v, want well under %v (sources should run concurrently)", elapsed, 3*delay)
+	}
+}
+
+func TestNDJSONExporter_ListAll_PropagatesError(t *testing.T) {
+	wantErr := fmt.Errorf("boom")
+
+	exporter := exportsvc.NewNDJSONExporter(map[string]exportsvc.SourceFunc{
+		"credential": func(context.Context, string, bool) ([]exportsvc.Record, error) {
+			return nil, wantErr
+		},
+	})
+
+	if _, err := exporter.ListAll(context.Background(), "user-1", false); err == nil {
+		t.Fatal("ListAll() error = nil, want non-nil")
+	}
+}
+
+// BenchmarkNDJSONExporter_ListAll measures aggregate listing overhead across
+// a handful of sources of varying size.
+func BenchmarkNDJSONExporter_ListAll(b *testing.B) {
+	exporter := exportsvc.NewNDJSONExporter(map[string]exportsvc.SourceFunc{
+		"credential": sleepySource("credential", 100, 0),
+		"bankcard":   sleepySource("bankcard", 50, 0),
+		"generic":    sleepySource("generic", 200, 0),
+	})
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := exporter.ListAll(context.Background(), "user-1", false); err != nil {
+			b.Fatalf("ListAll() error = %v", err)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package exportsvc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Search returns every Record across all registered sources whose Name or
+// metadata value contains query, case-insensitively. It's a naive in-memory
+// scan: a Postgres-backed deployment should instead query a tsvector column
+// maintained on write, but no such repository is wired up in this tree, so
+// ListAll plus a substring filter is what the /secrets/search endpoint
+// actually runs against today.
+func (e *NDJSONExporter) Search(ctx context.Context, userID, query string) ([]Record, error) {
+	records, err := e.ListAll(ctx, userID, false)
+	if err != nil {
+		return nil, fmt.Errorf("list all secrets: %w", err)
+	}
+
+	if query == "" {
+		return records, nil
+	}
+
+	needle := strings.ToLower(query)
+
+	out := make([]Record, 0, len(records))
+	for _, rec := range records {
+		if matchesQuery(rec, needle) {
+			out = append(out, rec)
+		}
+	}
+
+	return out, nil
+}
+
+func matchesQuery(rec Record, needle string) bool {
+	if strings.Contains(strings.ToLower(rec.Name), needle) {
+		return true
+	}
+
+	for k, v := range rec.Metadata {
+		if strings.Contains(strings.ToLower(k), needle) || strings.Contains(strings.ToLower(v), needle) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,126 @@
+// Package exportsvc streams a user's decrypted secrets out in formats
+// meant for migrating to another tool (CSV) or for backup tooling (NDJSON,
+// see the bulk export endpoint). Export always re-reads and decrypts on
+// demand; nothing is cached.
+package exportsvc
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	"github.com/andymarkow/gophkeeper/internal/cryptutils"
+	"github.com/andymarkow/gophkeeper/internal/domain/bankcard"
+	"github.com/andymarkow/gophkeeper/internal/domain/credential"
+	"github.com/andymarkow/gophkeeper/internal/repository/bankcardrepo"
+	"github.com/andymarkow/gophkeeper/internal/service/credentialsvc"
+)
+
+// credentialLister is the subset of credentialsvc.Service export depends
+// on.
+type credentialLister interface {
+	List(ctx context.Context, userID string) ([]*credential.Secret, error)
+	ListWithData(ctx context.Context, userID string, maxPageSize int) ([]*credentialsvc.Decrypted, error)
+}
+
+// Service exports a user's secrets as CSV for migration to other password
+// managers.
+type Service struct {
+	credentials credentialLister
+	bankcards   bankcardrepo.Storage
+	keyring     *cryptutils.Keyring
+	maxPageSize int
+}
+
+// NewService returns a Service backed by credentials and bankcards,
+// decrypting bank card fields with keyring. Exports fail with
+// credentialsvc.ErrPageSizeExceeded past maxPageSize credentials, the same
+// cap ListWithData enforces.
+func NewService(credentials credentialLister, bankcards bankcardrepo.Storage, keyring *cryptutils.Keyring, maxPageSize int) *Service {
+	return &Service{credentials: credentials, bankcards: bankcards, keyring: keyring, maxPageSize: maxPageSize}
+}
+
+// ExportCredentialsCSV writes userID's credentials as CSV (name, login,
+// password) to w.
+func (s *Service) ExportCredentialsCSV(ctx context.Context, userID string, w io.Writer) error {
+	secrets, err := s.credentials.ListWithData(ctx, userID, s.maxPageSize)
+	if err != nil {
+		return fmt.Errorf("list credentials: %w", err)
+	}
+
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{"name", "login", "password"}); err != nil {
+		return fmt.Errorf("write header: %w", err)
+	}
+
+	for _, sec := range secrets {
+		if err := cw.Write([]string{sec.Name, sec.Login, sec.Password}); err != nil {
+			return fmt.Errorf("write row: %w", err)
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// ExportBankCardsCSV writes userID's bank cards as CSV (name, number,
+// holder, expiry_date, cvv) to w.
+func (s *Service) ExportBankCardsCSV(ctx context.Context, userID string, w io.Writer) error {
+	cards, err := s.bankcards.List(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("list bank cards: %w", err)
+	}
+
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{"name", "number", "holder", "expiry_date", "cvv"}); err != nil {
+		return fmt.Errorf("write header: %w", err)
+	}
+
+	for _, card := range cards {
+		row, err := s.decryptCardRow(card)
+		if err != nil {
+			return fmt.Errorf("decrypt bank card %q: %w", card.ID, err)
+		}
+
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("write row: %w", err)
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+func (s *Service) decryptCardRow(card *bankcard.Secret) ([]string, error) {
+	key, err := s.keyring.Key(card.KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("select decryption key: %w", err)
+	}
+
+	number, err := cryptutils.Decrypt(card.Number, key)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt number: %w", err)
+	}
+
+	holder, err := cryptutils.Decrypt(card.Holder, key)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt holder: %w", err)
+	}
+
+	expiry, err := cryptutils.Decrypt(card.ExpiryDate, key)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt expiry date: %w", err)
+	}
+
+	cvv, err := cryptutils.Decrypt(card.CVV, key)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt cvv: %w", err)
+	}
+
+	return []string{card.Name, string(number), string(holder), string(expiry), string(cvv)}, nil
+}
@@ -0,0 +1,166 @@
+package exportsvc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/file"
+	"github.com/andymarkow/gophkeeper/internal/domain/generic"
+	"github.com/andymarkow/gophkeeper/internal/domain/text"
+	"github.com/andymarkow/gophkeeper/internal/repository/bankcardrepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/genericrepo"
+)
+
+// genericDecrypter is the subset of genericsvc.Service the generic secret
+// source depends on.
+type genericDecrypter interface {
+	DecryptPayload(sec *generic.Secret) (map[string]any, error)
+}
+
+// textLister is the subset of textsvc.Service the text secret source
+// depends on.
+type textLister interface {
+	List(ctx context.Context, userID string) ([]*text.Secret, error)
+}
+
+// fileLister is the subset of filesvc.Service the file secret source
+// depends on.
+type fileLister interface {
+	List(ctx context.Context, userID string) ([]*file.Secret, error)
+}
+
+// CredentialSource returns a SourceFunc listing a user's credentials,
+// decrypting login/password only when includeData is true.
+func CredentialSource(credentials credentialLister, maxPageSize int) SourceFunc {
+	return func(ctx context.Context, userID string, includeData bool) ([]Record, error) {
+		if !includeData {
+			secrets, err := credentials.List(ctx, userID)
+			if err != nil {
+				return nil, err
+			}
+
+			out := make([]Record, len(secrets))
+			for i, sec := range secrets {
+				out[i] = Record{
+					ID: sec.ID, Name: sec.Name, Metadata: sec.Metadata,
+					CreatedAt: sec.CreatedAt, UpdatedAt: sec.UpdatedAt,
+				}
+			}
+
+			return out, nil
+		}
+
+		secrets, err := credentials.ListWithData(ctx, userID, maxPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		out := make([]Record, len(secrets))
+		for i, sec := range secrets {
+			out[i] = Record{
+				ID: sec.ID, Name: sec.Name, Metadata: sec.Metadata,
+				Data:      map[string]any{"login": sec.Login, "password": sec.Password},
+				CreatedAt: sec.CreatedAt, UpdatedAt: sec.UpdatedAt,
+			}
+		}
+
+		return out, nil
+	}
+}
+
+// BankCardSource returns a SourceFunc listing a user's bank cards. Data is
+// never populated, so includeData has no effect here.
+func BankCardSource(bankcards bankcardrepo.Storage) SourceFunc {
+	return func(ctx context.Context, userID string, _ bool) ([]Record, error) {
+		cards, err := bankcards.List(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		out := make([]Record, len(cards))
+		for i, card := range cards {
+			out[i] = Record{
+				ID: card.ID, Name: card.Name, Metadata: card.Metadata,
+				CreatedAt: card.CreatedAt, UpdatedAt: card.UpdatedAt,
+			}
+		}
+
+		return out, nil
+	}
+}
+
+// TextSource returns a SourceFunc listing a user's text secrets. Data is
+// never populated: the content lives in object storage and decrypting it
+// is disproportionate for an aggregate listing; fetch the secret by its own
+// endpoint for that.
+func TextSource(texts textLister) SourceFunc {
+	return func(ctx context.Context, userID string, _ bool) ([]Record, error) {
+		secrets, err := texts.List(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		out := make([]Record, len(secrets))
+		for i, sec := range secrets {
+			out[i] = Record{
+				ID: sec.ID, Name: sec.Name, Metadata: sec.Metadata,
+				CreatedAt: sec.CreatedAt, UpdatedAt: sec.UpdatedAt,
+			}
+		}
+
+		return out, nil
+	}
+}
+
+// FileSource returns a SourceFunc listing a user's file secrets. Data is
+// never populated, for the same reason as TextSource.
+func FileSource(files fileLister) SourceFunc {
+	return func(ctx context.Context, userID string, _ bool) ([]Record, error) {
+		secrets, err := files.List(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		out := make([]Record, len(secrets))
+		for i, sec := range secrets {
+			out[i] = Record{
+				ID: sec.ID, Name: sec.Name, Metadata: sec.Metadata,
+				CreatedAt: sec.CreatedAt, UpdatedAt: sec.UpdatedAt,
+			}
+		}
+
+		return out, nil
+	}
+}
+
+// GenericSource returns a SourceFunc listing a user's generic secrets,
+// decrypting each payload only when includeData is true.
+func GenericSource(secrets genericrepo.Storage, decrypter genericDecrypter) SourceFunc {
+	return func(ctx context.Context, userID string, includeData bool) ([]Record, error) {
+		list, err := secrets.List(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		out := make([]Record, len(list))
+		for i, sec := range list {
+			rec := Record{
+				ID: sec.ID, Name: sec.Name, Metadata: sec.Metadata,
+				CreatedAt: sec.CreatedAt, UpdatedAt: sec.UpdatedAt,
+			}
+
+			if includeData {
+				payload, err := decrypter.DecryptPayload(sec)
+				if err != nil {
+					return nil, fmt.Errorf("decrypt generic secret %q: %w", sec.ID, err)
+				}
+
+				rec.Data = payload
+			}
+
+			out[i] = rec
+		}
+
+		return out, nil
+	}
+}
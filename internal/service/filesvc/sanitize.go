@@ -0,0 +1,63 @@
+package filesvc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// imageExtensions are the file extensions eligible for thumbnail
+// generation on upload.
+var imageExtensions = map[string]bool{
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+	".gif":  true,
+}
+
+// isImageName reports whether name's extension is one Upload will attempt
+// to generate a thumbnail for.
+func isImageName(name string) bool {
+	return imageExtensions[strings.ToLower(path.Ext(name))]
+}
+
+// sanitizeName strips path separators, ".." segments and control characters
+// from a user-supplied file name, so it can't be used to escape the user's
+// object-storage prefix or to smuggle control bytes into a later
+// Content-Disposition header. The result is safe to use as a single path
+// segment but is not guaranteed unique; callers needing uniqueness should
+// combine it with an ID, as getObjName does.
+func sanitizeName(name string) string {
+	// path.Base collapses any "../" traversal and leading/trailing
+	// slashes down to the final segment.
+	name = path.Base(path.Clean("/" + name))
+
+	var b strings.Builder
+
+	for _, r := range name {
+		if r < 0x20 || r == 0x7f {
+			continue // strip control characters
+		}
+
+		b.WriteRune(r)
+	}
+
+	name = strings.TrimSpace(b.String())
+	if name == "" || name == "." || name == "/" {
+		name = "unnamed"
+	}
+
+	return name
+}
+
+// getObjName derives the object storage key for userID's file secret id,
+// embedding a sanitized form of name purely for operator-facing
+// readability in bucket listings; uniqueness and traversal-safety come from
+// the userID/id prefix, not from name.
+func getObjName(userID, id, name string) string {
+	sum := sha256.Sum256([]byte(name))
+
+	return fmt.Sprintf("users/%s/files/%s-%s-%s", userID, id, hex.EncodeToString(sum[:8]), sanitizeName(name))
+}
@@ -0,0 +1,842 @@
+// Package filesvc implements file secret CRUD plus upload/download of the
+// encrypted payload, centrally enforcing ownership and the per-secret ACL.
+package filesvc
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/cryptutils"
+	"github.com/andymarkow/gophkeeper/internal/domain/acl"
+	"github.com/andymarkow/gophkeeper/internal/domain/contentinfo"
+	"github.com/andymarkow/gophkeeper/internal/domain/file"
+	"github.com/andymarkow/gophkeeper/internal/domain/metadata"
+	"github.com/andymarkow/gophkeeper/internal/imgthumb"
+	"github.com/andymarkow/gophkeeper/internal/lock"
+	"github.com/andymarkow/gophkeeper/internal/objrepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/filerepo"
+)
+
+// ErrAccessDenied is returned when the caller is neither the secret's owner
+// nor granted access via its ACL.
+var ErrAccessDenied = errors.New("access denied")
+
+// ErrNoThumbnail is returned by Thumbnail when the secret has none, either
+// because it isn't an image or generation was skipped or failed at upload
+// time.
+var ErrNoThumbnail = errors.New("no thumbnail available")
+
+// ErrContentTypeNotAllowed is returned by Upload when the caller-supplied
+// content type is rejected by the service's ContentTypePolicy.
+var ErrContentTypeNotAllowed = errors.New("content type not allowed")
+
+// ErrVersionNotFound is returned by DownloadVersion when the requested
+// version index isn't (or is no longer) retained.
+var ErrVersionNotFound = errors.New("version not found")
+
+// ErrSecretLimitExceeded is returned by Upload when userID already owns
+// maxSecrets file secrets.
+var ErrSecretLimitExceeded = errors.New("file secret limit exceeded for this account")
+
+// ErrNotTrashed is returned by Restore when the secret isn't currently
+// trashed.
+var ErrNotTrashed = errors.New("file secret is not trashed")
+
+// DefaultMaxVersions is used when NewService is given a non-positive
+// maxVersions, keeping a small amount of history without an explicit
+// config value.
+const DefaultMaxVersions = 5
+
+// ContentTypePolicy restricts which content types may be uploaded as file
+// secrets, e.g. to block executables in a corporate deployment. Deny always
+// takes precedence over Allow; an empty Allow permits every type not
+// explicitly denied.
+type ContentTypePolicy struct {
+	Allow []string
+	Deny  []string
+}
+
+// Check returns ErrContentTypeNotAllowed if contentType is rejected by p.
+func (p ContentTypePolicy) Check(contentType string) error {
+	contentType = normalizeContentType(contentType)
+
+	for _, d := range p.Deny {
+		if normalizeContentType(d) == contentType {
+			return ErrContentTypeNotAllowed
+		}
+	}
+
+	if len(p.Allow) == 0 {
+		return nil
+	}
+
+	for _, a := range p.Allow {
+		if normalizeContentType(a) == contentType {
+			return nil
+		}
+	}
+
+	return ErrContentTypeNotAllowed
+}
+
+// normalizeContentType strips any parameters (e.g. "; charset=utf-8") and
+// lowercases a content type, so "Text/Plain; charset=utf-8" and
+// "text/plain" compare equal.
+func normalizeContentType(contentType string) string {
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = contentType[:i]
+	}
+
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
+
+// maxThumbnailSourceBytes caps the upload size eligible for thumbnail
+// generation, so a multi-GB "image" upload doesn't force the whole payload
+// into memory to decode it.
+const maxThumbnailSourceBytes = 20 << 20 // 20 MiB
+
+// Service implements file secret operations.
+type Service struct {
+	storage filerepo.Storage
+	objects objrepo.Storage
+	keyring *cryptutils.Keyring
+	// uploadLocks serializes re-uploads to the same secret ID, so two
+	// concurrent Replace calls can't interleave and leave mismatched
+	// salt/IV/checksum metadata.
+	uploadLocks  *lock.KeyedMutex
+	contentTypes ContentTypePolicy
+	// maxVersions caps how many displaced ContentInfo entries Replace
+	// keeps in Secret.Versions before purging the oldest.
+	maxVersions int
+	// maxSecrets caps how many file secrets a single user may own; 0
+	// disables the cap.
+	maxSecrets int
+}
+
+// NewService returns a Service backed by storage and objects, encrypting
+// uploads under keyring's primary key, rejecting uploads contentTypes
+// disallows, and retaining up to maxVersions prior versions per secret
+// after a Replace (DefaultMaxVersions if maxVersions <= 0). maxSecrets caps
+// how many file secrets a single user may own; 0 disables the cap.
+func NewService(storage filerepo.Storage, objects objrepo.Storage, keyring *cryptutils.Keyring,
+	contentTypes ContentTypePolicy, maxVersions, maxSecrets int,
+) *Service {
+	if maxVersions <= 0 {
+		maxVersions = DefaultMaxVersions
+	}
+
+	return &Service{
+		storage: storage, objects: objects, keyring: keyring,
+		uploadLocks: lock.NewKeyedMutex(), contentTypes: contentTypes,
+		maxVersions: maxVersions, maxSecrets: maxSecrets,
+	}
+}
+
+// Upload creates a new file secret owned by userID, encrypting r under the
+// keyring's primary key and storing it under an object key derived from
+// userID and a fresh secret ID, so a hostile name can neither escape the
+// user's prefix nor collide with another user's object. It fails with
+// ErrContentTypeNotAllowed if contentType is rejected by the service's
+// ContentTypePolicy, or ErrSecretLimitExceeded if userID already owns the
+// configured maximum.
+func (s *Service) Upload(ctx context.Context, userID, name, contentType string, r io.Reader, size int64) (*file.Secret, error) {
+	if err := s.contentTypes.Check(contentType); err != nil {
+		return nil, err
+	}
+
+	if s.maxSecrets > 0 {
+		existing, err := s.storage.List(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("list file secrets: %w", err)
+		}
+
+		if len(existing) >= s.maxSecrets {
+			return nil, ErrSecretLimitExceeded
+		}
+	}
+
+	id, err := randomID()
+	if err != nil {
+		return nil, fmt.Errorf("generate id: %w", err)
+	}
+
+	hasher := sha256.New()
+
+	var thumbBuf *bytes.Buffer
+
+	src := io.Reader(io.TeeReader(r, hasher))
+	if isImageName(name) && size > 0 && size <= maxThumbnailSourceBytes {
+		thumbBuf = &bytes.Buffer{}
+		src = io.TeeReader(src, thumbBuf)
+	}
+
+	pr, pw := io.Pipe()
+
+	go func() {
+		pw.CloseWithError(cryptutils.EncryptStream(pw, src, s.keyring.PrimaryKey(), contentinfo.CompressionNone))
+	}()
+
+	objKey := getObjName(userID, id, name)
+	encryptedSize := size + cryptutils.IVSize
+
+	if err := s.objects.Put(ctx, objKey, pr, encryptedSize, objrepo.Tags{UserID: userID, SecretID: id, SecretType: "file"}); err != nil {
+		return nil, fmt.Errorf("put object: %w", err)
+	}
+
+	sec := &file.Secret{
+		ID:     id,
+		UserID: userID,
+		Name:   name,
+		Content: contentinfo.ContentInfo{
+			ObjectKey: objKey,
+			Size:      size,
+			Checksum:  contentinfo.NewChecksum(contentinfo.ChecksumSHA256, hex.EncodeToString(hasher.Sum(nil))),
+			KeyID:     s.keyring.PrimaryKeyID(),
+		},
+	}
+
+	if thumbBuf != nil {
+		// A bad or undecodable image shouldn't fail the upload; the
+		// secret is simply left without a thumbnail.
+		if thumbKey, err := s.uploadThumbnail(ctx, userID, id, thumbBuf.Bytes()); err == nil {
+			sec.Thumbnail = contentinfo.ContentInfo{
+				ObjectKey: thumbKey,
+				KeyID:     s.keyring.PrimaryKeyID(),
+			}
+		}
+	}
+
+	if err := s.storage.Create(ctx, sec); err != nil {
+		return nil, fmt.Errorf("create file secret: %w", err)
+	}
+
+	return sec, nil
+}
+
+// presignedUploadTTL bounds how long a StageUpload URL remains usable.
+const presignedUploadTTL = 15 * time.Minute
+
+// stagingPrefix namespaces direct-upload staging objects, separately from
+// any secret's permanent object key.
+const stagingPrefix = "staging"
+
+// StageUpload returns a URL the caller may PUT raw file bytes to directly in
+// object storage, bypassing the app server's data path, and the staging key
+// identifying that upload for a later Complete call. The staged object is
+// never itself treated as a secret: it holds unencrypted bytes and must be
+// consumed (or expire via bucket lifecycle policy) before Complete runs.
+func (s *Service) StageUpload(ctx context.Context, userID string) (url, stagingKey string, err error) {
+	token, err := randomID()
+	if err != nil {
+		return "", "", fmt.Errorf("generate staging token: %w", err)
+	}
+
+	stagingKey = fmt.Sprintf("%s/%s/%s", stagingPrefix, userID, token)
+
+	url, err = s.objects.PresignedPutURL(ctx, stagingKey, presignedUploadTTL)
+	if err != nil {
+		return "", "", fmt.Errorf("presign staging upload: %w", err)
+	}
+
+	return url, stagingKey, nil
+}
+
+// CompleteUpload finalizes a direct upload previously staged via StageUpload:
+// it reads the plaintext object at stagingKey, encrypts it under the
+// keyring's primary key into the secret's permanent object key, records
+// content info, and removes the staging object. It fails with
+// ErrAccessDenied if stagingKey was not issued to userID, and with
+// ErrContentTypeNotAllowed if contentType is rejected by the service's
+// ContentTypePolicy.
+func (s *Service) CompleteUpload(ctx context.Context, userID, stagingKey, name, contentType string) (*file.Secret, error) {
+	if !strings.HasPrefix(stagingKey, fmt.Sprintf("%s/%s/", stagingPrefix, userID)) {
+		return nil, ErrAccessDenied
+	}
+
+	if err := s.contentTypes.Check(contentType); err != nil {
+		return nil, err
+	}
+
+	staged, err := s.objects.Get(ctx, stagingKey)
+	if err != nil {
+		return nil, fmt.Errorf("get staged object: %w", err)
+	}
+	defer staged.Close()
+
+	id, err := randomID()
+	if err != nil {
+		return nil, fmt.Errorf("generate id: %w", err)
+	}
+
+	counter := &byteCounter{}
+	teed := io.TeeReader(staged, counter)
+
+	pr, pw := io.Pipe()
+
+	go func() {
+		pw.CloseWithError(cryptutils.EncryptStream(pw, teed, s.keyring.PrimaryKey(), contentinfo.CompressionNone))
+	}()
+
+	objKey := getObjName(userID, id, name)
+
+	if err := s.objects.Put(ctx, objKey, pr, -1, objrepo.Tags{UserID: userID, SecretID: id, SecretType: "file"}); err != nil {
+		return nil, fmt.Errorf("put object: %w", err)
+	}
+
+	sec := &file.Secret{
+		ID:     id,
+		UserID: userID,
+		Name:   name,
+		Content: contentinfo.ContentInfo{
+			ObjectKey: objKey,
+			Size:      counter.n,
+			KeyID:     s.keyring.PrimaryKeyID(),
+		},
+	}
+
+	if err := s.storage.Create(ctx, sec); err != nil {
+		return nil, fmt.Errorf("create file secret: %w", err)
+	}
+
+	if err := s.objects.Delete(ctx, stagingKey); err != nil {
+		return nil, fmt.Errorf("delete staged object: %w", err)
+	}
+
+	return sec, nil
+}
+
+// byteCounter counts bytes written to it, for measuring a stream's length
+// as it's consumed rather than buffering it to find out up front.
+type byteCounter struct{ n int64 }
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+
+	return len(p), nil
+}
+
+// uploadThumbnail generates a JPEG thumbnail from data and stores it
+// encrypted under a key derived from userID and id, returning that key.
+func (s *Service) uploadThumbnail(ctx context.Context, userID, id string, data []byte) (string, error) {
+	thumb, err := imgthumb.Generate(data)
+	if err != nil {
+		return "", fmt.Errorf("generate thumbnail: %w", err)
+	}
+
+	objKey := fmt.Sprintf("users/%s/files/%s-thumb.jpg", userID, id)
+	size := int64(len(thumb)) + cryptutils.IVSize
+
+	pr, pw := io.Pipe()
+
+	go func() {
+		pw.CloseWithError(cryptutils.EncryptStream(pw, bytes.NewReader(thumb), s.keyring.PrimaryKey(), contentinfo.CompressionNone))
+	}()
+
+	if err := s.objects.Put(ctx, objKey, pr, size, objrepo.Tags{UserID: userID, SecretID: id, SecretType: "file-thumbnail"}); err != nil {
+		return "", fmt.Errorf("put thumbnail object: %w", err)
+	}
+
+	return objKey, nil
+}
+
+// Thumbnail returns a reader for userID's file secret id's decrypted
+// thumbnail, if one was generated at upload time and userID is the secret's
+// owner or has been granted read access via its ACL.
+func (s *Service) Thumbnail(ctx context.Context, userID, id string) (io.ReadCloser, *file.Secret, error) {
+	sec, err := s.storage.Get(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get file secret: %w", err)
+	}
+
+	if !canAccess(sec, userID, acl.PermissionRead) {
+		return nil, nil, ErrAccessDenied
+	}
+
+	if sec.Thumbnail.ObjectKey == "" {
+		return nil, nil, ErrNoThumbnail
+	}
+
+	key, err := s.keyring.Key(sec.Thumbnail.KeyID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("select decryption key: %w", err)
+	}
+
+	obj, err := s.objects.Get(ctx, sec.Thumbnail.ObjectKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get object: %w", err)
+	}
+
+	pr, pw := io.Pipe()
+
+	go func() {
+		err := cryptutils.DecryptStream(pw, obj, key, contentinfo.CompressionNone)
+		obj.Close()
+		pw.CloseWithError(err)
+	}()
+
+	return pr, sec, nil
+}
+
+// Download returns a reader for userID's file secret id's decrypted
+// payload, if userID is its owner or has been granted read access via its
+// ACL. The key used is selected by the record's stored KeyID, so rotating
+// the primary key doesn't break decryption of records written under a
+// retired one. Callers must close the returned reader.
+func (s *Service) Download(ctx context.Context, userID, id string) (io.ReadCloser, *file.Secret, error) {
+	sec, err := s.storage.Get(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get file secret: %w", err)
+	}
+
+	if !canAccess(sec, userID, acl.PermissionRead) {
+		return nil, nil, ErrAccessDenied
+	}
+
+	pr, err := s.decryptContent(ctx, sec.Content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pr, sec, nil
+}
+
+// DownloadVersion returns a reader for userID's file secret id's decrypted
+// content at version (an index into Secret.Versions, oldest retained
+// first), if userID is its owner or has been granted read access via its
+// ACL. It fails with ErrVersionNotFound if version is out of range,
+// including when it refers to a version already purged past
+// NewService's maxVersions. Callers must close the returned reader.
+func (s *Service) DownloadVersion(ctx context.Context, userID, id string, version int) (io.ReadCloser, *file.Secret, error) {
+	sec, err := s.storage.Get(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get file secret: %w", err)
+	}
+
+	if !canAccess(sec, userID, acl.PermissionRead) {
+		return nil, nil, ErrAccessDenied
+	}
+
+	if version < 0 || version >= len(sec.Versions) {
+		return nil, nil, ErrVersionNotFound
+	}
+
+	pr, err := s.decryptContent(ctx, sec.Versions[version])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pr, sec, nil
+}
+
+// ManifestChunkSize is the chunk size Manifest divides decrypted content
+// into. Fixed rather than configurable, so a client's locally-computed
+// chunk checksums always line up with what Manifest returns.
+const ManifestChunkSize = 4 << 20 // 4 MiB
+
+// Chunk is one fixed-size slice of a file secret's decrypted content, as
+// returned by Manifest.
+type Chunk struct {
+	Offset   int64
+	Size     int64
+	Checksum contentinfo.Checksum
+}
+
+// Manifest returns userID's file secret id's decrypted content split into
+// ManifestChunkSize chunks with each chunk's own checksum, so a client that
+// already has some prefix of a prior download can verify which chunks it
+// still holds are intact. Download serves no byte ranges, so the chunks that
+// aren't intact can only be fetched again with the whole content. It decrypts the whole object to compute per-chunk checksums, the same
+// cost as a full Download.
+func (s *Service) Manifest(ctx context.Context, userID, id string) ([]Chunk, *file.Secret, error) {
+	sec, err := s.storage.Get(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get file secret: %w", err)
+	}
+
+	if !canAccess(sec, userID, acl.PermissionRead) {
+		return nil, nil, ErrAccessDenied
+	}
+
+	pr, err := s.decryptContent(ctx, sec.Content)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer pr.Close()
+
+	chunks, err := chunkManifest(pr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("compute chunk manifest: %w", err)
+	}
+
+	return chunks, sec, nil
+}
+
+// chunkManifest reads r to the end, returning one Chunk per ManifestChunkSize
+// bytes (the last chunk may be shorter).
+func chunkManifest(r io.Reader) ([]Chunk, error) {
+	var (
+		chunks []Chunk
+		offset int64
+	)
+
+	buf := make([]byte, ManifestChunkSize)
+
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			sum := sha256.Sum256(buf[:n])
+			chunks = append(chunks, Chunk{
+				Offset:   offset,
+				Size:     int64(n),
+				Checksum: contentinfo.NewChecksum(contentinfo.ChecksumSHA256, hex.EncodeToString(sum[:])),
+			})
+			offset += int64(n)
+		}
+
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return chunks, nil
+}
+
+// decryptContent returns a reader streaming content decrypted with the key
+// identified by content.KeyID. Callers must close the returned reader.
+func (s *Service) decryptContent(ctx context.Context, content contentinfo.ContentInfo) (io.ReadCloser, error) {
+	key, err := s.keyring.Key(content.KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("select decryption key: %w", err)
+	}
+
+	obj, err := s.objects.Get(ctx, content.ObjectKey)
+	if err != nil {
+		return nil, fmt.Errorf("get object: %w", err)
+	}
+
+	pr, pw := io.Pipe()
+
+	go func() {
+		err := cryptutils.DecryptStream(pw, obj, key, content.CompressionAlgo)
+		obj.Close()
+		pw.CloseWithError(err)
+	}()
+
+	return pr, nil
+}
+
+// Preview returns a reader for the first n decrypted bytes of userID's file
+// secret id's payload, if userID is its owner or has been granted read
+// access via its ACL, so clients can show a preview without fetching the
+// whole object. Uncompressed secrets are decrypted incrementally, reading
+// only as much ciphertext as needed; compressed ones must still be decrypted
+// in full, since a compressed stream can't be truncated mid-way. Callers
+// must close the returned reader.
+func (s *Service) Preview(ctx context.Context, userID, id string, n int64) (io.ReadCloser, *file.Secret, error) {
+	sec, err := s.storage.Get(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get file secret: %w", err)
+	}
+
+	if !canAccess(sec, userID, acl.PermissionRead) {
+		return nil, nil, ErrAccessDenied
+	}
+
+	key, err := s.keyring.Key(sec.Content.KeyID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("select decryption key: %w", err)
+	}
+
+	obj, err := s.objects.Get(ctx, sec.Content.ObjectKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get object: %w", err)
+	}
+
+	pr, pw := io.Pipe()
+
+	go func() {
+		var err error
+		if sec.Content.CompressionAlgo == contentinfo.CompressionNone {
+			err = cryptutils.DecryptStreamN(pw, obj, key, n)
+		} else {
+			err = cryptutils.DecryptStream(&limitedWriter{w: pw, n: n}, obj, key, sec.Content.CompressionAlgo)
+		}
+		obj.Close()
+		pw.CloseWithError(err)
+	}()
+
+	return pr, sec, nil
+}
+
+// limitedWriter writes at most n bytes to w, silently discarding the rest,
+// so DecryptStream can be run to completion (compressed streams can't be
+// stopped mid-way) while a previewing reader only ever receives n bytes.
+type limitedWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if l.n <= 0 {
+		return len(p), nil
+	}
+
+	if int64(len(p)) > l.n {
+		if _, err := l.w.Write(p[:l.n]); err != nil {
+			return 0, err
+		}
+
+		n := len(p)
+		l.n = 0
+
+		return n, nil
+	}
+
+	written, err := l.w.Write(p)
+	l.n -= int64(written)
+
+	return written, err
+}
+
+// Replace re-encrypts and re-uploads userID's file secret id's content from
+// r, if userID is its owner or has been granted write access via its ACL.
+// The new content is written to a fresh object key and verified by checksum
+// before the secret's metadata is atomically swapped to point at it, so a
+// failed or partial upload never corrupts the existing content. The
+// displaced content is kept in Secret.Versions (see DownloadVersion)
+// instead of deleted outright; once that history exceeds the service's
+// maxVersions, the oldest retained version's object is purged.
+func (s *Service) Replace(ctx context.Context, userID, id string, r io.Reader, size int64) (*file.Secret, error) {
+	s.uploadLocks.Lock(id)
+	defer s.uploadLocks.Unlock(id)
+
+	sec, err := s.storage.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get file secret: %w", err)
+	}
+
+	if !canAccess(sec, userID, acl.PermissionWrite) {
+		return nil, ErrAccessDenied
+	}
+
+	newID, err := randomID()
+	if err != nil {
+		return nil, fmt.Errorf("generate id: %w", err)
+	}
+
+	newObjKey := getObjName(userID, newID, sec.Name)
+
+	hasher := sha256.New()
+	teed := io.TeeReader(r, hasher)
+
+	pr, pw := io.Pipe()
+
+	go func() {
+		pw.CloseWithError(cryptutils.EncryptStream(pw, teed, s.keyring.PrimaryKey(), contentinfo.CompressionNone))
+	}()
+
+	encryptedSize := size + cryptutils.IVSize
+
+	if err := s.objects.Put(ctx, newObjKey, pr, encryptedSize, objrepo.Tags{UserID: userID, SecretID: id, SecretType: "file"}); err != nil {
+		return nil, fmt.Errorf("put object: %w", err)
+	}
+
+	updated := *sec
+	updated.Content = contentinfo.ContentInfo{
+		ObjectKey: newObjKey,
+		Size:      size,
+		Checksum:  contentinfo.NewChecksum(contentinfo.ChecksumSHA256, hex.EncodeToString(hasher.Sum(nil))),
+		KeyID:     s.keyring.PrimaryKeyID(),
+	}
+
+	updated.Versions = append(append([]contentinfo.ContentInfo{}, sec.Versions...), sec.Content)
+
+	var purgeObjKey string
+	if len(updated.Versions) > s.maxVersions {
+		purgeObjKey = updated.Versions[0].ObjectKey
+		updated.Versions = updated.Versions[1:]
+	}
+
+	if err := s.storage.Update(ctx, &updated); err != nil {
+		if delErr := s.objects.Delete(ctx, newObjKey); delErr != nil {
+			return nil, fmt.Errorf("update file secret: %w (and cleanup failed: %w)", err, delErr)
+		}
+
+		return nil, fmt.Errorf("update file secret: %w", err)
+	}
+
+	if purgeObjKey != "" {
+		if err := s.objects.Delete(ctx, purgeObjKey); err != nil {
+			return nil, fmt.Errorf("delete expired version object: %w", err)
+		}
+	}
+
+	return &updated, nil
+}
+
+// Delete moves the secret with id into the trash, if userID is its owner.
+// ACL grants do not extend to deletion. Its object storage content is left
+// in place until retentionsvc.PurgeExpired permanently removes it (see
+// PurgeTrashed); Restore undoes this before that happens.
+func (s *Service) Delete(ctx context.Context, userID, id string) error {
+	sec, err := s.storage.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get file secret: %w", err)
+	}
+
+	if sec.UserID != userID {
+		return ErrAccessDenied
+	}
+
+	if err := s.storage.Trash(ctx, id, time.Now()); err != nil {
+		return fmt.Errorf("trash file secret: %w", err)
+	}
+
+	return nil
+}
+
+// Restore takes userID's file secret id out of the trash, if userID is its
+// owner and it is currently trashed.
+func (s *Service) Restore(ctx context.Context, userID, id string) error {
+	sec, err := s.storage.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get file secret: %w", err)
+	}
+
+	if sec.UserID != userID {
+		return ErrAccessDenied
+	}
+
+	if sec.DeletedAt == nil {
+		return ErrNotTrashed
+	}
+
+	if err := s.storage.Restore(ctx, id); err != nil {
+		return fmt.Errorf("restore file secret: %w", err)
+	}
+
+	return nil
+}
+
+// SetHighSecurity marks or clears the secret's step-up gate (see
+// metadata.MarkHighSecurity), if userID is its owner or has been granted
+// write access via its ACL.
+func (s *Service) SetHighSecurity(ctx context.Context, userID, id string, enabled bool) error {
+	sec, err := s.storage.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get file secret: %w", err)
+	}
+
+	if !canAccess(sec, userID, acl.PermissionWrite) {
+		return ErrAccessDenied
+	}
+
+	metadata.MarkHighSecurity(&sec.Metadata, enabled)
+
+	if err := s.storage.Update(ctx, sec); err != nil {
+		return fmt.Errorf("update file secret: %w", err)
+	}
+
+	return nil
+}
+
+// List returns every file secret owned by userID, excluding trashed ones.
+func (s *Service) List(ctx context.Context, userID string) ([]*file.Secret, error) {
+	secrets, err := s.storage.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list file secrets: %w", err)
+	}
+
+	return secrets, nil
+}
+
+// ListTrashed returns userID's trashed file secrets.
+func (s *Service) ListTrashed(ctx context.Context, userID string) ([]*file.Secret, error) {
+	secrets, err := s.storage.ListTrashed(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list trashed file secrets: %w", err)
+	}
+
+	return secrets, nil
+}
+
+// ListAllTrashed returns every file secret, across all users, trashed
+// before cutoff. It backs retentionsvc's background purge via trashsvc.
+func (s *Service) ListAllTrashed(ctx context.Context, cutoff time.Time) ([]*file.Secret, error) {
+	secrets, err := s.storage.ListAllTrashed(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list trashed file secrets: %w", err)
+	}
+
+	out := make([]*file.Secret, 0, len(secrets))
+	for _, sec := range secrets {
+		if sec.DeletedAt != nil && sec.DeletedAt.Before(cutoff) {
+			out = append(out, sec)
+		}
+	}
+
+	return out, nil
+}
+
+// PurgeTrashed permanently deletes the trashed file secret id, including
+// its object storage content, thumbnail and retained versions. Unlike
+// Delete, it does not check ownership: it is only ever called by
+// retentionsvc's background purge (via trashsvc) against a secret it
+// already found in the trash.
+func (s *Service) PurgeTrashed(ctx context.Context, id string) error {
+	sec, err := s.storage.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get file secret: %w", err)
+	}
+
+	if err := s.objects.Delete(ctx, sec.Content.ObjectKey); err != nil {
+		return fmt.Errorf("delete object: %w", err)
+	}
+
+	if sec.Thumbnail.ObjectKey != "" {
+		if err := s.objects.Delete(ctx, sec.Thumbnail.ObjectKey); err != nil {
+			return fmt.Errorf("delete thumbnail object: %w", err)
+		}
+	}
+
+	for _, v := range sec.Versions {
+		if err := s.objects.Delete(ctx, v.ObjectKey); err != nil {
+			return fmt.Errorf("delete version object: %w", err)
+		}
+	}
+
+	if err := s.storage.Delete(ctx, id); err != nil {
+		return fmt.Errorf("delete file secret: %w", err)
+	}
+
+	return nil
+}
+
+func canAccess(sec *file.Secret, userID string, perm acl.Permission) bool {
+	if sec.UserID == userID {
+		return true
+	}
+
+	return sec.ACL.Allows(acl.UserPrincipal(userID), perm)
+}
+
+func randomID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,137 @@
+// Package fscksvc cross-references file/text secret metadata against the
+// objects actually present in object storage, detecting drift between the
+// two: rows pointing at objects that no longer exist, objects left behind
+// with no owning row, and stored objects whose size disagrees with what the
+// row expects.
+package fscksvc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/andymarkow/gophkeeper/internal/cryptutils"
+	"github.com/andymarkow/gophkeeper/internal/objrepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/filerepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/textrepo"
+)
+
+// Finding describes one row/object discrepancy.
+type Finding struct {
+	SecretType string // "file", "file-thumbnail" or "text"
+	SecretID   string
+	UserID     string
+	ObjectKey  string
+	Detail     string
+}
+
+// Report summarizes one fsck run.
+type Report struct {
+	MissingObjects  []Finding
+	SizeMismatches  []Finding
+	OrphanedObjects []string
+}
+
+// Service checks file/text secret metadata against object storage contents.
+type Service struct {
+	files   filerepo.Storage
+	texts   textrepo.Storage
+	objects objrepo.Storage
+}
+
+// NewService returns a Service that cross-references files and texts
+// against the objects bucket.
+func NewService(files filerepo.Storage, texts textrepo.Storage, objects objrepo.Storage) *Service {
+	return &Service{files: files, texts: texts, objects: objects}
+}
+
+// Check lists every file/text row and every object in the bucket, and
+// reports where they disagree.
+func (s *Service) Check(ctx context.Context) (Report, error) {
+	files, err := s.files.ListAll(ctx)
+	if err != nil {
+		return Report{}, fmt.Errorf("list file secrets: %w", err)
+	}
+
+	texts, err := s.texts.ListAll(ctx)
+	if err != nil {
+		return Report{}, fmt.Errorf("list text secrets: %w", err)
+	}
+
+	objects, err := s.objects.List(ctx, "")
+	if err != nil {
+		return Report{}, fmt.Errorf("list objects: %w", err)
+	}
+
+	sizes := make(map[string]int64, len(objects))
+	for _, o := range objects {
+		sizes[o.Key] = o.Size
+	}
+
+	known := make(map[string]bool, len(objects))
+
+	var report Report
+
+	check := func(secretType, id, userID, objKey string, plaintextSize int64) {
+		known[objKey] = true
+
+		size, ok := sizes[objKey]
+		if !ok {
+			report.MissingObjects = append(report.MissingObjects, Finding{
+				SecretType: secretType, SecretID: id, UserID: userID, ObjectKey: objKey,
+				Detail: "object not found in storage",
+			})
+
+			return
+		}
+
+		// Stored objects are IV-prefixed ciphertext, so their size is the
+		// plaintext size plus one IV. A negative plaintextSize (e.g.
+		// thumbnails, whose size isn't tracked on the row) skips this check.
+		if plaintextSize < 0 {
+			return
+		}
+
+		if want := plaintextSize + cryptutils.IVSize; size != want {
+			report.SizeMismatches = append(report.SizeMismatches, Finding{
+				SecretType: secretType, SecretID: id, UserID: userID, ObjectKey: objKey,
+				Detail: fmt.Sprintf("expected %d bytes, got %d", want, size),
+			})
+		}
+	}
+
+	for _, f := range files {
+		check("file", f.ID, f.UserID, f.Content.ObjectKey, f.Content.Size)
+
+		if f.Thumbnail.ObjectKey != "" {
+			check("file-thumbnail", f.ID, f.UserID, f.Thumbnail.ObjectKey, -1)
+		}
+
+		for _, v := range f.Versions {
+			check("file", f.ID, f.UserID, v.ObjectKey, v.Size)
+		}
+	}
+
+	for _, t := range texts {
+		check("text", t.ID, t.UserID, t.Content.ObjectKey, t.Content.Size)
+	}
+
+	for key := range sizes {
+		if !known[key] {
+			report.OrphanedObjects = append(report.OrphanedObjects, key)
+		}
+	}
+
+	return report, nil
+}
+
+// Repair deletes every orphaned object recorded in report. Missing objects
+// and size mismatches require operator judgment and are never auto-repaired.
+func (s *Service) Repair(ctx context.Context, report Report) error {
+	for _, key := range report.OrphanedObjects {
+		if err := s.objects.Delete(ctx, key); err != nil {
+			return fmt.Errorf("delete orphaned object %q: %w", key, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+// Package gdprsvc implements the admin data-export and right-to-erasure
+// workflows required for GDPR compliance.
+package gdprsvc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/repository/userrepo"
+)
+
+// Exporter produces a user's domain records for inclusion in an export.
+// An export holds one section per Exporter passed to NewService; no secret
+// service in this tree implements it.
+type Exporter interface {
+	// Name identifies the section this exporter contributes, e.g.
+	// "credentials".
+	Name() string
+	ExportUser(ctx context.Context, userID string) (any, error)
+}
+
+// Eraser irreversibly deletes a user's records from one subsystem.
+type Eraser interface {
+	EraseUser(ctx context.Context, userID string) error
+}
+
+// Service coordinates export/erasure across every registered subsystem.
+type Service struct {
+	users     userrepo.Storage
+	exporters []Exporter
+	erasers   []Eraser
+	signKey   []byte
+}
+
+// NewService returns a Service that exports from exporters and erases via
+// erasers, signing export reports with signKey so recipients can verify
+// authenticity.
+func NewService(users userrepo.Storage, exporters []Exporter, erasers []Eraser, signKey []byte) *Service {
+	return &Service{users: users, exporters: exporters, erasers: erasers, signKey: signKey}
+}
+
+// Export is the complete machine-readable export for one subject.
+type Export struct {
+	UserID      string         `json:"user_id"`
+	Login       string         `json:"login"`
+	GeneratedAt time.Time      `json:"generated_at"`
+	Sections    map[string]any `json:"sections"`
+	Signature   string         `json:"signature"`
+}
+
+// ExportUser produces a signed export of every registered subsystem's data
+// for userID.
+func (s *Service) ExportUser(ctx context.Context, userID string) (*Export, error) {
+	u, err := s.users.GetUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+
+	export := &Export{
+		UserID:      userID,
+		Login:       u.Login,
+		GeneratedAt: time.Now(),
+		Sections:    make(map[string]any, len(s.exporters)),
+	}
+
+	for _, exporter := range s.exporters {
+		data, err := exporter.ExportUser(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("export %s: %w", exporter.Name(), err)
+		}
+
+		export.Sections[exporter.Name()] = data
+	}
+
+	sig, err := s.sign(export)
+	if err != nil {
+		return nil, err
+	}
+
+	export.Signature = sig
+
+	return export, nil
+}
+
+// EraseUser irreversibly deletes the user's data from every registered
+// subsystem. Subsystems are erased in order; the first failure aborts and is
+// returned, so the account is never left half-erased without a clear error
+// surfaced to the operator. The user record itself is not deleted.
+func (s *Service) EraseUser(ctx context.Context, userID string) error {
+	for _, eraser := range s.erasers {
+		if err := eraser.EraseUser(ctx, userID); err != nil {
+			return fmt.Errorf("erase user data: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// sign computes an HMAC-SHA256 over the export's content (excluding the
+// signature field itself) so a recipient can verify the report was produced
+// by this server and not tampered with afterward.
+func (s *Service) sign(export *Export) (string, error) {
+	payload, err := json.Marshal(struct {
+		UserID      string         `json:"user_id"`
+		Login       string         `json:"login"`
+		GeneratedAt time.Time      `json:"generated_at"`
+		Sections    map[string]any `json:"sections"`
+	}{export.UserID, export.Login, export.GeneratedAt, export.Sections})
+	if err != nil {
+		return "", fmt.Errorf("marshal export for signing: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, s.signKey)
+	mac.Write(payload)
+
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
@@ -0,0 +1,196 @@
+// Package genericsvc implements generic key/value secret CRUD, centrally
+// enforcing ownership and the per-secret ACL.
+package genericsvc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/andymarkow/gophkeeper/internal/cryptutils"
+	"github.com/andymarkow/gophkeeper/internal/domain/acl"
+	"github.com/andymarkow/gophkeeper/internal/domain/generic"
+	"github.com/andymarkow/gophkeeper/internal/domain/metadata"
+	"github.com/andymarkow/gophkeeper/internal/repository/genericrepo"
+)
+
+// ErrAccessDenied is returned when the caller is neither the secret's owner
+// nor granted access via its ACL.
+var ErrAccessDenied = errors.New("access denied")
+
+// ErrSecretLimitExceeded is returned by Create when userID already owns
+// maxSecrets generic secrets.
+var ErrSecretLimitExceeded = errors.New("generic secret limit exceeded for this account")
+
+// Service implements generic key/value secret operations.
+type Service struct {
+	storage    genericrepo.Storage
+	keyring    *cryptutils.Keyring
+	maxSecrets int
+}
+
+// NewService returns a Service backed by storage, encrypting and decrypting
+// payloads with keyring. maxSecrets caps how many generic secrets a single
+// user may own; 0 disables the cap.
+func NewService(storage genericrepo.Storage, keyring *cryptutils.Keyring, maxSecrets int) *Service {
+	return &Service{storage: storage, keyring: keyring, maxSecrets: maxSecrets}
+}
+
+// EncryptPayload JSON-marshals payload and encrypts it under the keyring's
+// primary key, returning the ciphertext and the key ID it was encrypted
+// under, ready to assign to a Secret's Payload and KeyID fields.
+func (s *Service) EncryptPayload(payload map[string]any) (ciphertext []byte, keyID string, err error) {
+	plaintext, err := json.Marshal(payload)
+	if err != nil {
+		return nil, "", fmt.Errorf("marshal payload: %w", err)
+	}
+
+	ciphertext, err = cryptutils.Encrypt(plaintext, s.keyring.PrimaryKey())
+	if err != nil {
+		return nil, "", fmt.Errorf("encrypt payload: %w", err)
+	}
+
+	return ciphertext, s.keyring.PrimaryKeyID(), nil
+}
+
+// DecryptPayload decrypts sec's Payload and unmarshals it back into a
+// key/value map.
+func (s *Service) DecryptPayload(sec *generic.Secret) (map[string]any, error) {
+	key, err := s.keyring.Key(sec.KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("select decryption key: %w", err)
+	}
+
+	plaintext, err := cryptutils.Decrypt(sec.Payload, key)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt payload: %w", err)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(plaintext, &payload); err != nil {
+		return nil, fmt.Errorf("unmarshal payload: %w", err)
+	}
+
+	return payload, nil
+}
+
+// Create stores a new secret owned by userID. sec.Payload must already be
+// encrypted, e.g. via EncryptPayload. Fails with ErrSecretLimitExceeded if
+// userID already owns the configured maximum.
+func (s *Service) Create(ctx context.Context, userID string, sec *generic.Secret) error {
+	if s.maxSecrets > 0 {
+		existing, err := s.storage.List(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("list generic secrets: %w", err)
+		}
+
+		if len(existing) >= s.maxSecrets {
+			return ErrSecretLimitExceeded
+		}
+	}
+
+	sec.UserID = userID
+
+	if err := s.storage.Create(ctx, sec); err != nil {
+		return fmt.Errorf("create generic secret: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns the secret with id if userID is its owner or has been granted
+// read access via its ACL.
+func (s *Service) Get(ctx context.Context, userID, id string) (*generic.Secret, error) {
+	sec, err := s.storage.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get generic secret: %w", err)
+	}
+
+	if !canAccess(sec, userID, acl.PermissionRead) {
+		return nil, ErrAccessDenied
+	}
+
+	return sec, nil
+}
+
+// Update persists changes to sec if userID is its owner or has been granted
+// write access via its ACL. sec.Payload must already be encrypted.
+func (s *Service) Update(ctx context.Context, userID string, sec *generic.Secret) error {
+	existing, err := s.storage.Get(ctx, sec.ID)
+	if err != nil {
+		return fmt.Errorf("get generic secret: %w", err)
+	}
+
+	if !canAccess(existing, userID, acl.PermissionWrite) {
+		return ErrAccessDenied
+	}
+
+	sec.UserID = existing.UserID
+
+	if err := s.storage.Update(ctx, sec); err != nil {
+		return fmt.Errorf("update generic secret: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the secret with id if userID is its owner. ACL grants do
+// not extend to deletion, only the owner can delete.
+func (s *Service) Delete(ctx context.Context, userID, id string) error {
+	existing, err := s.storage.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get generic secret: %w", err)
+	}
+
+	if existing.UserID != userID {
+		return ErrAccessDenied
+	}
+
+	if err := s.storage.Delete(ctx, id); err != nil {
+		return fmt.Errorf("delete generic secret: %w", err)
+	}
+
+	return nil
+}
+
+// List returns every secret owned by userID. It does not include secrets
+// merely shared with userID via ACL.
+func (s *Service) List(ctx context.Context, userID string) ([]*generic.Secret, error) {
+	secrets, err := s.storage.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list generic secrets: %w", err)
+	}
+
+	return secrets, nil
+}
+
+// SetHighSecurity marks or clears the secret's step-up gate (see
+// metadata.MarkHighSecurity), if userID is its owner or has been granted
+// write access via its ACL.
+func (s *Service) SetHighSecurity(ctx context.Context, userID, id string, enabled bool) error {
+	sec, err := s.storage.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get generic secret: %w", err)
+	}
+
+	if !canAccess(sec, userID, acl.PermissionWrite) {
+		return ErrAccessDenied
+	}
+
+	metadata.MarkHighSecurity(&sec.Metadata, enabled)
+
+	if err := s.storage.Update(ctx, sec); err != nil {
+		return fmt.Errorf("update generic secret: %w", err)
+	}
+
+	return nil
+}
+
+func canAccess(sec *generic.Secret, userID string, perm acl.Permission) bool {
+	if sec.UserID == userID {
+		return true
+	}
+
+	return sec.ACL.Allows(acl.UserPrincipal(userID), perm)
+}
@@ -0,0 +1,72 @@
+// Package loginalertsvc notifies users over their preferred notification
+// channels when their account is logged into from a device/IP that hasn't
+// been seen before.
+package loginalertsvc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/andymarkow/gophkeeper/internal/repository/sessionrepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/userrepo"
+	"github.com/andymarkow/gophkeeper/internal/service/notifysvc"
+)
+
+// Service detects new-device logins and notifies the affected user.
+type Service struct {
+	sessions sessionrepo.Storage
+	users    userrepo.Storage
+	notify   *notifysvc.Service
+}
+
+// NewService returns a Service wired to the given dependencies.
+func NewService(sessions sessionrepo.Storage, users userrepo.Storage, notify *notifysvc.Service) *Service {
+	return &Service{sessions: sessions, users: users, notify: notify}
+}
+
+// Fingerprint derives a stable, non-reversible device fingerprint from the
+// request's IP and User-Agent.
+func Fingerprint(ip, userAgent string) string {
+	sum := sha256.Sum256([]byte(ip + "|" + userAgent))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// NotifyIfNewDevice remembers the device fingerprint for userID and, if it
+// hasn't been seen before and the user has not opted out, sends them a login
+// alert over their preferred notification channels. Failures to notify are
+// returned but are not fatal to login; callers should log and continue
+// rather than fail the request.
+func (s *Service) NotifyIfNewDevice(ctx context.Context, userID, ip, userAgent string) error {
+	fp := Fingerprint(ip, userAgent)
+
+	known, err := s.sessions.IsKnown(ctx, userID, fp)
+	if err != nil {
+		return fmt.Errorf("check known device: %w", err)
+	}
+
+	if err := s.sessions.Remember(ctx, userID, fp); err != nil {
+		return fmt.Errorf("remember device: %w", err)
+	}
+
+	if known {
+		return nil
+	}
+
+	u, err := s.users.GetUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get user: %w", err)
+	}
+
+	if !u.NotifyOnNewLogin {
+		return nil
+	}
+
+	if err := s.notify.Notify(ctx, userID, notifysvc.KindLoginAlert, struct{ IP string }{IP: ip}); err != nil {
+		return fmt.Errorf("send login alert: %w", err)
+	}
+
+	return nil
+}
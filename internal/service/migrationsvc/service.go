@@ -0,0 +1,104 @@
+// Package migrationsvc reports the applied/pending state of the goose SQL
+// migrations under the repository's migrations directory, so operators can
+// verify schema state without shelling into the database.
+package migrationsvc
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Migration describes one migration file and whether goose has recorded it
+// as applied against the configured database.
+type Migration struct {
+	Version int64
+	Name    string
+	Applied bool
+}
+
+// Service reports migration status for the migrations found in dir against
+// the goose_db_version bookkeeping table in pool.
+type Service struct {
+	pool *pgxpool.Pool
+	dir  string
+}
+
+// NewService returns a Service reading migration files from dir and applied
+// state from pool's goose_db_version table.
+func NewService(pool *pgxpool.Pool, dir string) *Service {
+	return &Service{pool: pool, dir: dir}
+}
+
+var versionPrefix = regexp.MustCompile(`^(\d+)_`)
+
+// Status returns every migration found in the service's directory, ordered
+// by version, annotated with whether goose has applied it.
+func (s *Service) Status(ctx context.Context) ([]Migration, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("read migrations dir: %w", err)
+	}
+
+	applied, err := s.appliedVersions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query applied versions: %w", err)
+	}
+
+	var migrations []Migration
+
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".sql" {
+			continue
+		}
+
+		m := versionPrefix.FindStringSubmatch(e.Name())
+		if m == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		migrations = append(migrations, Migration{
+			Version: version,
+			Name:    e.Name(),
+			Applied: applied[version],
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+func (s *Service) appliedVersions(ctx context.Context) (map[int64]bool, error) {
+	const query = `SELECT version_id FROM goose_db_version WHERE is_applied = true`
+
+	rows, err := s.pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]bool)
+
+	for rows.Next() {
+		var version int64
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+
+		applied[version] = true
+	}
+
+	return applied, rows.Err()
+}
@@ -0,0 +1,300 @@
+// Package notifysvc renders and dispatches notifications — login alerts,
+// expiry warnings, share invitations — to each recipient's preferred
+// channels (email, Telegram, webhook), instead of every calling service
+// picking a channel and wording the message itself.
+package notifysvc
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/deadletter"
+	"github.com/andymarkow/gophkeeper/internal/domain/user"
+	"github.com/andymarkow/gophkeeper/internal/notify"
+	"github.com/andymarkow/gophkeeper/internal/repository/deadletterrepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/telegramrepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/userrepo"
+)
+
+// maxSendAttempts is how many times Notify tries a single channel before
+// giving up and dead-lettering the notification.
+const maxSendAttempts = 3
+
+// ErrChannelNotConfigured is returned by ReplayDeadLetter when the letter's
+// channel has no notifier registered (e.g. the webhook URL was unset after
+// the letter was recorded).
+var ErrChannelNotConfigured = errors.New("notification channel not configured")
+
+// Kind selects which notification template to render.
+type Kind string
+
+const (
+	KindLoginAlert      Kind = "login_alert"
+	KindExpiryWarning   Kind = "expiry_warning"
+	KindShareInvitation Kind = "share_invitation"
+	KindReminder        Kind = "reminder"
+)
+
+// Channel identifies a delivery mechanism. Its string value is also what
+// callers store in user.User.NotifyChannels.
+type Channel string
+
+const (
+	ChannelEmail    Channel = "email"
+	ChannelTelegram Channel = "telegram"
+	ChannelWebhook  Channel = "webhook"
+)
+
+// DefaultChannels is used for accounts with no NotifyChannels preference.
+var DefaultChannels = []Channel{ChannelEmail}
+
+type tmplPair struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+func mustTemplates(subject, body string) tmplPair {
+	return tmplPair{
+		subject: template.Must(template.New("subject").Parse(subject)),
+		body:    template.Must(template.New("body").Parse(body)),
+	}
+}
+
+// templates maps each Kind to its {subject, body} text/template pair. Data
+// passed to Notify is executed against both.
+var templates = map[Kind]tmplPair{
+	KindLoginAlert: mustTemplates(
+		"New login to your gophkeeper account",
+		"Your gophkeeper account was just logged into from a new device/IP ({{.IP}}).\n"+
+			"If this wasn't you, change your password immediately."),
+	KindExpiryWarning: mustTemplates(
+		"A secret in your vault is expiring soon",
+		`Your {{.SecretType}} {{printf "%q" .Name}} expires on {{.ExpiresAt}}. Update it before then.`),
+	KindShareInvitation: mustTemplates(
+		"A secret was shared with you",
+		`{{.SharerLogin}} shared a {{.SecretType}} secret named {{printf "%q" .Name}} with you on gophkeeper.`),
+	KindReminder: mustTemplates(
+		"Reminder: {{.Name}}",
+		`This is your reminder about the {{.SecretType}} secret {{printf "%q" .Name}}.`),
+}
+
+// Service renders a Kind's template and delivers it across a user's
+// preferred channels.
+type Service struct {
+	users       userrepo.Storage
+	telegram    telegramrepo.Storage
+	channels    map[Channel]notify.Notifier
+	deadletters deadletterrepo.Storage
+}
+
+// NewService returns a Service resolving recipients via users and telegram
+// (telegram may be nil if the Telegram integration isn't configured) and
+// delivering over the given per-channel notifiers. A channel with no entry
+// in channels is silently skipped for users preferring it. deadletters may
+// be nil, in which case exhausted deliveries are simply dropped as before,
+// with no record kept for replay.
+func NewService(
+	users userrepo.Storage,
+	telegram telegramrepo.Storage,
+	channels map[Channel]notify.Notifier,
+	deadletters deadletterrepo.Storage,
+) *Service {
+	return &Service{users: users, telegram: telegram, channels: channels, deadletters: deadletters}
+}
+
+// Notify renders kind's template with data and delivers it to userID over
+// every channel in its NotifyChannels preference (DefaultChannels, if
+// unset). It keeps trying the user's remaining channels even if one fails,
+// and returns every failure joined together.
+func (s *Service) Notify(ctx context.Context, userID string, kind Kind, data any) error {
+	tmpl, ok := templates[kind]
+	if !ok {
+		return fmt.Errorf("unknown notification kind %q", kind)
+	}
+
+	var subject, body bytes.Buffer
+
+	if err := tmpl.subject.Execute(&subject, data); err != nil {
+		return fmt.Errorf("render subject: %w", err)
+	}
+
+	if err := tmpl.body.Execute(&body, data); err != nil {
+		return fmt.Errorf("render body: %w", err)
+	}
+
+	u, err := s.users.GetUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get user: %w", err)
+	}
+
+	channels := u.NotifyChannels
+	if len(channels) == 0 {
+		channels = make([]string, len(DefaultChannels))
+		for i, c := range DefaultChannels {
+			channels[i] = string(c)
+		}
+	}
+
+	var errs []error
+
+	for _, name := range channels {
+		to, ok := s.recipient(ctx, Channel(name), u)
+		if !ok {
+			continue
+		}
+
+		notifier, ok := s.channels[Channel(name)]
+		if !ok {
+			continue
+		}
+
+		if err := sendWithRetry(ctx, notifier, to, subject.String(), body.String()); err != nil {
+			s.deadLetter(ctx, userID, name, string(kind), to, subject.String(), body.String(), err)
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// sendWithRetry calls notifier.Send up to maxSendAttempts times, returning
+// the last error if every attempt fails.
+func sendWithRetry(ctx context.Context, notifier notify.Notifier, to, subject, body string) error {
+	var err error
+
+	for attempt := 1; attempt <= maxSendAttempts; attempt++ {
+		if err = notifier.Send(ctx, to, subject, body); err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+// deadLetter records a delivery that exhausted every retry, so an operator
+// can inspect and replay it via ReplayDeadLetter instead of the event being
+// silently lost. Failure to record is itself swallowed: a dead-letter
+// backend outage must not also take down the original delivery error.
+func (s *Service) deadLetter(ctx context.Context, userID, channel, kind, to, subject, body string, sendErr error) {
+	if s.deadletters == nil {
+		return
+	}
+
+	id, err := randomID()
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+
+	_ = s.deadletters.Create(ctx, &deadletter.Letter{
+		ID:            id,
+		UserID:        userID,
+		Channel:       channel,
+		Kind:          kind,
+		Address:       to,
+		Subject:       subject,
+		Body:          body,
+		Error:         sendErr.Error(),
+		Attempts:      maxSendAttempts,
+		LastAttemptAt: now,
+	})
+}
+
+// ReplayDeadLetter retries a previously dead-lettered notification. On
+// success it removes the letter; on failure it bumps Attempts and Error so
+// the operator can see the replay also failed.
+func (s *Service) ReplayDeadLetter(ctx context.Context, id string) error {
+	if s.deadletters == nil {
+		return fmt.Errorf("replay dead letter: %w", deadletterrepo.ErrNotFound)
+	}
+
+	letter, err := s.deadletters.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get dead letter: %w", err)
+	}
+
+	notifier, ok := s.channels[Channel(letter.Channel)]
+	if !ok {
+		return fmt.Errorf("replay dead letter %q: %w", id, ErrChannelNotConfigured)
+	}
+
+	if err := sendWithRetry(ctx, notifier, letter.Address, letter.Subject, letter.Body); err != nil {
+		letter.Attempts++
+		letter.LastAttemptAt = time.Now()
+		letter.Error = err.Error()
+
+		if updateErr := s.deadletters.Update(ctx, letter); updateErr != nil {
+			return fmt.Errorf("update dead letter after failed replay: %w", updateErr)
+		}
+
+		return fmt.Errorf("replay dead letter %q: %w", id, err)
+	}
+
+	if err := s.deadletters.Delete(ctx, id); err != nil {
+		return fmt.Errorf("delete replayed dead letter: %w", err)
+	}
+
+	return nil
+}
+
+// ListDeadLetters returns every recorded delivery failure awaiting replay.
+func (s *Service) ListDeadLetters(ctx context.Context) ([]*deadletter.Letter, error) {
+	if s.deadletters == nil {
+		return nil, nil
+	}
+
+	letters, err := s.deadletters.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list dead letters: %w", err)
+	}
+
+	return letters, nil
+}
+
+// randomID returns a random 16-byte hex-encoded identifier for a new dead
+// letter.
+func randomID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate id: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// recipient resolves the address u should be reached at over channel.
+func (s *Service) recipient(ctx context.Context, channel Channel, u *user.User) (string, bool) {
+	switch channel {
+	case ChannelEmail:
+		return u.Email, u.Email != ""
+
+	case ChannelTelegram:
+		if s.telegram == nil {
+			return "", false
+		}
+
+		chatID, err := s.telegram.ChatForUser(ctx, u.ID)
+		if err != nil {
+			return "", false
+		}
+
+		return strconv.FormatInt(chatID, 10), true
+
+	case ChannelWebhook:
+		// The destination URL is fixed per deployment on the
+		// WebhookNotifier itself, so any user who opted in routes there;
+		// the user ID lets the receiver attribute the event.
+		return u.ID, true
+
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,83 @@
+// Package relationsvc manages links between secrets of any type.
+package relationsvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/relation"
+	"github.com/andymarkow/gophkeeper/internal/repository/relationrepo"
+)
+
+// ErrSelfLink is returned when a caller tries to link a secret to itself.
+var ErrSelfLink = errors.New("cannot link a secret to itself")
+
+// Service links and unlinks secrets and resolves the secrets linked to a
+// given one. It does not itself verify that either side of a relation
+// exists or that the caller owns it; callers are expected to resolve and
+// authorize both Refs against their owning secret service first.
+type Service struct {
+	storage relationrepo.Storage
+}
+
+// NewService returns a Service backed by storage.
+func NewService(storage relationrepo.Storage) *Service {
+	return &Service{storage: storage}
+}
+
+// Link records a relation between from and to.
+func (s *Service) Link(ctx context.Context, from, to relation.Ref) error {
+	if from == to {
+		return ErrSelfLink
+	}
+
+	if to.Type < from.Type || (to.Type == from.Type && to.ID < from.ID) {
+		from, to = to, from
+	}
+
+	if err := s.storage.Create(ctx, &relation.Relation{From: from, To: to}); err != nil {
+		return fmt.Errorf("create relation: %w", err)
+	}
+
+	return nil
+}
+
+// Unlink removes the relation between from and to, if any.
+func (s *Service) Unlink(ctx context.Context, from, to relation.Ref) error {
+	if err := s.storage.Delete(ctx, from, to); err != nil {
+		return fmt.Errorf("delete relation: %w", err)
+	}
+
+	return nil
+}
+
+// Linked returns every Ref linked to ref, in either direction.
+func (s *Service) Linked(ctx context.Context, ref relation.Ref) ([]relation.Ref, error) {
+	rels, err := s.storage.ListFor(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("list relations: %w", err)
+	}
+
+	out := make([]relation.Ref, 0, len(rels))
+	for _, rel := range rels {
+		if rel.From == ref {
+			out = append(out, rel.To)
+		} else {
+			out = append(out, rel.From)
+		}
+	}
+
+	return out, nil
+}
+
+// SecretDeleted removes every relation referencing ref. Secret services
+// should call this from their own Delete after the secret itself is
+// removed, so relations never outlive the secrets they connect.
+func (s *Service) SecretDeleted(ctx context.Context, ref relation.Ref) error {
+	if err := s.storage.DeleteAllFor(ctx, ref); err != nil {
+		return fmt.Errorf("delete relations for %s:%s: %w", ref.Type, ref.ID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,190 @@
+// Package remindersvc implements reminder rule CRUD and the delivery job
+// that fires a rule once it becomes due, notifying the owner via
+// notifysvc.
+package remindersvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/cryptutils"
+	"github.com/andymarkow/gophkeeper/internal/domain/reminder"
+	"github.com/andymarkow/gophkeeper/internal/repository/apitokenrepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/bankcardrepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/reminderrepo"
+	"github.com/andymarkow/gophkeeper/internal/service/expirysvc"
+	"github.com/andymarkow/gophkeeper/internal/service/notifysvc"
+)
+
+// ErrAccessDenied is returned when the caller does not own the rule.
+var ErrAccessDenied = errors.New("access denied")
+
+// ErrInvalidRule is returned when a rule sets neither or both of RemindAt
+// and DaysBeforeExpiry.
+var ErrInvalidRule = errors.New("exactly one of remind_at or days_before_expiry must be set")
+
+// ErrUnknownSecretType is returned when a rule references a SecretType that
+// delivery doesn't know how to resolve an expiry for.
+var ErrUnknownSecretType = errors.New("unknown secret type")
+
+// Service implements reminder rule CRUD, scoped to each user's own rules,
+// and DeliverDue, the job that fires rules once they come due.
+type Service struct {
+	rules     reminderrepo.Storage
+	bankcards bankcardrepo.Storage
+	apitokens apitokenrepo.Storage
+	keyring   *cryptutils.Keyring
+	notify    *notifysvc.Service
+}
+
+// NewService returns a Service backed by rules, resolving bank
+// card/API token expiry via bankcards/apitokens and keyring, and delivering
+// due reminders through notify.
+func NewService(
+	rules reminderrepo.Storage,
+	bankcards bankcardrepo.Storage,
+	apitokens apitokenrepo.Storage,
+	keyring *cryptutils.Keyring,
+	notify *notifysvc.Service,
+) *Service {
+	return &Service{rules: rules, bankcards: bankcards, apitokens: apitokens, keyring: keyring, notify: notify}
+}
+
+// Create validates and stores a new reminder rule owned by userID.
+func (s *Service) Create(ctx context.Context, userID string, rule *reminder.Rule) error {
+	if err := validateRule(rule); err != nil {
+		return err
+	}
+
+	rule.UserID = userID
+
+	if err := s.rules.Create(ctx, rule); err != nil {
+		return fmt.Errorf("create reminder rule: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns the rule with id if userID owns it.
+func (s *Service) Get(ctx context.Context, userID, id string) (*reminder.Rule, error) {
+	rule, err := s.rules.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get reminder rule: %w", err)
+	}
+
+	if rule.UserID != userID {
+		return nil, ErrAccessDenied
+	}
+
+	return rule, nil
+}
+
+// Delete removes the rule with id if userID owns it.
+func (s *Service) Delete(ctx context.Context, userID, id string) error {
+	if _, err := s.Get(ctx, userID, id); err != nil {
+		return err
+	}
+
+	if err := s.rules.Delete(ctx, id); err != nil {
+		return fmt.Errorf("delete reminder rule: %w", err)
+	}
+
+	return nil
+}
+
+// List returns userID's reminder rules.
+func (s *Service) List(ctx context.Context, userID string) ([]*reminder.Rule, error) {
+	rules, err := s.rules.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list reminder rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+func validateRule(rule *reminder.Rule) error {
+	if (rule.RemindAt == nil) == (rule.DaysBeforeExpiry == nil) {
+		return ErrInvalidRule
+	}
+
+	return nil
+}
+
+// DeliverDue scans every reminder rule, resolves the secret's expiry for
+// DaysBeforeExpiry rules, and notifies the owner of each one that has come
+// due, marking it fired so it isn't sent again.
+func (s *Service) DeliverDue(ctx context.Context) (int, error) {
+	rules, err := s.rules.ListAll(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list reminder rules: %w", err)
+	}
+
+	now := time.Now()
+	delivered := 0
+
+	for _, rule := range rules {
+		if rule.FiredAt != nil {
+			continue
+		}
+
+		name, expiresAt, err := s.resolveSecret(ctx, rule)
+		if err != nil {
+			continue
+		}
+
+		if !rule.Due(now, expiresAt) {
+			continue
+		}
+
+		data := struct{ SecretType, Name string }{
+			SecretType: string(rule.SecretType),
+			Name:       name,
+		}
+
+		if err := s.notify.Notify(ctx, rule.UserID, notifysvc.KindReminder, data); err != nil {
+			return delivered, fmt.Errorf("notify user %q: %w", rule.UserID, err)
+		}
+
+		rule.FiredAt = &now
+
+		if err := s.rules.Update(ctx, rule); err != nil {
+			return delivered, fmt.Errorf("mark rule %q fired: %w", rule.ID, err)
+		}
+
+		delivered++
+	}
+
+	return delivered, nil
+}
+
+// resolveSecret returns rule's secret's display name and, if known, its own
+// expiration date.
+func (s *Service) resolveSecret(ctx context.Context, rule *reminder.Rule) (name string, expiresAt *time.Time, err error) {
+	switch rule.SecretType {
+	case reminder.SecretTypeBankCard:
+		card, err := s.bankcards.Get(ctx, rule.SecretID)
+		if err != nil {
+			return "", nil, fmt.Errorf("get bank card: %w", err)
+		}
+
+		expiry, err := expirysvc.DecryptBankCardExpiry(card, s.keyring)
+		if err != nil {
+			return card.Name, nil, nil //nolint:nilerr // a reminder with no resolvable expiry just never fires
+		}
+
+		return card.Name, &expiry, nil
+
+	case reminder.SecretTypeAPIToken:
+		tok, err := s.apitokens.Get(ctx, rule.SecretID)
+		if err != nil {
+			return "", nil, fmt.Errorf("get api token: %w", err)
+		}
+
+		return tok.Name, tok.ExpiresAt, nil
+
+	default:
+		return "", nil, ErrUnknownSecretType
+	}
+}
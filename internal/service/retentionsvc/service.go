@@ -0,0 +1,104 @@
+// Package retentionsvc resolves each account's effective trash retention
+// window (instance default, overridden per user) and purges trashed
+// secrets once that window has elapsed.
+//
+// It depends on a TrashSource; see trashsvc for the concrete adapter over
+// file and text secrets' soft delete.
+package retentionsvc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/audit"
+	"github.com/andymarkow/gophkeeper/internal/repository/userrepo"
+)
+
+// TrashedSecret identifies one trashed secret awaiting purge.
+type TrashedSecret struct {
+	SecretType string // "file" or "text"
+	SecretID   string
+	UserID     string
+	TrashedAt  time.Time
+}
+
+// TrashSource lists and permanently removes trashed secrets. trashsvc
+// implements it over file and text secrets' soft delete.
+type TrashSource interface {
+	// ListTrashed returns every secret trashed before cutoff, across all
+	// users.
+	ListTrashed(ctx context.Context, cutoff time.Time) ([]TrashedSecret, error)
+	// PurgeTrashed permanently deletes the trashed secret identified by
+	// secretType and id, including its object storage content.
+	PurgeTrashed(ctx context.Context, secretType, id string) error
+}
+
+// Service resolves retention policy and purges secrets past it.
+type Service struct {
+	trash            TrashSource
+	users            userrepo.Storage
+	audit            audit.Logger
+	defaultRetention time.Duration
+}
+
+// NewService returns a Service that purges trash via source, using
+// defaultRetention for accounts without a TrashRetention override.
+func NewService(source TrashSource, users userrepo.Storage, auditLog audit.Logger, defaultRetention time.Duration) *Service {
+	return &Service{trash: source, users: users, audit: auditLog, defaultRetention: defaultRetention}
+}
+
+// EffectiveRetention returns userID's trash retention window: its override
+// if set, else the instance default.
+func (s *Service) EffectiveRetention(ctx context.Context, userID string) (time.Duration, error) {
+	u, err := s.users.GetUser(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("get user: %w", err)
+	}
+
+	if u.TrashRetention > 0 {
+		return u.TrashRetention, nil
+	}
+
+	return s.defaultRetention, nil
+}
+
+// PurgeExpired lists every secret trashed before now, keeps only those
+// whose owner's effective retention has actually elapsed (an owner's
+// override may be longer than the instance default), permanently deletes
+// them, and emits a retention.purged audit event per purge.
+func (s *Service) PurgeExpired(ctx context.Context) (int, error) {
+	candidates, err := s.trash.ListTrashed(ctx, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("list trashed secrets: %w", err)
+	}
+
+	purged := 0
+
+	for _, t := range candidates {
+		retention, err := s.EffectiveRetention(ctx, t.UserID)
+		if err != nil {
+			continue
+		}
+
+		age := time.Since(t.TrashedAt)
+		if age < retention {
+			continue
+		}
+
+		if err := s.trash.PurgeTrashed(ctx, t.SecretType, t.SecretID); err != nil {
+			return purged, fmt.Errorf("purge %s %s: %w", t.SecretType, t.SecretID, err)
+		}
+
+		purged++
+
+		s.audit.Log(ctx, audit.Event{
+			Action:  "retention.purged",
+			ActorID: "system",
+			Target:  fmt.Sprintf("%s:%s", t.SecretType, t.SecretID),
+			Detail:  fmt.Sprintf("purged after %s in trash (retention %s)", age.Round(time.Second), retention),
+		})
+	}
+
+	return purged, nil
+}
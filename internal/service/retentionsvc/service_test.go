@@ -0,0 +1,71 @@
+package retentionsvc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/audit"
+	"github.com/andymarkow/gophkeeper/internal/domain/user"
+	"github.com/andymarkow/gophkeeper/internal/repository/userrepo"
+	"github.com/andymarkow/gophkeeper/internal/service/retentionsvc"
+)
+
+type fakeTrashSource struct {
+	secrets []retentionsvc.TrashedSecret
+	purged  []string
+}
+
+func (f *fakeTrashSource) ListTrashed(context.Context, time.Time) ([]retentionsvc.TrashedSecret, error) {
+	return f.secrets, nil
+}
+
+func (f *fakeTrashSource) PurgeTrashed(_ context.Context, secretType, id string) error {
+	f.purged = append(f.purged, secretType+":"+id)
+
+	return nil
+}
+
+type noopAuditLogger struct{}
+
+func (noopAuditLogger) Log(context.Context, audit.Event) {}
+
+func TestPurgeExpired_RespectsPerUserOverride(t *testing.T) {
+	storage := userrepo.NewMemStorage()
+
+	// defaultOwner has no override, so the instance default (1h) applies.
+	defaultOwner := &user.User{ID: "user-default", Login: "default-owner"}
+	if err := storage.CreateUser(context.Background(), defaultOwner); err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+
+	// longRetentionOwner overrides the default with a much longer window,
+	// so its trashed secret must survive a purge pass that would delete
+	// defaultOwner's secret of the same age.
+	longRetentionOwner := &user.User{ID: "user-long", Login: "long-retention-owner", TrashRetention: 24 * time.Hour}
+	if err := storage.CreateUser(context.Background(), longRetentionOwner); err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+
+	trashedAt := time.Now().Add(-2 * time.Hour)
+
+	source := &fakeTrashSource{secrets: []retentionsvc.TrashedSecret{
+		{SecretType: "file", SecretID: "f1", UserID: defaultOwner.ID, TrashedAt: trashedAt},
+		{SecretType: "text", SecretID: "t1", UserID: longRetentionOwner.ID, TrashedAt: trashedAt},
+	}}
+
+	svc := retentionsvc.NewService(source, storage, noopAuditLogger{}, time.Hour)
+
+	purged, err := svc.PurgeExpired(context.Background())
+	if err != nil {
+		t.Fatalf("PurgeExpired() error = %v", err)
+	}
+
+	if purged != 1 {
+		t.Fatalf("PurgeExpired() purged = %d, want 1", purged)
+	}
+
+	if len(source.purged) != 1 || source.purged[0] != "file:f1" {
+		t.Fatalf("PurgeExpired() purged secrets = %v, want [file:f1]", source.purged)
+	}
+}
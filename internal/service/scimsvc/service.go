@@ -0,0 +1,152 @@
+// Package scimsvc maps a SCIM 2.0 Users resource onto userrepo, so an
+// enterprise identity provider can provision and deprovision gophkeeper
+// accounts automatically.
+package scimsvc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/user"
+	"github.com/andymarkow/gophkeeper/internal/passwordhash"
+	"github.com/andymarkow/gophkeeper/internal/repository/userrepo"
+)
+
+// ErrNotFound is returned when the referenced SCIM resource doesn't exist.
+var ErrNotFound = userrepo.ErrUserNotFound
+
+// ErrConflict is returned when provisioning would collide with an existing
+// account's userName.
+var ErrConflict = userrepo.ErrLoginAlreadyExists
+
+// User is the subset of a SCIM User resource gophkeeper maps onto an
+// account: its id, userName (login), active flag, and email.
+type User struct {
+	ID       string
+	UserName string
+	Email    string
+	Active   bool
+}
+
+// Service implements SCIM provisioning against userrepo.Storage.
+type Service struct {
+	storage  userrepo.Storage
+	tenantID string
+	hasher   *passwordhash.Hasher
+}
+
+// NewService returns a Service backed by storage, provisioning every
+// account under tenantID. A SCIM integration is configured per identity
+// provider, and in multi-tenancy deployments each identity provider belongs
+// to exactly one tenant; tenantID is empty for single-tenant deployments.
+// bcryptCost configures the cost used to hash each provisioned account's
+// random password (0 for passwordhash.DefaultCost).
+func NewService(storage userrepo.Storage, tenantID string, bcryptCost int) *Service {
+	return &Service{storage: storage, tenantID: tenantID, hasher: passwordhash.NewHasher(bcryptCost)}
+}
+
+// Create provisions a new account for the given SCIM User. The account is
+// created with a random password, since SCIM provisioning carries no
+// credential; the user authenticates via their identity provider's SSO flow
+// or sets a password afterward.
+func (s *Service) Create(ctx context.Context, in User) (User, error) {
+	password, err := randomPassword()
+	if err != nil {
+		return User{}, fmt.Errorf("generate password: %w", err)
+	}
+
+	hashed, err := s.hasher.Hash(password)
+	if err != nil {
+		return User{}, err
+	}
+
+	id, err := randomID()
+	if err != nil {
+		return User{}, fmt.Errorf("generate id: %w", err)
+	}
+
+	u := &user.User{
+		ID:               id,
+		Login:            in.UserName,
+		HashedPassword:   hashed,
+		Email:            in.Email,
+		NotifyOnNewLogin: true,
+		Disabled:         !in.Active,
+		TenantID:         s.tenantID,
+	}
+
+	if err := s.storage.CreateUser(ctx, u); err != nil {
+		return User{}, err
+	}
+
+	return toSCIM(u), nil
+}
+
+// Get returns the SCIM User for id.
+func (s *Service) Get(ctx context.Context, id string) (User, error) {
+	u, err := s.storage.GetUser(ctx, id)
+	if err != nil {
+		return User{}, err
+	}
+
+	return toSCIM(u), nil
+}
+
+// Replace overwrites the mutable SCIM-visible fields of the account with id.
+func (s *Service) Replace(ctx context.Context, id string, in User) (User, error) {
+	existing, err := s.storage.GetUser(ctx, id)
+	if err != nil {
+		return User{}, err
+	}
+
+	existing.Login = in.UserName
+	existing.Email = in.Email
+
+	if err := s.storage.UpdateUser(ctx, existing); err != nil {
+		return User{}, err
+	}
+
+	if err := s.storage.SetDisabled(ctx, id, !in.Active); err != nil {
+		return User{}, err
+	}
+
+	existing.Disabled = !in.Active
+
+	return toSCIM(existing), nil
+}
+
+// Deprovision soft-disables the account with id, as SCIM DELETE. The
+// account's data is retained; only authentication is blocked.
+func (s *Service) Deprovision(ctx context.Context, id string) error {
+	err := s.storage.SetDisabled(ctx, id, true)
+	if errors.Is(err, userrepo.ErrUserNotFound) {
+		return ErrNotFound
+	}
+
+	return err
+}
+
+func toSCIM(u *user.User) User {
+	return User{ID: u.ID, UserName: u.Login, Email: u.Email, Active: !u.Disabled}
+}
+
+func randomID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
+
+func randomPassword() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
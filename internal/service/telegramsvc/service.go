@@ -0,0 +1,63 @@
+// Package telegramsvc links gophkeeper accounts to Telegram chats so users
+// can retrieve secrets via bot commands.
+package telegramsvc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/repository/telegramrepo"
+)
+
+// codeTTL is how long a generated link code remains valid.
+const codeTTL = 10 * time.Minute
+
+// Service manages Telegram account linking.
+type Service struct {
+	storage telegramrepo.Storage
+}
+
+// NewService returns a Service backed by storage.
+func NewService(storage telegramrepo.Storage) *Service {
+	return &Service{storage: storage}
+}
+
+// GenerateLinkCode creates a short-lived code the user enters into the bot
+// with /link <code> to associate their Telegram chat with userID.
+func (s *Service) GenerateLinkCode(ctx context.Context, userID string) (string, error) {
+	buf := make([]byte, 5)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate code: %w", err)
+	}
+
+	code := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(buf)
+
+	if err := s.storage.CreateCode(ctx, code, userID, time.Now().Add(codeTTL)); err != nil {
+		return "", fmt.Errorf("store link code: %w", err)
+	}
+
+	return code, nil
+}
+
+// CompleteLink consumes code and binds chatID to the account it was issued
+// for, returning the linked userID.
+func (s *Service) CompleteLink(ctx context.Context, chatID int64, code string) (string, error) {
+	userID, err := s.storage.ConsumeCode(ctx, code)
+	if err != nil {
+		return "", fmt.Errorf("consume link code: %w", err)
+	}
+
+	if err := s.storage.BindChat(ctx, chatID, userID); err != nil {
+		return "", fmt.Errorf("bind chat: %w", err)
+	}
+
+	return userID, nil
+}
+
+// UserForChat returns the account linked to chatID.
+func (s *Service) UserForChat(ctx context.Context, chatID int64) (string, error) {
+	return s.storage.UserForChat(ctx, chatID)
+}
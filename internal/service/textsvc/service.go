@@ -0,0 +1,456 @@
+// Package textsvc implements text secret CRUD plus an opt-in, server-side
+// blind search index over secret contents, centrally enforcing ownership
+// and the per-secret ACL.
+package textsvc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/cryptutils"
+	"github.com/andymarkow/gophkeeper/internal/domain/acl"
+	"github.com/andymarkow/gophkeeper/internal/domain/contentinfo"
+	"github.com/andymarkow/gophkeeper/internal/domain/metadata"
+	"github.com/andymarkow/gophkeeper/internal/domain/text"
+	"github.com/andymarkow/gophkeeper/internal/lock"
+	"github.com/andymarkow/gophkeeper/internal/objrepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/searchindexrepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/textrepo"
+	"github.com/andymarkow/gophkeeper/internal/repository/userrepo"
+	"github.com/andymarkow/gophkeeper/internal/searchindex"
+)
+
+// ErrAccessDenied is returned when the caller is neither the secret's owner
+// nor granted access via its ACL.
+var ErrAccessDenied = errors.New("access denied")
+
+// ErrSearchDisabled is returned by Search when the caller has not opted
+// into search indexing.
+var ErrSearchDisabled = errors.New("search index not enabled for this account")
+
+// ErrSecretLimitExceeded is returned by Create when userID already owns
+// maxSecrets text secrets.
+var ErrSecretLimitExceeded = errors.New("text secret limit exceeded for this account")
+
+// ErrNotTrashed is returned by Restore when the secret isn't currently
+// trashed.
+var ErrNotTrashed = errors.New("text secret is not trashed")
+
+// Service implements text secret operations.
+type Service struct {
+	storage textrepo.Storage
+	objects objrepo.Storage
+	keyring *cryptutils.Keyring
+	users   userrepo.Storage
+	index   searchindexrepo.Storage
+	// uploadLocks serializes re-uploads to the same secret ID, so two
+	// concurrent Replace calls can't interleave and leave mismatched
+	// salt/IV/checksum metadata.
+	uploadLocks *lock.KeyedMutex
+	maxSecrets  int
+}
+
+// NewService returns a Service backed by storage and objects, encrypting
+// content under keyring's primary key. users and index back the opt-in
+// search feature: a secret is indexed only for users with
+// User.SearchIndexEnabled set. maxSecrets caps how many text secrets a
+// single user may own; 0 disables the cap.
+func NewService(storage textrepo.Storage, objects objrepo.Storage, keyring *cryptutils.Keyring,
+	users userrepo.Storage, index searchindexrepo.Storage, maxSecrets int,
+) *Service {
+	return &Service{
+		storage: storage, objects: objects, keyring: keyring, users: users, index: index,
+		uploadLocks: lock.NewKeyedMutex(), maxSecrets: maxSecrets,
+	}
+}
+
+// Create stores a new text secret owned by userID, encrypting content under
+// the keyring's primary key. If the user has opted into search indexing,
+// content's words are blind-indexed alongside it. Fails with
+// ErrSecretLimitExceeded if userID already owns the configured maximum.
+func (s *Service) Create(ctx context.Context, userID, name, content string) (*text.Secret, error) {
+	if s.maxSecrets > 0 {
+		existing, err := s.storage.List(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("list text secrets: %w", err)
+		}
+
+		if len(existing) >= s.maxSecrets {
+			return nil, ErrSecretLimitExceeded
+		}
+	}
+
+	id, err := randomID()
+	if err != nil {
+		return nil, fmt.Errorf("generate id: %w", err)
+	}
+
+	objKey := fmt.Sprintf("%s/%s/%s", userID, "texts", id)
+
+	pr, pw := io.Pipe()
+
+	go func() {
+		pw.CloseWithError(cryptutils.EncryptStream(pw, strings.NewReader(content), s.keyring.PrimaryKey(), contentinfo.CompressionNone))
+	}()
+
+	size := int64(len(content)) + cryptutils.IVSize
+
+	if err := s.objects.Put(ctx, objKey, pr, size, objrepo.Tags{UserID: userID, SecretID: id, SecretType: "text"}); err != nil {
+		return nil, fmt.Errorf("put object: %w", err)
+	}
+
+	sum := sha256.Sum256([]byte(content))
+
+	sec := &text.Secret{
+		ID:     id,
+		UserID: userID,
+		Name:   name,
+		Content: contentinfo.ContentInfo{
+			ObjectKey: objKey,
+			Size:      int64(len(content)),
+			Checksum:  contentinfo.NewChecksum(contentinfo.ChecksumSHA256, hex.EncodeToString(sum[:])),
+			KeyID:     s.keyring.PrimaryKeyID(),
+		},
+	}
+
+	if err := s.storage.Create(ctx, sec); err != nil {
+		return nil, fmt.Errorf("create text secret: %w", err)
+	}
+
+	if err := s.reindex(ctx, userID, id, content); err != nil {
+		return nil, fmt.Errorf("index text secret: %w", err)
+	}
+
+	return sec, nil
+}
+
+// Get returns userID's text secret id's decrypted content, if userID is its
+// owner or has been granted read access via its ACL.
+func (s *Service) Get(ctx context.Context, userID, id string) (*text.Secret, string, error) {
+	sec, err := s.storage.Get(ctx, id)
+	if err != nil {
+		return nil, "", fmt.Errorf("get text secret: %w", err)
+	}
+
+	if !canAccess(sec, userID, acl.PermissionRead) {
+		return nil, "", ErrAccessDenied
+	}
+
+	content, err := s.decrypt(ctx, sec)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return sec, content, nil
+}
+
+// Replace re-encrypts and re-uploads userID's text secret id's content, if
+// userID is its owner or has been granted write access via its ACL. The new
+// content is written to a fresh object key before the secret's metadata is
+// atomically swapped to point at it, so a failed or partial upload never
+// corrupts the existing content; the old object is deleted, and the search
+// index re-synced, only after the swap succeeds.
+func (s *Service) Replace(ctx context.Context, userID, id, content string) (*text.Secret, error) {
+	s.uploadLocks.Lock(id)
+	defer s.uploadLocks.Unlock(id)
+
+	sec, err := s.storage.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get text secret: %w", err)
+	}
+
+	if !canAccess(sec, userID, acl.PermissionWrite) {
+		return nil, ErrAccessDenied
+	}
+
+	newID, err := randomID()
+	if err != nil {
+		return nil, fmt.Errorf("generate id: %w", err)
+	}
+
+	newObjKey := fmt.Sprintf("%s/%s/%s", userID, "texts", newID)
+
+	pr, pw := io.Pipe()
+
+	go func() {
+		pw.CloseWithError(cryptutils.EncryptStream(pw, strings.NewReader(content), s.keyring.PrimaryKey(), contentinfo.CompressionNone))
+	}()
+
+	size := int64(len(content)) + cryptutils.IVSize
+
+	if err := s.objects.Put(ctx, newObjKey, pr, size, objrepo.Tags{UserID: userID, SecretID: id, SecretType: "text"}); err != nil {
+		return nil, fmt.Errorf("put object: %w", err)
+	}
+
+	oldObjKey := sec.Content.ObjectKey
+	sum := sha256.Sum256([]byte(content))
+
+	updated := *sec
+	updated.Content = contentinfo.ContentInfo{
+		ObjectKey: newObjKey,
+		Size:      int64(len(content)),
+		Checksum:  contentinfo.NewChecksum(contentinfo.ChecksumSHA256, hex.EncodeToString(sum[:])),
+		KeyID:     s.keyring.PrimaryKeyID(),
+	}
+
+	if err := s.storage.Update(ctx, &updated); err != nil {
+		if delErr := s.objects.Delete(ctx, newObjKey); delErr != nil {
+			return nil, fmt.Errorf("update text secret: %w (and cleanup failed: %w)", err, delErr)
+		}
+
+		return nil, fmt.Errorf("update text secret: %w", err)
+	}
+
+	if err := s.objects.Delete(ctx, oldObjKey); err != nil {
+		return nil, fmt.Errorf("delete old object: %w", err)
+	}
+
+	if err := s.reindex(ctx, userID, id, content); err != nil {
+		return nil, fmt.Errorf("index text secret: %w", err)
+	}
+
+	return &updated, nil
+}
+
+// Delete moves the secret with id into the trash and removes its search
+// index entry, if userID is its owner. ACL grants do not extend to
+// deletion. Its object storage content is left in place until
+// retentionsvc.PurgeExpired permanently removes it (see PurgeTrashed);
+// Restore undoes this before that happens.
+func (s *Service) Delete(ctx context.Context, userID, id string) error {
+	sec, err := s.storage.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get text secret: %w", err)
+	}
+
+	if sec.UserID != userID {
+		return ErrAccessDenied
+	}
+
+	if err := s.storage.Trash(ctx, id, time.Now()); err != nil {
+		return fmt.Errorf("trash text secret: %w", err)
+	}
+
+	if err := s.index.Delete(ctx, userID, id); err != nil {
+		return fmt.Errorf("delete index entry: %w", err)
+	}
+
+	return nil
+}
+
+// Restore takes userID's text secret id out of the trash and restores its
+// search index entry (if the user has search indexing enabled), if userID
+// is its owner and it is currently trashed.
+func (s *Service) Restore(ctx context.Context, userID, id string) error {
+	sec, err := s.storage.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get text secret: %w", err)
+	}
+
+	if sec.UserID != userID {
+		return ErrAccessDenied
+	}
+
+	if sec.DeletedAt == nil {
+		return ErrNotTrashed
+	}
+
+	if err := s.storage.Restore(ctx, id); err != nil {
+		return fmt.Errorf("restore text secret: %w", err)
+	}
+
+	content, err := s.decrypt(ctx, sec)
+	if err != nil {
+		return fmt.Errorf("decrypt text secret: %w", err)
+	}
+
+	if err := s.reindex(ctx, userID, id, content); err != nil {
+		return fmt.Errorf("index text secret: %w", err)
+	}
+
+	return nil
+}
+
+// SetHighSecurity marks or clears the secret's step-up gate (see
+// metadata.MarkHighSecurity), if userID is its owner or has been granted
+// write access via its ACL.
+func (s *Service) SetHighSecurity(ctx context.Context, userID, id string, enabled bool) error {
+	sec, err := s.storage.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get text secret: %w", err)
+	}
+
+	if !canAccess(sec, userID, acl.PermissionWrite) {
+		return ErrAccessDenied
+	}
+
+	metadata.MarkHighSecurity(&sec.Metadata, enabled)
+
+	if err := s.storage.Update(ctx, sec); err != nil {
+		return fmt.Errorf("update text secret: %w", err)
+	}
+
+	return nil
+}
+
+// List returns every text secret owned by userID, excluding trashed ones.
+func (s *Service) List(ctx context.Context, userID string) ([]*text.Secret, error) {
+	secrets, err := s.storage.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list text secrets: %w", err)
+	}
+
+	return secrets, nil
+}
+
+// ListTrashed returns userID's trashed text secrets.
+func (s *Service) ListTrashed(ctx context.Context, userID string) ([]*text.Secret, error) {
+	secrets, err := s.storage.ListTrashed(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list trashed text secrets: %w", err)
+	}
+
+	return secrets, nil
+}
+
+// ListAllTrashed returns every text secret, across all users, trashed
+// before cutoff. It backs retentionsvc's background purge via trashsvc.
+func (s *Service) ListAllTrashed(ctx context.Context, cutoff time.Time) ([]*text.Secret, error) {
+	secrets, err := s.storage.ListAllTrashed(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list trashed text secrets: %w", err)
+	}
+
+	out := make([]*text.Secret, 0, len(secrets))
+	for _, sec := range secrets {
+		if sec.DeletedAt != nil && sec.DeletedAt.Before(cutoff) {
+			out = append(out, sec)
+		}
+	}
+
+	return out, nil
+}
+
+// PurgeTrashed permanently deletes the trashed text secret id, including
+// its object storage content. Unlike Delete, it does not check ownership:
+// it is only ever called by retentionsvc's background purge (via
+// trashsvc) against a secret it already found in the trash.
+func (s *Service) PurgeTrashed(ctx context.Context, id string) error {
+	sec, err := s.storage.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get text secret: %w", err)
+	}
+
+	if err := s.objects.Delete(ctx, sec.Content.ObjectKey); err != nil {
+		return fmt.Errorf("delete object: %w", err)
+	}
+
+	if err := s.storage.Delete(ctx, id); err != nil {
+		return fmt.Errorf("delete text secret: %w", err)
+	}
+
+	if err := s.index.Delete(ctx, sec.UserID, id); err != nil {
+		return fmt.Errorf("delete index entry: %w", err)
+	}
+
+	return nil
+}
+
+// Search returns userID's text secrets whose indexed content matches every
+// word in query. It fails with ErrSearchDisabled unless the user has
+// opted into search indexing via User.SearchIndexEnabled.
+func (s *Service) Search(ctx context.Context, userID, query string) ([]*text.Secret, error) {
+	u, err := s.users.GetUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+
+	if !u.SearchIndexEnabled {
+		return nil, ErrSearchDisabled
+	}
+
+	tokens := searchindex.BlindTokens(s.keyring.PrimaryKey(), searchindex.Tokenize(query))
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	ids, err := s.index.Search(ctx, userID, tokens)
+	if err != nil {
+		return nil, fmt.Errorf("search index: %w", err)
+	}
+
+	secrets := make([]*text.Secret, 0, len(ids))
+
+	for _, id := range ids {
+		sec, err := s.storage.Get(ctx, id)
+		if err != nil {
+			continue
+		}
+
+		secrets = append(secrets, sec)
+	}
+
+	return secrets, nil
+}
+
+// reindex updates a secret's blind index entry to match the user's current
+// opt-in state: indexed when enabled, removed when not (or when content is
+// empty).
+func (s *Service) reindex(ctx context.Context, userID, id, content string) error {
+	u, err := s.users.GetUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get user: %w", err)
+	}
+
+	if !u.SearchIndexEnabled {
+		return s.index.Delete(ctx, userID, id)
+	}
+
+	tokens := searchindex.BlindTokens(s.keyring.PrimaryKey(), searchindex.Tokenize(content))
+
+	return s.index.Index(ctx, userID, id, tokens)
+}
+
+func (s *Service) decrypt(ctx context.Context, sec *text.Secret) (string, error) {
+	key, err := s.keyring.Key(sec.Content.KeyID)
+	if err != nil {
+		return "", fmt.Errorf("select decryption key: %w", err)
+	}
+
+	obj, err := s.objects.Get(ctx, sec.Content.ObjectKey)
+	if err != nil {
+		return "", fmt.Errorf("get object: %w", err)
+	}
+	defer obj.Close()
+
+	var buf strings.Builder
+	if err := cryptutils.DecryptStream(&buf, obj, key, contentinfo.CompressionNone); err != nil {
+		return "", fmt.Errorf("decrypt object: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+func canAccess(sec *text.Secret, userID string, perm acl.Permission) bool {
+	if sec.UserID == userID {
+		return true
+	}
+
+	return sec.ACL.Allows(acl.UserPrincipal(userID), perm)
+}
+
+func randomID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
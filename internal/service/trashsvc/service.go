@@ -0,0 +1,88 @@
+// Package trashsvc adapts file and text secret trash listing/purging into
+// retentionsvc.TrashSource, so retentionsvc doesn't need to know about
+// either secret type directly.
+package trashsvc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/file"
+	"github.com/andymarkow/gophkeeper/internal/domain/text"
+	"github.com/andymarkow/gophkeeper/internal/service/retentionsvc"
+)
+
+// secretTypeFile and secretTypeText are the retentionsvc.TrashedSecret
+// SecretType values this package produces and accepts.
+const (
+	secretTypeFile = "file"
+	secretTypeText = "text"
+)
+
+// fileTrash is the subset of filesvc.Service trashsvc depends on.
+type fileTrash interface {
+	ListAllTrashed(ctx context.Context, cutoff time.Time) ([]*file.Secret, error)
+	PurgeTrashed(ctx context.Context, id string) error
+}
+
+// textTrash is the subset of textsvc.Service trashsvc depends on.
+type textTrash interface {
+	ListAllTrashed(ctx context.Context, cutoff time.Time) ([]*text.Secret, error)
+	PurgeTrashed(ctx context.Context, id string) error
+}
+
+// Service implements retentionsvc.TrashSource across file and text
+// secrets.
+type Service struct {
+	files fileTrash
+	texts textTrash
+}
+
+// NewService returns a Service backed by files and texts.
+func NewService(files fileTrash, texts textTrash) *Service {
+	return &Service{files: files, texts: texts}
+}
+
+// ListTrashed returns every file and text secret, across all users,
+// trashed before cutoff.
+func (s *Service) ListTrashed(ctx context.Context, cutoff time.Time) ([]retentionsvc.TrashedSecret, error) {
+	var out []retentionsvc.TrashedSecret
+
+	files, err := s.files.ListAllTrashed(ctx, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("list trashed file secrets: %w", err)
+	}
+
+	for _, sec := range files {
+		out = append(out, retentionsvc.TrashedSecret{
+			SecretType: secretTypeFile, SecretID: sec.ID, UserID: sec.UserID, TrashedAt: *sec.DeletedAt,
+		})
+	}
+
+	texts, err := s.texts.ListAllTrashed(ctx, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("list trashed text secrets: %w", err)
+	}
+
+	for _, sec := range texts {
+		out = append(out, retentionsvc.TrashedSecret{
+			SecretType: secretTypeText, SecretID: sec.ID, UserID: sec.UserID, TrashedAt: *sec.DeletedAt,
+		})
+	}
+
+	return out, nil
+}
+
+// PurgeTrashed permanently deletes the trashed secret identified by
+// secretType and id.
+func (s *Service) PurgeTrashed(ctx context.Context, secretType, id string) error {
+	switch secretType {
+	case secretTypeFile:
+		return s.files.PurgeTrashed(ctx, id)
+	case secretTypeText:
+		return s.texts.PurgeTrashed(ctx, id)
+	default:
+		return fmt.Errorf("trashsvc: unknown secret type %q", secretType)
+	}
+}
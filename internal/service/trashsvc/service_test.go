@@ -0,0 +1,102 @@
+package trashsvc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/file"
+	"github.com/andymarkow/gophkeeper/internal/domain/text"
+	"github.com/andymarkow/gophkeeper/internal/service/trashsvc"
+)
+
+type fakeFileTrash struct {
+	trashed []*file.Secret
+	purged  []string
+}
+
+func (f *fakeFileTrash) ListAllTrashed(context.Context, time.Time) ([]*file.Secret, error) {
+	return f.trashed, nil
+}
+
+func (f *fakeFileTrash) PurgeTrashed(_ context.Context, id string) error {
+	f.purged = append(f.purged, id)
+
+	return nil
+}
+
+type fakeTextTrash struct {
+	trashed []*text.Secret
+	purged  []string
+}
+
+func (f *fakeTextTrash) ListAllTrashed(context.Context, time.Time) ([]*text.Secret, error) {
+	return f.trashed, nil
+}
+
+func (f *fakeTextTrash) PurgeTrashed(_ context.Context, id string) error {
+	f.purged = append(f.purged, id)
+
+	return nil
+}
+
+func TestListTrashed_CombinesFileAndTextSecrets(t *testing.T) {
+	deletedAt := time.Now()
+
+	files := &fakeFileTrash{trashed: []*file.Secret{
+		{ID: "f1", UserID: "user-1", DeletedAt: &deletedAt},
+	}}
+	texts := &fakeTextTrash{trashed: []*text.Secret{
+		{ID: "t1", UserID: "user-2", DeletedAt: &deletedAt},
+	}}
+
+	svc := trashsvc.NewService(files, texts)
+
+	got, err := svc.ListTrashed(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("ListTrashed() error = %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("ListTrashed() returned %d secrets, want 2", len(got))
+	}
+
+	if got[0].SecretType != "file" || got[0].SecretID != "f1" || got[0].UserID != "user-1" {
+		t.Fatalf("ListTrashed()[0] = %+v, want file secret f1 owned by user-1", got[0])
+	}
+
+	if got[1].SecretType != "text" || got[1].SecretID != "t1" || got[1].UserID != "user-2" {
+		t.Fatalf("ListTrashed()[1] = %+v, want text secret t1 owned by user-2", got[1])
+	}
+}
+
+func TestPurgeTrashed_DispatchesBySecretType(t *testing.T) {
+	files := &fakeFileTrash{}
+	texts := &fakeTextTrash{}
+
+	svc := trashsvc.NewService(files, texts)
+
+	if err := svc.PurgeTrashed(context.Background(), "file", "f1"); err != nil {
+		t.Fatalf("PurgeTrashed(file) error = %v", err)
+	}
+
+	if err := svc.PurgeTrashed(context.Background(), "text", "t1"); err != nil {
+		t.Fatalf("PurgeTrashed(text) error = %v", err)
+	}
+
+	if len(files.purged) != 1 || files.purged[0] != "f1" {
+		t.Fatalf("files.purged = %v, want [f1]", files.purged)
+	}
+
+	if len(texts.purged) != 1 || texts.purged[0] != "t1" {
+		t.Fatalf("texts.purged = %v, want [t1]", texts.purged)
+	}
+}
+
+func TestPurgeTrashed_RejectsUnknownSecretType(t *testing.T) {
+	svc := trashsvc.NewService(&fakeFileTrash{}, &fakeTextTrash{})
+
+	if err := svc.PurgeTrashed(context.Background(), "bankcard", "b1"); err == nil {
+		t.Fatalf("PurgeTrashed(bankcard) error = nil, want non-nil")
+	}
+}
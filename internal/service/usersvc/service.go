@@ -0,0 +1,110 @@
+// Package usersvc implements user profile and account operations on top of
+// userrepo.Storage.
+package usersvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/andymarkow/gophkeeper/internal/domain/user"
+	"github.com/andymarkow/gophkeeper/internal/passwordhash"
+	"github.com/andymarkow/gophkeeper/internal/repository/userrepo"
+)
+
+// ErrInvalidPassword is returned by ChangePassword when oldPassword does not
+// match the account's current hashed password.
+var ErrInvalidPassword = errors.New("invalid password")
+
+// Service provides user account operations.
+type Service struct {
+	storage userrepo.Storage
+	hasher  *passwordhash.Hasher
+}
+
+// NewService returns a Service backed by the given storage, hashing new
+// passwords at bcryptCost (0 for passwordhash.DefaultCost).
+func NewService(storage userrepo.Storage, bcryptCost int) *Service {
+	return &Service{storage: storage, hasher: passwordhash.NewHasher(bcryptCost)}
+}
+
+// Profile is the user information safe to return to clients and admins.
+type Profile struct {
+	ID          string
+	Login       string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	LastLoginAt *time.Time
+}
+
+// GetProfile returns the profile for the given user ID.
+func (s *Service) GetProfile(ctx context.Context, userID string) (*Profile, error) {
+	u, err := s.storage.GetUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+
+	return toProfile(u), nil
+}
+
+// RecordLogin stamps the user's last_login_at to now, called after a
+// successful authentication.
+func (s *Service) RecordLogin(ctx context.Context, userID string) error {
+	if err := s.storage.UpdateLastLogin(ctx, userID, time.Now()); err != nil {
+		return fmt.Errorf("update last login: %w", err)
+	}
+
+	return nil
+}
+
+// ChangePassword verifies oldPassword against the stored hash, persists the
+// hash of newPassword, and bumps the user's token version so every session
+// token issued before the change stops working.
+func (s *Service) ChangePassword(ctx context.Context, userID, oldPassword, newPassword string) error {
+	u, err := s.storage.GetUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get user: %w", err)
+	}
+
+	if err := s.hasher.Compare(u.HashedPassword, oldPassword); err != nil {
+		return ErrInvalidPassword
+	}
+
+	hashed, err := s.hasher.Hash(newPassword)
+	if err != nil {
+		return err
+	}
+
+	u.HashedPassword = hashed
+
+	if err := s.storage.UpdateUser(ctx, u); err != nil {
+		return fmt.Errorf("update user: %w", err)
+	}
+
+	if err := s.storage.BumpTokenVersion(ctx, userID); err != nil {
+		return fmt.Errorf("bump token version: %w", err)
+	}
+
+	return nil
+}
+
+// LogoutAll invalidates every session token previously issued to userID,
+// without changing the password.
+func (s *Service) LogoutAll(ctx context.Context, userID string) error {
+	if err := s.storage.BumpTokenVersion(ctx, userID); err != nil {
+		return fmt.Errorf("bump token version: %w", err)
+	}
+
+	return nil
+}
+
+func toProfile(u *user.User) *Profile {
+	return &Profile{
+		ID:          u.ID,
+		Login:       u.Login,
+		CreatedAt:   u.CreatedAt,
+		UpdatedAt:   u.UpdatedAt,
+		LastLoginAt: u.LastLoginAt,
+	}
+}
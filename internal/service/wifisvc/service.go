@@ -0,0 +1,242 @@
+// Package wifisvc implements Wi-Fi network secret CRUD and renders the
+// standard Wi-Fi QR provisioning payload, centrally enforcing ownership and
+// the per-secret ACL.
+package wifisvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/andymarkow/gophkeeper/internal/cryptutils"
+	"github.com/andymarkow/gophkeeper/internal/domain/acl"
+	"github.com/andymarkow/gophkeeper/internal/domain/wifi"
+	"github.com/andymarkow/gophkeeper/internal/repository/wifirepo"
+)
+
+// ErrAccessDenied is returned when the caller is neither the secret's owner
+// nor granted access via its ACL.
+var ErrAccessDenied = errors.New("access denied")
+
+// ErrSecretLimitExceeded is returned by Create when userID already owns
+// maxSecrets Wi-Fi secrets.
+var ErrSecretLimitExceeded = errors.New("wifi secret limit exceeded for this account")
+
+// Service implements Wi-Fi network secret operations.
+type Service struct {
+	storage    wifirepo.Storage
+	keyring    *cryptutils.Keyring
+	maxSecrets int
+}
+
+// NewService returns a Service backed by storage, decrypting passwords with
+// keyring. maxSecrets caps how many Wi-Fi secrets a single user may own; 0
+// disables the cap.
+func NewService(storage wifirepo.Storage, keyring *cryptutils.Keyring, maxSecrets int) *Service {
+	return &Service{storage: storage, keyring: keyring, maxSecrets: maxSecrets}
+}
+
+// EncryptPassword encrypts password under the keyring's primary key,
+// returning the ciphertext and key ID to store on a Secret.
+func (s *Service) EncryptPassword(password string) (ciphertext []byte, keyID string, err error) {
+	ciphertext, err = cryptutils.Encrypt([]byte(password), s.keyring.PrimaryKey())
+	if err != nil {
+		return nil, "", fmt.Errorf("encrypt password: %w", err)
+	}
+
+	return ciphertext, s.keyring.PrimaryKeyID(), nil
+}
+
+// DecryptPassword decrypts sec's Password in full, returning "" without
+// error for a "nopass" network, which stores no password to decrypt.
+func (s *Service) DecryptPassword(sec *wifi.Secret) (string, error) {
+	if sec.Security == "nopass" {
+		return "", nil
+	}
+
+	key, err := s.keyring.Key(sec.KeyID)
+	if err != nil {
+		return "", fmt.Errorf("select decryption key: %w", err)
+	}
+
+	plaintext, err := cryptutils.Decrypt(sec.Password, key)
+	if err != nil {
+		return "", fmt.Errorf("decrypt password: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+// Create stores a new secret owned by userID. Fails with
+// ErrSecretLimitExceeded if userID already owns the configured maximum.
+func (s *Service) Create(ctx context.Context, userID string, sec *wifi.Secret) error {
+	if s.maxSecrets > 0 {
+		existing, err := s.storage.List(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("list wifi secrets: %w", err)
+		}
+
+		if len(existing) >= s.maxSecrets {
+			return ErrSecretLimitExceeded
+		}
+	}
+
+	sec.UserID = userID
+
+	if sec.Security == "" {
+		sec.Security = "WPA"
+	}
+
+	if err := s.storage.Create(ctx, sec); err != nil {
+		return fmt.Errorf("create wifi secret: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns the secret with id if userID is its owner or has been granted
+// read access via its ACL.
+func (s *Service) Get(ctx context.Context, userID, id string) (*wifi.Secret, error) {
+	sec, err := s.storage.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get wifi secret: %w", err)
+	}
+
+	if !canAccess(sec, userID, acl.PermissionRead) {
+		return nil, ErrAccessDenied
+	}
+
+	return sec, nil
+}
+
+// Update persists changes to sec if userID is its owner or has been granted
+// write access via its ACL.
+func (s *Service) Update(ctx context.Context, userID string, sec *wifi.Secret) error {
+	existing, err := s.storage.Get(ctx, sec.ID)
+	if err != nil {
+		return fmt.Errorf("get wifi secret: %w", err)
+	}
+
+	if !canAccess(existing, userID, acl.PermissionWrite) {
+		return ErrAccessDenied
+	}
+
+	sec.UserID = existing.UserID
+
+	if err := s.storage.Update(ctx, sec); err != nil {
+		return fmt.Errorf("update wifi secret: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the secret with id if userID is its owner. ACL grants do
+// not extend to deletion, only the owner can delete.
+func (s *Service) Delete(ctx context.Context, userID, id string) error {
+	existing, err := s.storage.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get wifi secret: %w", err)
+	}
+
+	if existing.UserID != userID {
+		return ErrAccessDenied
+	}
+
+	if err := s.storage.Delete(ctx, id); err != nil {
+		return fmt.Errorf("delete wifi secret: %w", err)
+	}
+
+	return nil
+}
+
+// List returns every secret owned by userID. It does not include secrets
+// merely shared with userID via ACL.
+func (s *Service) List(ctx context.Context, userID string) ([]*wifi.Secret, error) {
+	secrets, err := s.storage.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list wifi secrets: %w", err)
+	}
+
+	return secrets, nil
+}
+
+// ProvisioningPayload returns the secret's standard Wi-Fi QR payload string
+// ("WIFI:T:...;S:...;P:...;H:...;;"), if userID is its owner or has been
+// granted read access via its ACL, for a client to render as a QR code that
+// phone cameras can scan to join the network directly.
+func (s *Service) ProvisioningPayload(ctx context.Context, userID, id string) (string, error) {
+	sec, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return "", err
+	}
+
+	var password string
+
+	if sec.Security != "nopass" {
+		key, err := s.keyring.Key(sec.KeyID)
+		if err != nil {
+			return "", fmt.Errorf("select decryption key: %w", err)
+		}
+
+		plaintext, err := cryptutils.Decrypt(sec.Password, key)
+		if err != nil {
+			return "", fmt.Errorf("decrypt password: %w", err)
+		}
+
+		password = string(plaintext)
+	}
+
+	return BuildProvisioningPayload(sec.SSID, sec.Security, password, sec.Hidden), nil
+}
+
+// BuildProvisioningPayload renders the standard Wi-Fi QR payload format
+// understood by iOS and Android camera apps.
+func BuildProvisioningPayload(ssid, security, password string, hidden bool) string {
+	var b strings.Builder
+
+	b.WriteString("WIFI:T:")
+	b.WriteString(escapeQRField(security))
+	b.WriteString(";S:")
+	b.WriteString(escapeQRField(ssid))
+
+	if security != "nopass" {
+		b.WriteString(";P:")
+		b.WriteString(escapeQRField(password))
+	}
+
+	if hidden {
+		b.WriteString(";H:true")
+	}
+
+	b.WriteString(";;")
+
+	return b.String()
+}
+
+// qrSpecialChars are the characters the Wi-Fi QR payload format requires
+// backslash-escaped, since they're used as field delimiters.
+const qrSpecialChars = `\;,":`
+
+// escapeQRField backslash-escapes field-delimiter characters in v.
+func escapeQRField(v string) string {
+	var b strings.Builder
+
+	for _, r := range v {
+		if strings.ContainsRune(qrSpecialChars, r) {
+			b.WriteByte('\\')
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+func canAccess(sec *wifi.Secret, userID string, perm acl.Permission) bool {
+	if sec.UserID == userID {
+		return true
+	}
+
+	return sec.ACL.Allows(acl.UserPrincipal(userID), perm)
+}
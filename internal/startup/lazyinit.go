@@ -0,0 +1,45 @@
+package startup
+
+import (
+	"context"
+	"sync"
+)
+
+// LazyInit runs an initializer in the background with retry, so a
+// dependency that isn't reachable yet at process start (MinIO not up yet
+// in a compose/k8s rollout) doesn't block or crash server startup. Ready
+// reports whether it has succeeded yet; callers surface that through
+// readiness checks instead of through a hard failure at boot.
+type LazyInit struct {
+	mu    sync.RWMutex
+	ready bool
+	err   error
+}
+
+// NewLazyInit starts fn in the background, retrying per cfg until it
+// succeeds or ctx is canceled, and returns immediately with a LazyInit
+// callers can poll via Ready.
+func NewLazyInit(ctx context.Context, cfg RetryConfig, fn func(ctx context.Context) error) *LazyInit {
+	l := &LazyInit{}
+
+	go func() {
+		err := Probe(ctx, cfg, fn)
+
+		l.mu.Lock()
+		l.ready = err == nil
+		l.err = err
+		l.mu.Unlock()
+	}()
+
+	return l
+}
+
+// Ready reports whether the initializer has succeeded yet, and the error
+// Probe gave up with if it failed. While attempts are still running it
+// returns false and a nil error.
+func (l *LazyInit) Ready() (bool, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.ready, l.err
+}
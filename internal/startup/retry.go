@@ -0,0 +1,65 @@
+// Package startup provides retry/backoff helpers for probing dependencies
+// at boot, so a server can come up even if one of them (MinIO, Postgres)
+// isn't ready yet instead of crashing outright.
+package startup
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RetryConfig controls Probe's backoff between attempts.
+type RetryConfig struct {
+	MaxAttempts  int
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	Multiplier   float64
+}
+
+// DefaultRetryConfig is a reasonable startup probing schedule: a handful of
+// attempts with exponential backoff capped at a few seconds, finishing well
+// within a typical container orchestrator's readiness deadline.
+var DefaultRetryConfig = RetryConfig{
+	MaxAttempts:  5,
+	InitialDelay: 200 * time.Millisecond,
+	MaxDelay:     5 * time.Second,
+	Multiplier:   2,
+}
+
+// Probe calls fn until it succeeds, up to cfg.MaxAttempts times (without
+// limit when MaxAttempts <= 0), backing off between failures. It returns the
+// last error if every attempt fails, or ctx's error if canceled while waiting
+// between attempts.
+func Probe(ctx context.Context, cfg RetryConfig, fn func(ctx context.Context) error) error {
+	delay := cfg.InitialDelay
+
+	var err error
+
+	for attempt := 1; cfg.MaxAttempts <= 0 || attempt <= cfg.MaxAttempts; attempt++ {
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+
+		if attempt == cfg.MaxAttempts {
+			break
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		delay = time.Duration(float64(delay) * cfg.Multiplier)
+		if delay > cfg.MaxDelay {
+			delay = cfg.MaxDelay
+		}
+	}
+
+	return fmt.Errorf("probe failed after %d attempts: %w", cfg.MaxAttempts, err)
+}
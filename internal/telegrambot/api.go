@@ -0,0 +1,91 @@
+package telegrambot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const apiBaseURL = "https://api.telegram.org/bot"
+
+// API is a minimal Telegram Bot API client covering only what this bot
+// needs: polling for updates and sending text replies.
+type API struct {
+	token      string
+	httpClient *http.Client
+}
+
+// NewAPI returns an API client authenticated with token.
+func NewAPI(token string) *API {
+	return &API{token: token, httpClient: http.DefaultClient}
+}
+
+type apiResponse[T any] struct {
+	OK     bool `json:"ok"`
+	Result T    `json:"result"`
+}
+
+type tgMessage struct {
+	Chat struct {
+		ID int64 `json:"id"`
+	} `json:"chat"`
+	Text string `json:"text"`
+}
+
+type tgUpdate struct {
+	UpdateID int        `json:"update_id"`
+	Message  *tgMessage `json:"message"`
+}
+
+// GetUpdates long-polls for updates after offset, waiting up to timeoutSec
+// seconds for new ones.
+func (a *API) GetUpdates(ctx context.Context, offset int, timeoutSec int) ([]tgUpdate, error) {
+	q := url.Values{
+		"offset":  {fmt.Sprint(offset)},
+		"timeout": {fmt.Sprint(timeoutSec)},
+	}
+
+	var resp apiResponse[[]tgUpdate]
+	if err := a.call(ctx, "getUpdates", q, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Result, nil
+}
+
+// SendMessage implements Sender against the real Telegram Bot API.
+func (a *API) SendMessage(ctx context.Context, chatID int64, text string) error {
+	q := url.Values{
+		"chat_id": {fmt.Sprint(chatID)},
+		"text":    {text},
+	}
+
+	return a.call(ctx, "sendMessage", q, &apiResponse[json.RawMessage]{})
+}
+
+func (a *API) call(ctx context.Context, method string, q url.Values, out any) error {
+	reqURL := apiBaseURL + a.token + "/" + method + "?" + q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("call %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("call %s: unexpected status %d", method, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode %s response: %w", method, err)
+	}
+
+	return nil
+}
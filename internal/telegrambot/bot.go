@@ -0,0 +1,106 @@
+// Package telegrambot implements an optional Telegram bot integration that
+// lets a linked user retrieve secrets via authenticated chat commands.
+package telegrambot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/andymarkow/gophkeeper/internal/audit"
+)
+
+// SecretReader is the subset of secret lookup needed to serve /get.
+type SecretReader interface {
+	// GetByName returns the decrypted display value for userID's secret
+	// named name (e.g. a credential's login/password pair rendered as
+	// text), or an error if it doesn't exist or isn't accessible.
+	GetByName(ctx context.Context, userID, name string) (string, error)
+}
+
+// Linker resolves the account behind a chat, and completes new links.
+type Linker interface {
+	CompleteLink(ctx context.Context, chatID int64, code string) (userID string, err error)
+	UserForChat(ctx context.Context, chatID int64) (userID string, err error)
+}
+
+// Sender delivers a text reply to a chat. *API implements this against the
+// real Telegram Bot API.
+type Sender interface {
+	SendMessage(ctx context.Context, chatID int64, text string) error
+}
+
+// Update is the minimal shape of an incoming Telegram update this bot acts
+// on: a text message from a chat.
+type Update struct {
+	ChatID int64
+	Text   string
+}
+
+// Bot handles incoming updates and replies via sender.
+type Bot struct {
+	sender  Sender
+	linker  Linker
+	secrets SecretReader
+	audit   audit.Logger
+}
+
+// NewBot returns a Bot wiring together the account linker, secret reader,
+// and audit trail.
+func NewBot(sender Sender, linker Linker, secrets SecretReader, auditLog audit.Logger) *Bot {
+	return &Bot{sender: sender, linker: linker, secrets: secrets, audit: auditLog}
+}
+
+// HandleUpdate dispatches one incoming update to the matching command.
+func (b *Bot) HandleUpdate(ctx context.Context, upd Update) error {
+	fields := strings.Fields(upd.Text)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	switch fields[0] {
+	case "/link":
+		return b.handleLink(ctx, upd.ChatID, fields[1:])
+	case "/get":
+		return b.handleGet(ctx, upd.ChatID, fields[1:])
+	default:
+		return b.sender.SendMessage(ctx, upd.ChatID, "unknown command, try /link <code> or /get <secret name>")
+	}
+}
+
+func (b *Bot) handleLink(ctx context.Context, chatID int64, args []string) error {
+	if len(args) != 1 {
+		return b.sender.SendMessage(ctx, chatID, "usage: /link <code>")
+	}
+
+	userID, err := b.linker.CompleteLink(ctx, chatID, args[0])
+	if err != nil {
+		return b.sender.SendMessage(ctx, chatID, "link failed: code is invalid or expired")
+	}
+
+	b.audit.Log(ctx, audit.Event{Action: "telegram.link", ActorID: userID, Target: fmt.Sprintf("chat:%d", chatID)})
+
+	return b.sender.SendMessage(ctx, chatID, "account linked")
+}
+
+func (b *Bot) handleGet(ctx context.Context, chatID int64, args []string) error {
+	if len(args) != 1 {
+		return b.sender.SendMessage(ctx, chatID, "usage: /get <secret name>")
+	}
+
+	userID, err := b.linker.UserForChat(ctx, chatID)
+	if err != nil {
+		return b.sender.SendMessage(ctx, chatID, "this chat isn't linked yet, send /link <code>")
+	}
+
+	value, err := b.secrets.GetByName(ctx, userID, args[0])
+	if err != nil {
+		b.audit.Log(ctx, audit.Event{Action: "telegram.get.denied", ActorID: userID, Target: args[0], Detail: err.Error()})
+
+		return b.sender.SendMessage(ctx, chatID, "secret not found")
+	}
+
+	b.audit.Log(ctx, audit.Event{Action: "telegram.get", ActorID: userID, Target: args[0]})
+
+	return b.sender.SendMessage(ctx, chatID, value)
+}
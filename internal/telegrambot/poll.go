@@ -0,0 +1,44 @@
+package telegrambot
+
+import (
+	"context"
+	"log/slog"
+)
+
+// pollTimeoutSec is how long each getUpdates long-poll call waits for new
+// updates before returning empty.
+const pollTimeoutSec = 30
+
+// Run long-polls the Telegram Bot API and dispatches each update to bot
+// until ctx is canceled.
+func Run(ctx context.Context, api *API, bot *Bot, logger *slog.Logger) error {
+	offset := 0
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		updates, err := api.GetUpdates(ctx, offset, pollTimeoutSec)
+		if err != nil {
+			logger.Error("telegram: get updates failed", slog.Any("error", err))
+
+			continue
+		}
+
+		for _, u := range updates {
+			offset = u.UpdateID + 1
+
+			if u.Message == nil {
+				continue
+			}
+
+			upd := Update{ChatID: u.Message.Chat.ID, Text: u.Message.Text}
+			if err := bot.HandleUpdate(ctx, upd); err != nil {
+				logger.Error("telegram: handle update failed", slog.Any("error", err), slog.Int64("chat_id", upd.ChatID))
+			}
+		}
+	}
+}
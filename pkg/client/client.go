@@ -0,0 +1,180 @@
+// Package client is the public Go SDK for the gophkeeper API.
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Interceptor observes or modifies a request before it is sent, and/or the
+// response after it is received. Implementations commonly log, collect
+// metrics, or inject headers.
+type Interceptor interface {
+	// Before runs before the request is sent. It may return a modified
+	// request.
+	Before(req *http.Request) (*http.Request, error)
+	// After runs once a response (or error) is available.
+	After(resp *http.Response, err error)
+}
+
+// RetryPolicy configures automatic retries for idempotent requests.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy makes up to 3 attempts in all at GET/HEAD/OPTIONS
+// requests, with exponential backoff and jitter.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 3, BaseDelay: 200 * time.Millisecond, MaxDelay: 3 * time.Second}
+}
+
+// Client is the gophkeeper API SDK client.
+type Client struct {
+	baseURL      string
+	httpClient   *http.Client
+	interceptors []Interceptor
+	retry        RetryPolicy
+	token        string
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient overrides the underlying *http.Client.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithInterceptor registers an Interceptor, run in registration order.
+func WithInterceptor(i Interceptor) Option {
+	return func(c *Client) { c.interceptors = append(c.interceptors, i) }
+}
+
+// WithRetryPolicy overrides the retry policy for idempotent requests.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) { c.retry = p }
+}
+
+// WithToken sets the bearer token sent with every request.
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// New returns a Client targeting baseURL.
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    baseURL,
+		httpClient: http.DefaultClient,
+		retry:      DefaultRetryPolicy(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// isIdempotent reports whether method is safe to retry automatically.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// Do sends req through the interceptor chain and the configured retry
+// policy, honoring a Retry-After header from the server's rate limiter.
+func (c *Client) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	req = req.WithContext(ctx)
+
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	for _, i := range c.interceptors {
+		var err error
+
+		req, err = i.Before(req)
+		if err != nil {
+			return nil, fmt.Errorf("interceptor before hook: %w", err)
+		}
+	}
+
+	maxAttempts := 1
+	if isIdempotent(req.Method) {
+		maxAttempts = max(c.retry.MaxAttempts, 1)
+	}
+
+	var (
+		resp *http.Response
+		err  error
+	)
+
+	delay := c.retry.BaseDelay
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		resp, err = c.httpClient.Do(req)
+
+		for _, i := range c.interceptors {
+			i.After(resp, err)
+		}
+
+		if err == nil && resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+			return resp, nil
+		}
+
+		if attempt == maxAttempts {
+			break
+		}
+
+		wait := retryAfter(resp)
+		if wait == 0 {
+			wait = jitter(delay)
+			delay *= 2
+			if c.retry.MaxDelay > 0 && delay > c.retry.MaxDelay {
+				delay = c.retry.MaxDelay
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+
+	return resp, err
+}
+
+// retryAfter parses the server's Retry-After header (seconds), returning 0
+// if absent or invalid.
+func retryAfter(resp *http.Response) time.Duration {
+	if resp == nil {
+		return 0
+	}
+
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+// jitter returns a random duration in [d/2, d] to avoid retry stampedes.
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+
+	return d/2 + time.Duration(rand.Int64N(int64(d)/2+1))
+}
@@ -0,0 +1,214 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"time"
+)
+
+// Login authenticates against POST /api/v1/auth/login and, on success,
+// stores the returned token on c so subsequent calls are authenticated
+// without a separate WithToken option.
+func (c *Client) Login(ctx context.Context, login, password string) error {
+	body, err := json.Marshal(struct {
+		Login    string `json:"login"`
+		Password string `json:"password"`
+	}{Login: login, Password: password})
+	if err != nil {
+		return fmt.Errorf("marshal login request: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.baseURL+"/api/v1/auth/login", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build login request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	var resp struct {
+		Token string `json:"token"`
+	}
+	if err := c.doJSON(ctx, req, &resp); err != nil {
+		return fmt.Errorf("login: %w", err)
+	}
+
+	c.token = resp.Token
+
+	return nil
+}
+
+// BankCardSecret is a bank card secret as returned by GetCardSecret and
+// RevealCardSecret. Number and CVV are masked unless retrieved via
+// RevealCardSecret.
+type BankCardSecret struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Number     string    `json:"number,omitempty"`
+	Holder     string    `json:"holder,omitempty"`
+	ExpiryDate string    `json:"expiry_date,omitempty"`
+	CVV        string    `json:"cvv,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// GetCardSecret fetches a bank card secret with Number and CVV masked (see
+// GET /api/v1/secrets/bankcards/{id}). There is no CreateCardSecret: the
+// server has no bank card creation endpoint, only masked-get, reveal and
+// CSV export.
+func (c *Client) GetCardSecret(ctx context.Context, id string) (*BankCardSecret, error) {
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+"/api/v1/secrets/bankcards/"+id, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+
+	var sec BankCardSecret
+	if err := c.doJSON(ctx, req, &sec); err != nil {
+		return nil, fmt.Errorf("get card secret: %w", err)
+	}
+
+	return &sec, nil
+}
+
+// RevealCardSecret fetches a bank card secret with every field decrypted in
+// full (see POST /api/v1/secrets/bankcards/{id}/reveal). The server rate
+// limits and audit-logs every call regardless of outcome.
+func (c *Client) RevealCardSecret(ctx context.Context, id string) (*BankCardSecret, error) {
+	req, err := http.NewRequest(http.MethodPost, c.baseURL+"/api/v1/secrets/bankcards/"+id+"/reveal", nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+
+	var sec BankCardSecret
+	if err := c.doJSON(ctx, req, &sec); err != nil {
+		return nil, fmt.Errorf("reveal card secret: %w", err)
+	}
+
+	return &sec, nil
+}
+
+// FileSecret describes a file secret's metadata, as returned by UploadFile.
+type FileSecret struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// UploadFile uploads r's content as name via POST /api/v1/files, sending it
+// as the multipart/form-data "file" field the server expects. contentType
+// is sent on the part itself, which is where FileHandler.Upload reads it
+// from; an empty contentType leaves it to the server's own sniffing.
+func (c *Client) UploadFile(ctx context.Context, name, contentType string, r io.Reader) (*FileSecret, error) {
+	var body bytes.Buffer
+
+	mw := multipart.NewWriter(&body)
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename=%q`, name))
+
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+
+	fw, err := mw.CreatePart(header)
+	if err != nil {
+		return nil, fmt.Errorf("create form file part: %w", err)
+	}
+
+	if _, err := io.Copy(fw, r); err != nil {
+		return nil, fmt.Errorf("copy file content: %w", err)
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, fmt.Errorf("close multipart writer: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.baseURL+"/api/v1/files", &body)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	var sec FileSecret
+	if err := c.doJSON(ctx, req, &sec); err != nil {
+		return nil, fmt.Errorf("upload file: %w", err)
+	}
+
+	return &sec, nil
+}
+
+// DownloadFile streams a file secret's decrypted content from
+// GET /api/v1/files/{id}. The caller must close the returned ReadCloser.
+func (c *Client) DownloadFile(ctx context.Context, id string) (io.ReadCloser, error) {
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+"/api/v1/files/"+id, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+
+	resp, err := c.Do(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("download file: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+
+		return nil, newAPIError(resp)
+	}
+
+	return resp.Body, nil
+}
+
+// doJSON sends req and decodes a JSON response body into out, returning an
+// *APIError for any non-2xx response.
+func (c *Client) doJSON(ctx context.Context, req *http.Request, out any) error {
+	resp, err := c.Do(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return newAPIError(resp)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+
+	return nil
+}
+
+// APIError wraps a non-2xx response from the server, preserving its status
+// code and the httperr.Response body the server already encodes it as.
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("gophkeeper: %s (status %d, code %s)", e.Message, e.StatusCode, e.Code)
+}
+
+func newAPIError(resp *http.Response) error {
+	var body struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+
+	_ = json.NewDecoder(resp.Body).Decode(&body)
+
+	return &APIError{StatusCode: resp.StatusCode, Code: body.Code, Message: body.Message}
+}